@@ -0,0 +1,212 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PushSecretStoreRef defines which SecretStore the PushSecret writes to.
+type PushSecretStoreRef struct {
+	// Name of the SecretStore resource
+	Name string `json:"name"`
+
+	// Kind of the SecretStore resource (SecretStore or ClusterSecretStore)
+	// Defaults to `SecretStore`
+	// +optional
+	// +kubebuilder:default="SecretStore"
+	Kind string `json:"kind,omitempty"`
+}
+
+// PushSecretDeletionPolicy defines what happens to the provider secrets
+// once they are no longer pushed by the PushSecret.
+// +kubebuilder:validation:Enum=Delete;None
+type PushSecretDeletionPolicy string
+
+const (
+	// PushSecretDeletionPolicyDelete deletes the provider secrets when the PushSecret
+	// is deleted or when a key is removed from .spec.data.
+	PushSecretDeletionPolicyDelete PushSecretDeletionPolicy = "Delete"
+
+	// PushSecretDeletionPolicyNone keeps the provider secrets as they are.
+	PushSecretDeletionPolicyNone PushSecretDeletionPolicy = "None"
+)
+
+// PushSecretSecret selects the Kubernetes Secret to push.
+type PushSecretSecret struct {
+	// Name of the Secret. The Secret must exist in the same namespace as the PushSecret.
+	Name string `json:"name"`
+}
+
+// PushSecretSelector defines the source of the data that is pushed.
+type PushSecretSelector struct {
+	Secret PushSecretSecret `json:"secret"`
+}
+
+// PushSecretRemoteRef defines the location of the secret in the provider.
+type PushSecretRemoteRef struct {
+	// Name of the resulting provider secret.
+	RemoteKey string `json:"remoteKey"`
+
+	// +optional
+	// Name of the property in the resulting provider secret.
+	// If empty, the value replaces the whole provider secret.
+	Property string `json:"property,omitempty"`
+}
+
+func (r PushSecretRemoteRef) GetRemoteKey() string {
+	return r.RemoteKey
+}
+
+func (r PushSecretRemoteRef) GetProperty() string {
+	return r.Property
+}
+
+// PushSecretMatch maps a key of the Secret to a location in the provider.
+type PushSecretMatch struct {
+	// Secret Key to be pushed
+	SecretKey string `json:"secretKey"`
+
+	// Remote Refs to push to providers.
+	RemoteRef PushSecretRemoteRef `json:"remoteRef"`
+}
+
+// PushSecretData defines a single key to push.
+type PushSecretData struct {
+	// Match a given Secret Key to be pushed to the provider.
+	Match PushSecretMatch `json:"match"`
+}
+
+// PushSecretSpec configures the behavior of the PushSecret.
+type PushSecretSpec struct {
+	// The Interval to which External Secrets will try to push a secret definition
+	// +kubebuilder:default="1h"
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// SecretStoreRefs lists the stores the data is pushed to.
+	// +kubebuilder:validation:MinItems=1
+	SecretStoreRefs []PushSecretStoreRef `json:"secretStoreRefs"`
+
+	// DeletionPolicy defines what happens to the provider secrets
+	// once they are not pushed anymore. Defaults to 'None'
+	// +optional
+	// +kubebuilder:default="None"
+	DeletionPolicy PushSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// The Secret Selector (k8s source) for the Push Secret
+	Selector PushSecretSelector `json:"selector"`
+
+	// Secret Data that should be pushed to providers
+	// +optional
+	Data []PushSecretData `json:"data,omitempty"`
+}
+
+type PushSecretConditionType string
+
+const (
+	PushSecretReady PushSecretConditionType = "Ready"
+)
+
+// PushSecretStatusCondition indicates the status of the PushSecret.
+type PushSecretStatusCondition struct {
+	Type   PushSecretConditionType `json:"type"`
+	Status corev1.ConditionStatus  `json:"status"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+const (
+	// ReasonSynced indicates that all keys were pushed.
+	ReasonSynced = "Synced"
+	// ReasonErrored indicates that at least one key could not be pushed.
+	ReasonErrored = "Errored"
+	// ReasonStoreNotAllowed indicates that the conditions of a
+	// ClusterSecretStore do not allow the namespace of the PushSecret.
+	ReasonStoreNotAllowed = "StoreNotAllowed"
+)
+
+type PushSecretSyncStatus string
+
+const (
+	PushSecretSyncStatusSynced PushSecretSyncStatus = "Synced"
+	PushSecretSyncStatusError  PushSecretSyncStatus = "Error"
+)
+
+// PushSecretSyncedData reports the result of pushing a single key to a single store.
+type PushSecretSyncedData struct {
+	StoreRef  PushSecretStoreRef   `json:"storeRef"`
+	SecretKey string               `json:"secretKey"`
+	RemoteRef PushSecretRemoteRef  `json:"remoteRef"`
+	Status    PushSecretSyncStatus `json:"status"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Pushed is true once the key has been written to the store. It stays true
+	// if a later push fails, so the key is deleted with deletionPolicy=Delete.
+	// +optional
+	Pushed bool `json:"pushed,omitempty"`
+}
+
+// PushSecretStatus indicates the history of the status of PushSecret.
+type PushSecretStatus struct {
+	// +nullable
+	// refreshTime is the time and date the external secret was fetched and
+	// the target secret updated
+	RefreshTime metav1.Time `json:"refreshTime,omitempty"`
+
+	// SyncedResourceVersion keeps track of the last synced version.
+	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
+
+	// SyncedPushSecrets reports the result of the last push per store and key.
+	// +optional
+	SyncedPushSecrets []PushSecretSyncedData `json:"syncedPushSecrets,omitempty"`
+
+	// +optional
+	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories={pushsecrets}
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+
+// PushSecret pushes the data of a Kubernetes Secret to one or more SecretStores.
+type PushSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PushSecretSpec   `json:"spec,omitempty"`
+	Status PushSecretStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PushSecretList contains a list of PushSecret resources.
+type PushSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PushSecret `json:"items"`
+}
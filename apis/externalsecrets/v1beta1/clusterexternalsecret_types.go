@@ -16,14 +16,23 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ClusterExternalSecretSpec defines the desired state of ClusterExternalSecret.
 type ClusterExternalSecretSpec struct {
-	// The spec for the ExternalSecrets to be created
+	// The spec for the ExternalSecrets to be created.
+	// String values may contain Go templates which are rendered for every namespace
+	// with .Namespace, .Labels and .Annotations of the namespace, except for target.template
+	// and dataFrom[].rewrite[].transform.template.
 	ExternalSecretSpec ExternalSecretSpec `json:"externalSecretSpec"`
 
+	// Overrides patch the externalSecretSpec for the namespaces matching their
+	// namespaceSelector. They are applied in order, before the templates are rendered.
+	// +optional
+	Overrides []ClusterExternalSecretOverride `json:"overrides,omitempty"`
+
 	// The name of the external secrets to be created defaults to the name of the ClusterExternalSecret
 	// +optional
 	ExternalSecretName string `json:"externalSecretName"`
@@ -35,6 +44,18 @@ type ClusterExternalSecretSpec struct {
 	RefreshInterval *metav1.Duration `json:"refreshTime,omitempty"`
 }
 
+// ClusterExternalSecretOverride patches the externalSecretSpec of a ClusterExternalSecret
+// for the namespaces matching its namespaceSelector.
+type ClusterExternalSecretOverride struct {
+	// The labels to select by to find the Namespaces the override applies to.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// ExternalSecretSpec is merged into the externalSecretSpec as a JSON merge patch (RFC 7386):
+	// objects are merged, lists and other values are replaced and null removes a field.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExternalSecretSpec apiextensions.JSON `json:"externalSecretSpec"`
+}
+
 type ClusterExternalSecretConditionType string
 
 const (
@@ -72,6 +72,61 @@ const (
 	DeletionPolicyRetain ExternalSecretDeletionPolicy = "Retain"
 )
 
+// ExternalSecretRefreshPolicy defines when the target is refreshed from the provider.
+// +kubebuilder:validation:Enum=CreatedOnce;Periodic;OnChange
+type ExternalSecretRefreshPolicy string
+
+const (
+	// CreatedOnce creates the target if it does not exist
+	// and never updates it afterwards.
+	RefreshPolicyCreatedOnce ExternalSecretRefreshPolicy = "CreatedOnce"
+
+	// Periodic refreshes the target every refreshInterval.
+	RefreshPolicyPeriodic ExternalSecretRefreshPolicy = "Periodic"
+
+	// OnChange refreshes the target only when the spec,
+	// labels or annotations of the ExternalSecret change.
+	RefreshPolicyOnChange ExternalSecretRefreshPolicy = "OnChange"
+)
+
+// ExternalSecretTargetKind defines the kind of the resource which is managed.
+// +kubebuilder:validation:Enum=Secret;ConfigMap
+type ExternalSecretTargetKind string
+
+const (
+	// TargetKindSecret writes the values to a Secret.
+	TargetKindSecret ExternalSecretTargetKind = "Secret"
+
+	// TargetKindConfigMap writes the values to a ConfigMap.
+	// Values which are not valid UTF-8 are written to binaryData.
+	TargetKindConfigMap ExternalSecretTargetKind = "ConfigMap"
+)
+
+// RolloutTargetKind defines the kind of a workload which is restarted.
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+type RolloutTargetKind string
+
+const (
+	RolloutTargetKindDeployment  RolloutTargetKind = "Deployment"
+	RolloutTargetKindStatefulSet RolloutTargetKind = "StatefulSet"
+	RolloutTargetKindDaemonSet   RolloutTargetKind = "DaemonSet"
+)
+
+// RolloutTarget references workloads in the namespace of the ExternalSecret
+// which are restarted when the data of the target changes.
+// Exactly one of name or selector must be set.
+type RolloutTarget struct {
+	Kind RolloutTargetKind `json:"kind"`
+
+	// Name of the workload.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector selects all workloads of the given kind with matching labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
 // ExternalSecretTemplateMetadata defines metadata fields for the Secret blueprint.
 type ExternalSecretTemplateMetadata struct {
 	// +optional
@@ -135,6 +190,12 @@ type ExternalSecretTarget struct {
 	// +optional
 	Name string `json:"name,omitempty"`
 
+	// Kind defines the kind of the resource to be managed, either Secret or ConfigMap.
+	// Defaults to 'Secret'
+	// +optional
+	// +kubebuilder:default="Secret"
+	Kind ExternalSecretTargetKind `json:"kind,omitempty"`
+
 	// CreationPolicy defines rules on how to create the resulting Secret
 	// Defaults to 'Owner'
 	// +optional
@@ -152,6 +213,11 @@ type ExternalSecretTarget struct {
 	// Immutable defines if the final secret will be immutable
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
+
+	// RolloutTargets defines workloads which are restarted
+	// whenever the data of the target changes.
+	// +optional
+	RolloutTargets []RolloutTarget `json:"rolloutTargets,omitempty"`
 }
 
 // ExternalSecretData defines the connection between the Kubernetes Secret key (spec.data.<key>) and the Provider data.
@@ -159,6 +225,19 @@ type ExternalSecretData struct {
 	SecretKey string `json:"secretKey"`
 
 	RemoteRef ExternalSecretDataRemoteRef `json:"remoteRef"`
+
+	// SourceRef allows you to fetch this entry from a different store
+	// than the one referenced in spec.secretStoreRef.
+	// +optional
+	SourceRef *StoreSourceRef `json:"sourceRef,omitempty"`
+}
+
+// StoreSourceRef allows you to override the store
+// from which the values of a data entry are pulled from.
+type StoreSourceRef struct {
+	// StoreRef points to the SecretStore or ClusterSecretStore
+	// which is used instead of spec.secretStoreRef.
+	StoreRef SecretStoreRef `json:"storeRef"`
 }
 
 // ExternalSecretDataRemoteRef defines Provider data location.
@@ -213,7 +292,6 @@ const (
 )
 
 // +kubebuilder:validation:MinProperties=1
-// +kubebuilder:validation:MaxProperties=1
 type ExternalSecretDataFromRemoteRef struct {
 	// Used to extract multiple key/value pairs from one secret
 	// +optional
@@ -221,6 +299,70 @@ type ExternalSecretDataFromRemoteRef struct {
 	// Used to find secrets based on tags or regular expressions
 	// +optional
 	Find *ExternalSecretFind `json:"find,omitempty"`
+
+	// SourceRef either points to a different store than spec.secretStoreRef
+	// which is used together with extract or find, or to a generator
+	// which creates the values instead of fetching them from the secret Provider.
+	// Generated values are kept until the ExternalSecret spec changes
+	// or a rotation is requested.
+	// +optional
+	SourceRef *SourceRef `json:"sourceRef,omitempty"`
+
+	// Used to rewrite secret Keys after getting them from the secret Provider.
+	// Multiple Rewrite operations can be provided. They are applied in a layered order (first to last).
+	// +optional
+	Rewrite []ExternalSecretRewrite `json:"rewrite,omitempty"`
+}
+
+// SourceRef allows you to override the source
+// from which the values are pulled from.
+type SourceRef struct {
+	// StoreRef points to the SecretStore or ClusterSecretStore
+	// which is used instead of spec.secretStoreRef.
+	// +optional
+	StoreRef *SecretStoreRef `json:"storeRef,omitempty"`
+
+	// GeneratorRef points to a generator custom resource.
+	// +optional
+	GeneratorRef *GeneratorRef `json:"generatorRef,omitempty"`
+}
+
+// GeneratorRef points to a generator custom resource.
+type GeneratorRef struct {
+	// Specify the apiVersion of the generator resource
+	// +kubebuilder:default="generators.external-secrets.io/v1alpha1"
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Specify the Kind of the resource, e.g. Password
+	Kind string `json:"kind"`
+
+	// Specify the name of the generator resource
+	Name string `json:"name"`
+}
+
+type ExternalSecretRewrite struct {
+	// Used to rewrite with regular expressions.
+	// The resulting key will be the output of a regexp.ReplaceAll operation.
+	// +optional
+	Regexp *ExternalSecretRewriteRegexp `json:"regexp,omitempty"`
+
+	// Used to apply a Go template on the secret keys.
+	// The resulting key will be the output of the template.
+	// +optional
+	Transform *ExternalSecretRewriteTransform `json:"transform,omitempty"`
+}
+
+type ExternalSecretRewriteRegexp struct {
+	// Used to define the regular expression of a re.Compiler.
+	Source string `json:"source"`
+	// Used to define the target pattern of a ReplaceAll operation.
+	Target string `json:"target"`
+}
+
+type ExternalSecretRewriteTransform struct {
+	// Used to define the template to apply on the secret key.
+	// `.value` holds the secret key in the template.
+	Template string `json:"template"`
 }
 
 type ExternalSecretFind struct {
@@ -258,6 +400,14 @@ type ExternalSecretSpec struct {
 	// +optional
 	Target ExternalSecretTarget `json:"target,omitempty"`
 
+	// RefreshPolicy determines when the target is refreshed from the provider:
+	// CreatedOnce creates the target once and never updates it,
+	// Periodic refreshes it every refreshInterval and
+	// OnChange refreshes it only when the ExternalSecret changes.
+	// Defaults to 'Periodic'
+	// +optional
+	RefreshPolicy ExternalSecretRefreshPolicy `json:"refreshPolicy,omitempty"`
+
 	// RefreshInterval is the amount of time before the values are read again from the SecretStore provider
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h"
 	// May be set to zero to fetch and create it once. Defaults to 1h.
@@ -302,6 +452,25 @@ const (
 	ConditionReasonSecretSyncedError = "SecretSyncedError"
 	// ConditionReasonSecretDeleted indicates that the secret has been deleted.
 	ConditionReasonSecretDeleted = "SecretDeleted"
+	// ConditionReasonSecretKeyCollision indicates that different provider keys
+	// were rewritten to the same secret key.
+	ConditionReasonSecretKeyCollision = "SecretKeyCollision"
+	// ConditionReasonAuthError indicates that the provider rejected the credentials of the store.
+	ConditionReasonAuthError = "AuthError"
+	// ConditionReasonPermissionDenied indicates that the store is not allowed to access a secret.
+	ConditionReasonPermissionDenied = "PermissionDenied"
+	// ConditionReasonThrottled indicates that the provider rejected requests because of a rate limit.
+	ConditionReasonThrottled = "Throttled"
+	// ConditionReasonTransientError indicates a temporary failure of the provider.
+	ConditionReasonTransientError = "TransientError"
+	// ConditionReasonInvalidRef indicates that a remote reference can not be used.
+	ConditionReasonInvalidRef = "InvalidRef"
+	// ConditionReasonStoreNotAllowed indicates that the conditions of a
+	// ClusterSecretStore do not allow its use in the namespace of the ExternalSecret.
+	ConditionReasonStoreNotAllowed = "StoreNotAllowed"
+	// ConditionReasonForbidden indicates that the access rules of a
+	// ClusterSecretStore do not allow the namespace to read a remote key.
+	ConditionReasonForbidden = "Forbidden"
 
 	ReasonInvalidStoreRef      = "InvalidStoreRef"
 	ReasonUnavailableStore     = "UnavailableStore"
@@ -309,6 +478,9 @@ const (
 	ReasonUpdateFailed         = "UpdateFailed"
 	ReasonUpdated              = "Updated"
 	ReasonDeleted              = "Deleted"
+	ReasonRolloutTriggered     = "RolloutTriggered"
+	ReasonRolloutFailed        = "RolloutFailed"
+	ReasonForbidden            = "Forbidden"
 )
 
 type ExternalSecretStatus struct {
@@ -320,10 +492,50 @@ type ExternalSecretStatus struct {
 	// SyncedResourceVersion keeps track of the last synced version
 	SyncedResourceVersion string `json:"syncedResourceVersion,omitempty"`
 
+	// RefreshPolicy is the refresh policy applied by the controller
+	// +optional
+	RefreshPolicy ExternalSecretRefreshPolicy `json:"refreshPolicy,omitempty"`
+
+	// ForceSync is the value of the force-sync annotation
+	// which was handled by the last successful refresh
+	// +optional
+	ForceSync string `json:"forceSync,omitempty"`
+
+	// SyncedKeys describes where every key of the target data has been
+	// synced from by the last successful refresh, sorted by key.
+	// +optional
+	SyncedKeys []SyncedKey `json:"syncedKeys,omitempty"`
+
 	// +optional
 	Conditions []ExternalSecretStatusCondition `json:"conditions,omitempty"`
 }
 
+// SyncedKey describes where a key has been synced from.
+type SyncedKey struct {
+	// Key is the key of the target data.
+	Key string `json:"key"`
+
+	// Source is the entry of the spec the value has been fetched with,
+	// e.g. spec.data[0] or spec.dataFrom[1], or spec.target.template
+	// if the value has been rendered by the template.
+	Source string `json:"source"`
+
+	// RemoteKey is the key of the secret at the provider,
+	// it is not set for find, generator and template keys.
+	// +optional
+	RemoteKey string `json:"remoteKey,omitempty"`
+
+	// Version is the version of the secret at the provider which has been
+	// synced, e.g. the VersionId of an AWS secret. It is only set if the
+	// provider reports versions and the key has been fetched from a single secret.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Hash is the hex encoded SHA-256 hash of the value, salted with the
+	// UID of the ExternalSecret. It changes whenever the value changes.
+	Hash string `json:"hash"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // ExternalSecret is the Schema for the external-secrets API.
@@ -344,6 +556,23 @@ type ExternalSecret struct {
 const (
 	// AnnotationDataHash is used to ensure consistency.
 	AnnotationDataHash = "reconcile.external-secrets.io/data-hash"
+
+	// AnnotationTemplateHash is used to detect changes of the templateFrom sources.
+	AnnotationTemplateHash = "reconcile.external-secrets.io/template-hash"
+
+	// AnnotationGeneratorState is used to detect if the generated values must be rotated.
+	AnnotationGeneratorState = "reconcile.external-secrets.io/generator-state"
+
+	// AnnotationGeneratorExpiry holds the earliest expiry of the generated values.
+	AnnotationGeneratorExpiry = "reconcile.external-secrets.io/generator-expiry"
+
+	// AnnotationRotateGenerators can be set on an ExternalSecret to request new
+	// values from its generators. Any change of the value triggers a rotation.
+	AnnotationRotateGenerators = "generators.external-secrets.io/rotate"
+
+	// AnnotationForceSync can be set on an ExternalSecret to request an immediate
+	// refresh regardless of the refresh policy. Any change of the value triggers a refresh.
+	AnnotationForceSync = "force-sync"
 )
 
 // +kubebuilder:object:root=true
@@ -16,18 +16,45 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-type ExternalSecretValidator struct{}
+// +kubebuilder:object:generate=false
+
+// ExternalSecretValidator validates ExternalSecrets on admission.
+type ExternalSecretValidator struct {
+	// Reader is used to look up the store referenced by the ExternalSecret.
+	// Checks which depend on the store are skipped if it is nil.
+	Reader client.Reader
+
+	// getProvider returns the provider of a store, GetProvider if nil.
+	getProvider func(GenericStore) (Provider, error)
+}
 
 func (esv *ExternalSecretValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
-	return validateExternalSecret(obj)
+	if err := validateExternalSecret(obj); err != nil {
+		return err
+	}
+	if err := esv.validateStoreConditions(ctx, obj); err != nil {
+		return err
+	}
+	return esv.validateMetadataPolicy(ctx, obj)
 }
 
 func (esv *ExternalSecretValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
-	return validateExternalSecret(newObj)
+	if err := validateExternalSecret(newObj); err != nil {
+		return err
+	}
+	if err := esv.validateStoreConditions(ctx, newObj); err != nil {
+		return err
+	}
+	return esv.validateMetadataPolicy(ctx, newObj)
 }
 
 func (esv *ExternalSecretValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
@@ -48,5 +75,225 @@ func validateExternalSecret(obj runtime.Object) error {
 	if es.Spec.Target.DeletionPolicy == DeletionPolicyMerge && es.Spec.Target.CreationPolicy == CreatePolicyNone {
 		return fmt.Errorf("deletionPolicy=Merge must not be used with creationPolcy=None. There is no Secret to merge with")
 	}
+
+	if es.Spec.Target.Kind == TargetKindConfigMap && es.Spec.Target.Template != nil && es.Spec.Target.Template.Type != "" {
+		return fmt.Errorf("template.type must not be used with target kind ConfigMap")
+	}
+
+	for i, target := range es.Spec.Target.RolloutTargets {
+		if err := validateRolloutTarget(target); err != nil {
+			return fmt.Errorf("invalid spec.target.rolloutTargets[%d]: %w", i, err)
+		}
+	}
+
+	for i, ref := range es.Spec.DataFrom {
+		if err := validateDataFromSource(ref); err != nil {
+			return fmt.Errorf("invalid spec.dataFrom[%d]: %w", i, err)
+		}
+		if err := validateRewrite(ref.Rewrite); err != nil {
+			return fmt.Errorf("invalid spec.dataFrom[%d].rewrite: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateDataFromSource ensures that a dataFrom entry has exactly one source.
+// A sourceRef.storeRef only overrides the store used by extract or find.
+func validateDataFromSource(ref ExternalSecretDataFromRemoteRef) error {
+	var sources int
+	if ref.Extract != nil {
+		sources++
+	}
+	if ref.Find != nil {
+		sources++
+	}
+	if ref.SourceRef != nil {
+		if (ref.SourceRef.StoreRef == nil) == (ref.SourceRef.GeneratorRef == nil) {
+			return fmt.Errorf("exactly one of sourceRef.storeRef or sourceRef.generatorRef must be set")
+		}
+		if ref.SourceRef.GeneratorRef != nil {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("exactly one of extract, find or sourceRef.generatorRef must be set")
+	}
 	return nil
 }
+
+// validateRolloutTarget ensures that a rollout target selects workloads
+// either by name or by a valid label selector.
+func validateRolloutTarget(target RolloutTarget) error {
+	if (target.Name == "") == (target.Selector == nil) {
+		return fmt.Errorf("exactly one of name or selector must be set")
+	}
+	if target.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(target.Selector); err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateRewrite(operations []ExternalSecretRewrite) error {
+	for i, op := range operations {
+		if (op.Regexp == nil) == (op.Transform == nil) {
+			return fmt.Errorf("exactly one of regexp or transform must be set in rewrite[%d]", i)
+		}
+		if op.Regexp != nil {
+			if _, err := regexp.Compile(op.Regexp.Source); err != nil {
+				return fmt.Errorf("invalid regexp in rewrite[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateStoreConditions rejects references to a ClusterSecretStore whose
+// conditions do not allow its use in the namespace of the ExternalSecret.
+// The check is skipped if the store or the namespace can not be read,
+// the controller enforces the conditions as well.
+func (esv *ExternalSecretValidator) validateStoreConditions(ctx context.Context, obj runtime.Object) error {
+	es, ok := obj.(*ExternalSecret)
+	if !ok || esv.Reader == nil {
+		return nil
+	}
+	var namespace *corev1.Namespace
+	for _, name := range clusterStoreNames(es) {
+		var store ClusterSecretStore
+		if err := esv.Reader.Get(ctx, types.NamespacedName{Name: name}, &store); err != nil {
+			continue
+		}
+		if len(store.Spec.Conditions) == 0 {
+			continue
+		}
+		if namespace == nil {
+			namespace = &corev1.Namespace{}
+			if err := esv.Reader.Get(ctx, types.NamespacedName{Name: es.Namespace}, namespace); err != nil {
+				return nil
+			}
+		}
+		if err := ValidateNamespace(&store, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterStoreNames returns the names of all ClusterSecretStores
+// the ExternalSecret fetches from. spec.secretStoreRef is only
+// included if an entry without its own store uses it.
+func clusterStoreNames(es *ExternalSecret) []string {
+	var refs []SecretStoreRef
+	for _, data := range es.Spec.Data {
+		if data.SourceRef != nil {
+			refs = append(refs, data.SourceRef.StoreRef)
+		} else {
+			refs = append(refs, es.Spec.SecretStoreRef)
+		}
+	}
+	for _, ref := range es.Spec.DataFrom {
+		switch {
+		case ref.SourceRef != nil && ref.SourceRef.StoreRef != nil:
+			refs = append(refs, *ref.SourceRef.StoreRef)
+		case ref.SourceRef == nil || ref.SourceRef.GeneratorRef == nil:
+			refs = append(refs, es.Spec.SecretStoreRef)
+		}
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, ref := range refs {
+		if ref.Kind == ClusterSecretStoreKind && !seen[ref.Name] {
+			seen[ref.Name] = true
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// validateMetadataPolicy rejects metadataPolicy=Fetch if the provider of the
+// referenced store is not able to fetch metadata. The check is skipped if the
+// store can not be read, e.g. because it has not been created yet.
+func (esv *ExternalSecretValidator) validateMetadataPolicy(ctx context.Context, obj runtime.Object) error {
+	es, ok := obj.(*ExternalSecret)
+	if !ok || esv.Reader == nil {
+		return nil
+	}
+	supported := make(map[SecretStoreRef]bool)
+	for _, fetch := range metadataFetchRefs(es) {
+		ok, checked := supported[fetch.storeRef]
+		if !checked {
+			ok = esv.supportsMetadataFetch(ctx, es.Namespace, fetch.storeRef)
+			supported[fetch.storeRef] = ok
+		}
+		if !ok {
+			return fmt.Errorf("invalid %s: metadataPolicy=Fetch is not supported by %s %q", fetch.field, fetch.storeRef.Kind, fetch.storeRef.Name)
+		}
+	}
+	return nil
+}
+
+// supportsMetadataFetch returns false only if the referenced store exists
+// and its provider is not able to fetch metadata.
+func (esv *ExternalSecretValidator) supportsMetadataFetch(ctx context.Context, namespace string, storeRef SecretStoreRef) bool {
+	var store GenericStore
+	ref := types.NamespacedName{Name: storeRef.Name}
+	if storeRef.Kind == ClusterSecretStoreKind {
+		store = &ClusterSecretStore{}
+	} else {
+		store = &SecretStore{}
+		ref.Namespace = namespace
+	}
+	if err := esv.Reader.Get(ctx, ref, store); err != nil {
+		return true
+	}
+	getProvider := esv.getProvider
+	if getProvider == nil {
+		getProvider = GetProvider
+	}
+	provider, err := getProvider(store)
+	if err != nil {
+		return true
+	}
+	fetcher, ok := provider.(MetadataFetcher)
+	return ok && fetcher.SupportsMetadataFetch(store)
+}
+
+// metadataFetch is a remoteRef which uses metadataPolicy=Fetch.
+type metadataFetch struct {
+	field    string
+	storeRef SecretStoreRef
+}
+
+// metadataFetchRefs returns the remoteRefs which use metadataPolicy=Fetch
+// along with the store they are fetched from.
+func metadataFetchRefs(es *ExternalSecret) []metadataFetch {
+	defaultRef := es.Spec.SecretStoreRef
+	var refs []metadataFetch
+	for i, data := range es.Spec.Data {
+		if data.RemoteRef.MetadataPolicy != ExternalSecretMetadataPolicyFetch {
+			continue
+		}
+		storeRef := defaultRef
+		if data.SourceRef != nil {
+			storeRef = data.SourceRef.StoreRef
+		}
+		refs = append(refs, metadataFetch{field: fmt.Sprintf("spec.data[%d].remoteRef", i), storeRef: storeRef})
+	}
+	for i, ref := range es.Spec.DataFrom {
+		if ref.Extract == nil || ref.Extract.MetadataPolicy != ExternalSecretMetadataPolicyFetch {
+			continue
+		}
+		storeRef := defaultRef
+		if ref.SourceRef != nil && ref.SourceRef.StoreRef != nil {
+			storeRef = *ref.SourceRef.StoreRef
+		}
+		refs = append(refs, metadataFetch{field: fmt.Sprintf("spec.dataFrom[%d].extract", i), storeRef: storeRef})
+	}
+	for i := range refs {
+		if refs[i].storeRef.Kind == "" {
+			refs[i].storeRef.Kind = SecretStoreKind
+		}
+	}
+	return refs
+}
@@ -0,0 +1,525 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// metadataPP is a provider which is able to fetch metadata.
+type metadataPP struct {
+	PP
+}
+
+func (p *metadataPP) SupportsMetadataFetch(store GenericStore) bool {
+	return true
+}
+
+// getTestProvider returns metadataPP for Kubernetes stores and PP for all others,
+// without touching the provider registry shared with the other tests.
+func getTestProvider(store GenericStore) (Provider, error) {
+	if store.GetSpec().Provider.Kubernetes != nil {
+		return &metadataPP{}, nil
+	}
+	return &PP{}, nil
+}
+
+func TestValidateMetadataPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "supported", Namespace: "default"},
+			Spec:       SecretStoreSpec{Provider: &SecretStoreProvider{Kubernetes: &KubernetesProvider{}}},
+		},
+		&ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "unsupported"},
+			Spec:       SecretStoreSpec{Provider: &SecretStoreProvider{Fake: &FakeProvider{}}},
+		},
+	).Build()
+
+	fetch := ExternalSecretDataRemoteRef{Key: "foo", MetadataPolicy: ExternalSecretMetadataPolicyFetch}
+	tbl := []struct {
+		test     string
+		storeRef SecretStoreRef
+		data     []ExternalSecretData
+		dataFrom []ExternalSecretDataFromRemoteRef
+		expErr   string
+	}{
+		{
+			test:     "should allow fetch on supported provider",
+			storeRef: SecretStoreRef{Name: "supported"},
+			data:     []ExternalSecretData{{SecretKey: "foo", RemoteRef: fetch}},
+		},
+		{
+			test:     "should reject fetch in data on unsupported provider",
+			storeRef: SecretStoreRef{Name: "unsupported", Kind: ClusterSecretStoreKind},
+			data:     []ExternalSecretData{{SecretKey: "foo", RemoteRef: fetch}},
+			expErr:   `invalid spec.data[0].remoteRef: metadataPolicy=Fetch is not supported by ClusterSecretStore "unsupported"`,
+		},
+		{
+			test:     "should reject fetch in dataFrom on unsupported provider",
+			storeRef: SecretStoreRef{Name: "unsupported", Kind: ClusterSecretStoreKind},
+			dataFrom: []ExternalSecretDataFromRemoteRef{{Extract: &fetch}},
+			expErr:   `invalid spec.dataFrom[0].extract: metadataPolicy=Fetch is not supported by ClusterSecretStore "unsupported"`,
+		},
+		{
+			test:     "should allow values on unsupported provider",
+			storeRef: SecretStoreRef{Name: "unsupported", Kind: ClusterSecretStoreKind},
+			data:     []ExternalSecretData{{SecretKey: "foo", RemoteRef: ExternalSecretDataRemoteRef{Key: "foo"}}},
+		},
+		{
+			test:     "should reject fetch on an unsupported store override",
+			storeRef: SecretStoreRef{Name: "supported"},
+			data: []ExternalSecretData{{
+				SecretKey: "foo",
+				RemoteRef: fetch,
+				SourceRef: &StoreSourceRef{StoreRef: SecretStoreRef{Name: "unsupported", Kind: ClusterSecretStoreKind}},
+			}},
+			expErr: `invalid spec.data[0].remoteRef: metadataPolicy=Fetch is not supported by ClusterSecretStore "unsupported"`,
+		},
+		{
+			test:     "should allow fetch on a supported store override",
+			storeRef: SecretStoreRef{Name: "unsupported", Kind: ClusterSecretStoreKind},
+			dataFrom: []ExternalSecretDataFromRemoteRef{{
+				Extract:   &fetch,
+				SourceRef: &SourceRef{StoreRef: &SecretStoreRef{Name: "supported"}},
+			}},
+		},
+		{
+			test:     "should skip the check if the store does not exist",
+			storeRef: SecretStoreRef{Name: "missing"},
+			data:     []ExternalSecretData{{SecretKey: "foo", RemoteRef: fetch}},
+		},
+	}
+	validator := &ExternalSecretValidator{Reader: kube, getProvider: getTestProvider}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			es := &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+				Spec: ExternalSecretSpec{
+					SecretStoreRef: row.storeRef,
+					Data:           row.data,
+					DataFrom:       row.dataFrom,
+				},
+			}
+			err := validator.ValidateCreate(context.Background(), es)
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateStoreConditions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}},
+		},
+		&ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+			Spec: SecretStoreSpec{Conditions: []ClusterSecretStoreCondition{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+				{Namespaces: []string{"shared"}},
+			}},
+		},
+		&ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrestricted"},
+		},
+	).Build()
+
+	tbl := []struct {
+		test      string
+		namespace string
+		storeRef  SecretStoreRef
+		data      []ExternalSecretData
+		expErr    string
+	}{
+		{
+			test:      "should allow a namespace matching the selector",
+			namespace: "team-a",
+			storeRef:  SecretStoreRef{Name: "restricted", Kind: ClusterSecretStoreKind},
+		},
+		{
+			test:      "should reject a namespace matching no condition",
+			namespace: "team-b",
+			storeRef:  SecretStoreRef{Name: "restricted", Kind: ClusterSecretStoreKind},
+			expErr:    `ClusterSecretStore "restricted" may not be used in namespace "team-b"`,
+		},
+		{
+			test:      "should allow a store without conditions",
+			namespace: "team-b",
+			storeRef:  SecretStoreRef{Name: "unrestricted", Kind: ClusterSecretStoreKind},
+		},
+		{
+			test:      "should reject a restricted store override",
+			namespace: "team-b",
+			storeRef:  SecretStoreRef{Name: "unrestricted", Kind: ClusterSecretStoreKind},
+			data: []ExternalSecretData{{
+				SecretKey: "foo",
+				RemoteRef: ExternalSecretDataRemoteRef{Key: "foo"},
+				SourceRef: &StoreSourceRef{StoreRef: SecretStoreRef{Name: "restricted", Kind: ClusterSecretStoreKind}},
+			}},
+			expErr: `ClusterSecretStore "restricted" may not be used in namespace "team-b"`,
+		},
+		{
+			test:      "should ignore a restricted store which no entry uses",
+			namespace: "team-b",
+			storeRef:  SecretStoreRef{Name: "restricted", Kind: ClusterSecretStoreKind},
+			data: []ExternalSecretData{{
+				SecretKey: "foo",
+				RemoteRef: ExternalSecretDataRemoteRef{Key: "foo"},
+				SourceRef: &StoreSourceRef{StoreRef: SecretStoreRef{Name: "unrestricted", Kind: ClusterSecretStoreKind}},
+			}},
+		},
+		{
+			test:      "should not apply conditions to a SecretStore of the same name",
+			namespace: "team-b",
+			storeRef:  SecretStoreRef{Name: "restricted"},
+		},
+	}
+	validator := &ExternalSecretValidator{Reader: kube, getProvider: getTestProvider}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			data := row.data
+			if data == nil {
+				data = []ExternalSecretData{{SecretKey: "foo", RemoteRef: ExternalSecretDataRemoteRef{Key: "foo"}}}
+			}
+			es := &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: row.namespace},
+				Spec: ExternalSecretSpec{
+					SecretStoreRef: row.storeRef,
+					Data:           data,
+				},
+			}
+			err := validator.ValidateCreate(context.Background(), es)
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	store := &ClusterSecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store"},
+		Spec: SecretStoreSpec{Conditions: []ClusterSecretStoreCondition{
+			{
+				Namespaces: []string{"listed"},
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev", "test"}},
+				}},
+			},
+		}},
+	}
+	tbl := []struct {
+		namespace string
+		labels    map[string]string
+		allowed   bool
+	}{
+		{namespace: "listed", allowed: true},
+		{namespace: "dev", labels: map[string]string{"env": "dev"}, allowed: true},
+		{namespace: "prod", labels: map[string]string{"env": "prod"}},
+		{namespace: "other"},
+	}
+	for _, row := range tbl {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: row.namespace, Labels: row.labels}}
+		err := ValidateNamespace(store, ns)
+		if row.allowed && err != nil {
+			t.Errorf("%s: unexpected error: %v", row.namespace, err)
+		}
+		if !row.allowed && !errors.As(err, &NamespaceNotAllowedError{}) {
+			t.Errorf("%s: want NamespaceNotAllowedError, got %v", row.namespace, err)
+		}
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	conditions := []ClusterSecretStoreCondition{{Namespaces: []string{"default"}}}
+	if err := validateConditions(&ClusterSecretStore{Spec: SecretStoreSpec{Conditions: conditions}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateConditions(&SecretStore{Spec: SecretStoreSpec{Conditions: conditions}}); err == nil {
+		t.Errorf("want error for conditions on a SecretStore")
+	}
+	if err := validateConditions(&ClusterSecretStore{Spec: SecretStoreSpec{Conditions: []ClusterSecretStoreCondition{{}}}}); err == nil {
+		t.Errorf("want error for an empty condition")
+	}
+}
+
+func TestValidateAccessRules(t *testing.T) {
+	condition := ClusterSecretStoreCondition{Namespaces: []string{"default"}}
+	tbl := []struct {
+		test   string
+		store  GenericStore
+		expErr string
+	}{
+		{
+			test:  "should allow a rule with prefixes",
+			store: &ClusterSecretStore{Spec: SecretStoreSpec{AccessRules: []ClusterSecretStoreAccessRule{{ClusterSecretStoreCondition: condition, Prefixes: []string{"team-a/"}}}}},
+		},
+		{
+			test:   "should reject rules on a SecretStore",
+			store:  &SecretStore{Spec: SecretStoreSpec{AccessRules: []ClusterSecretStoreAccessRule{{ClusterSecretStoreCondition: condition, Prefixes: []string{"team-a/"}}}}},
+			expErr: "accessRules may only be set on a ClusterSecretStore",
+		},
+		{
+			test:   "should reject a rule without namespaces",
+			store:  &ClusterSecretStore{Spec: SecretStoreSpec{AccessRules: []ClusterSecretStoreAccessRule{{Prefixes: []string{"team-a/"}}}}},
+			expErr: "invalid accessRules[0]: one of namespaceSelector or namespaces must be set",
+		},
+		{
+			test:   "should reject a rule without keys",
+			store:  &ClusterSecretStore{Spec: SecretStoreSpec{AccessRules: []ClusterSecretStoreAccessRule{{ClusterSecretStoreCondition: condition}}}},
+			expErr: "invalid accessRules[0]: one of prefixes or regexps must be set",
+		},
+		{
+			test:   "should reject an invalid regexp",
+			store:  &ClusterSecretStore{Spec: SecretStoreSpec{AccessRules: []ClusterSecretStoreAccessRule{{ClusterSecretStoreCondition: condition, RegExps: []string{"team-("}}}}},
+			expErr: "invalid accessRules[0].regexps[0]: error parsing regexp: missing closing ): `team-(`",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			err := validateAccessRules(row.store)
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateKeyPrefix(t *testing.T) {
+	for prefix, wantErr := range map[string]bool{
+		"":                          false,
+		"tenants/{{ .Namespace }}/": false,
+		"tenants/{{ .Namespace }}":  true,
+		"tenants/{{ .Namespace":     true,
+	} {
+		err := validateKeyPrefix(&ClusterSecretStore{Spec: SecretStoreSpec{KeyPrefix: prefix}})
+		if (err != nil) != wantErr {
+			t.Errorf("keyPrefix %q: want error %v, got %v", prefix, wantErr, err)
+		}
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	tbl := []struct {
+		rateLimit *SecretStoreRateLimit
+		wantErr   bool
+	}{
+		{rateLimit: nil},
+		{rateLimit: &SecretStoreRateLimit{QPS: 5}},
+		{rateLimit: &SecretStoreRateLimit{QPS: 5, Burst: 10}},
+		{rateLimit: &SecretStoreRateLimit{QPS: 0}, wantErr: true},
+		{rateLimit: &SecretStoreRateLimit{QPS: -1}, wantErr: true},
+		{rateLimit: &SecretStoreRateLimit{QPS: 5, Burst: -1}, wantErr: true},
+	}
+	for _, row := range tbl {
+		err := validateRateLimit(&SecretStore{Spec: SecretStoreSpec{RateLimit: row.rateLimit}})
+		if (err != nil) != row.wantErr {
+			t.Errorf("rateLimit %+v: want error %v, got %v", row.rateLimit, row.wantErr, err)
+		}
+	}
+}
+
+func TestValidateDataFromSource(t *testing.T) {
+	extract := &ExternalSecretDataRemoteRef{Key: "foo"}
+	tbl := []struct {
+		test     string
+		dataFrom ExternalSecretDataFromRemoteRef
+		expErr   string
+	}{
+		{
+			test:     "should allow extract",
+			dataFrom: ExternalSecretDataFromRemoteRef{Extract: extract},
+		},
+		{
+			test: "should allow generatorRef",
+			dataFrom: ExternalSecretDataFromRemoteRef{SourceRef: &SourceRef{
+				GeneratorRef: &GeneratorRef{Kind: "Password", Name: "pw"},
+			}},
+		},
+		{
+			test: "should allow extract from a different store",
+			dataFrom: ExternalSecretDataFromRemoteRef{
+				Extract:   extract,
+				SourceRef: &SourceRef{StoreRef: &SecretStoreRef{Name: "other"}},
+			},
+		},
+		{
+			test:     "should reject storeRef without extract or find",
+			dataFrom: ExternalSecretDataFromRemoteRef{SourceRef: &SourceRef{StoreRef: &SecretStoreRef{Name: "other"}}},
+			expErr:   "invalid spec.dataFrom[0]: exactly one of extract, find or sourceRef.generatorRef must be set",
+		},
+		{
+			test: "should reject storeRef together with generatorRef",
+			dataFrom: ExternalSecretDataFromRemoteRef{SourceRef: &SourceRef{
+				StoreRef:     &SecretStoreRef{Name: "other"},
+				GeneratorRef: &GeneratorRef{Kind: "Password", Name: "pw"},
+			}},
+			expErr: "invalid spec.dataFrom[0]: exactly one of sourceRef.storeRef or sourceRef.generatorRef must be set",
+		},
+		{
+			test:     "should reject empty sourceRef",
+			dataFrom: ExternalSecretDataFromRemoteRef{SourceRef: &SourceRef{}},
+			expErr:   "invalid spec.dataFrom[0]: exactly one of sourceRef.storeRef or sourceRef.generatorRef must be set",
+		},
+		{
+			test: "should reject multiple sources",
+			dataFrom: ExternalSecretDataFromRemoteRef{
+				Extract: extract,
+				Find:    &ExternalSecretFind{Tags: map[string]string{"foo": "bar"}},
+			},
+			expErr: "invalid spec.dataFrom[0]: exactly one of extract, find or sourceRef.generatorRef must be set",
+		},
+		{
+			test: "should reject missing source",
+			dataFrom: ExternalSecretDataFromRemoteRef{Rewrite: []ExternalSecretRewrite{{
+				Regexp: &ExternalSecretRewriteRegexp{Source: "foo", Target: "bar"},
+			}}},
+			expErr: "invalid spec.dataFrom[0]: exactly one of extract, find or sourceRef.generatorRef must be set",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			es := &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					DataFrom: []ExternalSecretDataFromRemoteRef{row.dataFrom},
+				},
+			}
+			err := validateExternalSecret(es)
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateTargetKind(t *testing.T) {
+	tbl := []struct {
+		test   string
+		target ExternalSecretTarget
+		expErr string
+	}{
+		{
+			test:   "should allow a ConfigMap target",
+			target: ExternalSecretTarget{Kind: TargetKindConfigMap},
+		},
+		{
+			test:   "should allow a template type with a Secret target",
+			target: ExternalSecretTarget{Kind: TargetKindSecret, Template: &ExternalSecretTemplate{Type: "kubernetes.io/tls"}},
+		},
+		{
+			test:   "should reject a template type with a ConfigMap target",
+			target: ExternalSecretTarget{Kind: TargetKindConfigMap, Template: &ExternalSecretTemplate{Type: "kubernetes.io/tls"}},
+			expErr: "template.type must not be used with target kind ConfigMap",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			err := validateExternalSecret(&ExternalSecret{Spec: ExternalSecretSpec{Target: row.target}})
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
+
+func TestValidateRolloutTargets(t *testing.T) {
+	tbl := []struct {
+		test   string
+		target RolloutTarget
+		expErr string
+	}{
+		{
+			test:   "should allow a name",
+			target: RolloutTarget{Kind: RolloutTargetKindDeployment, Name: "app"},
+		},
+		{
+			test:   "should allow a selector",
+			target: RolloutTarget{Kind: RolloutTargetKindStatefulSet, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+		},
+		{
+			test:   "should reject name and selector",
+			target: RolloutTarget{Kind: RolloutTargetKindDaemonSet, Name: "app", Selector: &metav1.LabelSelector{}},
+			expErr: "invalid spec.target.rolloutTargets[0]: exactly one of name or selector must be set",
+		},
+		{
+			test:   "should reject a missing name and selector",
+			target: RolloutTarget{Kind: RolloutTargetKindDeployment},
+			expErr: "invalid spec.target.rolloutTargets[0]: exactly one of name or selector must be set",
+		},
+		{
+			test: "should reject an invalid selector",
+			target: RolloutTarget{Kind: RolloutTargetKindDeployment, Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Foo"}},
+			}},
+			expErr: `invalid spec.target.rolloutTargets[0]: invalid selector: "Foo" is not a valid pod selector operator`,
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			es := &ExternalSecret{Spec: ExternalSecretSpec{Target: ExternalSecretTarget{RolloutTargets: []RolloutTarget{row.target}}}}
+			err := validateExternalSecret(es)
+			if row.expErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if row.expErr != "" && (err == nil || err.Error() != row.expErr) {
+				t.Errorf("unexpected error: got %v, want %s", err, row.expErr)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ package v1beta1
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -58,6 +60,19 @@ type Provider interface {
 // +k8s:deepcopy-gen:interfaces=nil
 // +k8s:deepcopy-gen=nil
 
+// MetadataFetcher is implemented by providers which are able to return
+// the metadata of a secret instead of its value (metadataPolicy=Fetch).
+type MetadataFetcher interface {
+	// SupportsMetadataFetch returns true if metadata can be fetched
+	// from the given store.
+	SupportsMetadataFetch(store GenericStore) bool
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
 // SecretsClient provides access to secrets.
 type SecretsClient interface {
 	// GetSecret returns a single secret from the provider
@@ -76,9 +91,73 @@ type SecretsClient interface {
 	// GetAllSecrets returns multiple k/v pairs from the provider
 	GetAllSecrets(ctx context.Context, ref ExternalSecretFind) (map[string][]byte, error)
 
+	// SetSecret writes a single secret value into the provider.
+	// Providers must not overwrite secrets they do not manage.
+	SetSecret(ctx context.Context, value []byte, remoteRef PushRemoteRef) error
+
+	// DeleteSecret deletes a secret previously written by SetSecret.
+	DeleteSecret(ctx context.Context, remoteRef PushRemoteRef) error
+
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretsClientRenewer is implemented by clients which hold short-lived
+// credentials. A client which is kept alive across reconciles is renewed
+// before it is used again, instead of creating a new client.
+type SecretsClientRenewer interface {
+	// Renew extends the lifetime of the credentials of the client.
+	// If it returns an error the client is closed and a new one is created.
+	Renew(ctx context.Context) error
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretVersionGetter is implemented by clients which know the version
+// of the values they return, e.g. the VersionId of an AWS secret.
+// The version is recorded in the status of an ExternalSecret.
+type SecretVersionGetter interface {
+	// GetSecretVersion returns the version of the value which the last call
+	// of GetSecret or GetSecretMap returned for the ref. It does not call
+	// the provider and returns an empty string if the version is not known.
+	GetSecretVersion(ctx context.Context, ref ExternalSecretDataRemoteRef) (string, error)
+}
+
+// GetSecretVersion returns the version of the value the client returned for
+// the ref, or an empty string if the client does not implement SecretVersionGetter.
+// Clients which wrap another client use it to pass the call on.
+func GetSecretVersion(ctx context.Context, client SecretsClient, ref ExternalSecretDataRemoteRef) (string, error) {
+	getter, ok := client.(SecretVersionGetter)
+	if !ok {
+		return "", nil
+	}
+	return getter.GetSecretVersion(ctx, ref)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// PushRemoteRef describes the location a PushSecret writes to.
+// It is an interface so that the API types of the PushSecret
+// do not need to live in this package.
+type PushRemoteRef interface {
+	// GetRemoteKey returns the name of the secret in the provider.
+	GetRemoteKey() string
+
+	// GetProperty returns the property of the provider secret the value
+	// is written to. If empty, the value replaces the whole secret.
+	GetProperty() string
+}
+
 var NoSecretErr = NoSecretError{}
 
 // NoSecretError shall be returned when a GetSecret can not find the
@@ -88,3 +167,105 @@ type NoSecretError struct{}
 func (NoSecretError) Error() string {
 	return "Secret does not exist"
 }
+
+// The following errors classify why a provider request failed. Providers wrap
+// the errors of their SDK in them, the controller uses them to pick the
+// condition reason and when to try again. Errors which are not classified
+// are treated like a misconfiguration.
+
+// AuthError shall be returned when the provider rejects the
+// credentials of the store, e.g. because they are invalid or expired.
+// +kubebuilder:object:generate=false
+type AuthError struct {
+	Err error
+}
+
+func (e AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e AuthError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionDeniedError shall be returned when the credentials of
+// the store are not allowed to access the requested secret.
+// +kubebuilder:object:generate=false
+type PermissionDeniedError struct {
+	Err error
+}
+
+func (e PermissionDeniedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e PermissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// ThrottledError shall be returned when the provider rejects a request
+// because a rate limit or quota has been exceeded.
+// +kubebuilder:object:generate=false
+type ThrottledError struct {
+	Err error
+	// RetryAfter is the time after which the request may succeed,
+	// if the provider tells.
+	RetryAfter time.Duration
+}
+
+func (e ThrottledError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// TransientError shall be returned when a request failed
+// but may succeed if it is sent again, e.g. on a timeout.
+// +kubebuilder:object:generate=false
+type TransientError struct {
+	Err error
+}
+
+func (e TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e TransientError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidRefError shall be returned when a remote reference
+// can not be used, e.g. because the key or property is malformed.
+// +kubebuilder:object:generate=false
+type InvalidRefError struct {
+	Err error
+}
+
+func (e InvalidRefError) Error() string {
+	return e.Err.Error()
+}
+
+func (e InvalidRefError) Unwrap() error {
+	return e.Err
+}
+
+// FromHTTPStatus classifies the error of a request
+// which the provider answered with the given HTTP status code.
+// The error is returned as is if the status code is not classified.
+func FromHTTPStatus(code int, err error) error {
+	switch {
+	case code == http.StatusUnauthorized:
+		return AuthError{Err: err}
+	case code == http.StatusForbidden:
+		return PermissionDeniedError{Err: err}
+	case code == http.StatusTooManyRequests:
+		return ThrottledError{Err: err}
+	case code == http.StatusBadRequest:
+		return InvalidRefError{Err: err}
+	case code == http.StatusRequestTimeout, code >= http.StatusInternalServerError:
+		return TransientError{Err: err}
+	}
+	return err
+}
@@ -46,6 +46,14 @@ func (p *PP) GetAllSecrets(ctx context.Context, ref ExternalSecretFind) (map[str
 	return map[string][]byte{}, nil
 }
 
+func (p *PP) SetSecret(ctx context.Context, value []byte, remoteRef PushRemoteRef) error {
+	return nil
+}
+
+func (p *PP) DeleteSecret(ctx context.Context, remoteRef PushRemoteRef) error {
+	return nil
+}
+
 func (p *PP) Close(ctx context.Context) error {
 	return nil
 }
@@ -0,0 +1,44 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1beta1
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHTTPStatus(t *testing.T) {
+	base := errors.New("request failed")
+	tbl := []struct {
+		code int
+		want interface{}
+	}{
+		{code: http.StatusUnauthorized, want: &AuthError{}},
+		{code: http.StatusForbidden, want: &PermissionDeniedError{}},
+		{code: http.StatusTooManyRequests, want: &ThrottledError{}},
+		{code: http.StatusBadRequest, want: &InvalidRefError{}},
+		{code: http.StatusRequestTimeout, want: &TransientError{}},
+		{code: http.StatusServiceUnavailable, want: &TransientError{}},
+	}
+	for _, tt := range tbl {
+		err := FromHTTPStatus(tt.code, base)
+		assert.True(t, errors.As(err, tt.want), "status %d: unexpected error type %T", tt.code, err)
+		assert.ErrorIs(t, err, base)
+		assert.Equal(t, base.Error(), err.Error())
+	}
+	assert.Equal(t, base, FromHTTPStatus(http.StatusConflict, base))
+}
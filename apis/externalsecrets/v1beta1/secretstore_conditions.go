@@ -0,0 +1,131 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// +kubebuilder:object:generate=false
+
+// NamespaceNotAllowedError is returned if the conditions of a
+// ClusterSecretStore do not allow its use in a namespace.
+type NamespaceNotAllowedError struct {
+	Store     string
+	Namespace string
+}
+
+func (e NamespaceNotAllowedError) Error() string {
+	return fmt.Sprintf("ClusterSecretStore %q may not be used in namespace %q", e.Store, e.Namespace)
+}
+
+// ValidateNamespace returns a NamespaceNotAllowedError if the conditions of
+// the store do not match the namespace. A store without conditions may be
+// used in every namespace.
+func ValidateNamespace(store GenericStore, namespace *corev1.Namespace) error {
+	conditions := store.GetSpec().Conditions
+	if len(conditions) == 0 {
+		return nil
+	}
+	for _, condition := range conditions {
+		ok, err := condition.Matches(namespace)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return NamespaceNotAllowedError{Store: store.GetName(), Namespace: namespace.Name}
+}
+
+// Matches returns true if the namespace is listed in the condition
+// or its labels match the namespaceSelector.
+func (c ClusterSecretStoreCondition) Matches(namespace *corev1.Namespace) (bool, error) {
+	for _, name := range c.Namespaces {
+		if name == namespace.Name {
+			return true, nil
+		}
+	}
+	if c.NamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(c.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// validateConditions ensures that conditions are only set on a
+// ClusterSecretStore and that every condition selects namespaces.
+func validateConditions(store GenericStore) error {
+	conditions := store.GetSpec().Conditions
+	if len(conditions) == 0 {
+		return nil
+	}
+	if _, ok := store.(*ClusterSecretStore); !ok {
+		return fmt.Errorf("conditions may only be set on a ClusterSecretStore")
+	}
+	for i, condition := range conditions {
+		if err := condition.validate(); err != nil {
+			return fmt.Errorf("invalid conditions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (c ClusterSecretStoreCondition) validate() error {
+	if c.NamespaceSelector == nil && len(c.Namespaces) == 0 {
+		return fmt.Errorf("one of namespaceSelector or namespaces must be set")
+	}
+	if c.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(c.NamespaceSelector); err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateAccessRules ensures that access rules are only set on a
+// ClusterSecretStore and that every rule selects namespaces and keys.
+func validateAccessRules(store GenericStore) error {
+	rules := store.GetSpec().AccessRules
+	if len(rules) == 0 {
+		return nil
+	}
+	if _, ok := store.(*ClusterSecretStore); !ok {
+		return fmt.Errorf("accessRules may only be set on a ClusterSecretStore")
+	}
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid accessRules[%d]: %w", i, err)
+		}
+		if len(rule.Prefixes) == 0 && len(rule.RegExps) == 0 {
+			return fmt.Errorf("invalid accessRules[%d]: one of prefixes or regexps must be set", i)
+		}
+		for j, re := range rule.RegExps {
+			if _, err := regexp.Compile(re); err != nil {
+				return fmt.Errorf("invalid accessRules[%d].regexps[%d]: %w", i, j, err)
+			}
+		}
+	}
+	return nil
+}
@@ -33,11 +33,66 @@ type SecretStoreSpec struct {
 	// +optional
 	RetrySettings *SecretStoreRetrySettings `json:"retrySettings,omitempty"`
 
+	// Used to limit the rate of requests to the provider.
+	// The limit is shared by all ExternalSecrets using this store.
+	// +optional
+	RateLimit *SecretStoreRateLimit `json:"rateLimit,omitempty"`
+
+	// Used to restrict a ClusterSecretStore to specific namespaces.
+	// The store may be used in a namespace if any condition matches.
+	// Must not be set on a SecretStore.
+	// +optional
+	Conditions []ClusterSecretStoreCondition `json:"conditions,omitempty"`
+
+	// Used to restrict the remote keys which ExternalSecrets may read from a
+	// ClusterSecretStore depending on their namespace. If set, a key may only be
+	// read if a rule which selects the namespace of the ExternalSecret allows it.
+	// Must not be set on a SecretStore.
+	// +optional
+	AccessRules []ClusterSecretStoreAccessRule `json:"accessRules,omitempty"`
+
+	// Used to scope the remote keys of ExternalSecrets and PushSecrets to a
+	// prefix which depends on their namespace. The prefix is a Go template which
+	// may use .Namespace and .Labels of the namespace, e.g. "tenants/{{ .Namespace }}/".
+	// It must end with "/" and is prepended to every remote key and find path. Keys must be relative
+	// and must not contain "..", found keys are returned without the prefix.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
 	// Used to configure store refresh interval in seconds. Empty or 0 will default to the controller config.
 	// +optional
 	RefreshInterval int `json:"refreshInterval"`
 }
 
+// ClusterSecretStoreCondition selects the namespaces in which ExternalSecrets
+// may use a ClusterSecretStore. It matches a namespace which is listed in
+// namespaces or whose labels match the namespaceSelector.
+type ClusterSecretStoreCondition struct {
+	// Choose namespaces by their labels.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Choose namespaces by their name.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// ClusterSecretStoreAccessRule allows ExternalSecrets in the selected namespaces
+// to read the remote keys which start with one of the prefixes or match one of
+// the regular expressions.
+type ClusterSecretStoreAccessRule struct {
+	ClusterSecretStoreCondition `json:",inline"`
+
+	// Remote keys starting with one of the prefixes may be read.
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+
+	// Remote keys matching one of the regular expressions may be read.
+	// The expressions have to match the whole key.
+	// +optional
+	RegExps []string `json:"regexps,omitempty"`
+}
+
 // SecretStoreProvider contains the provider-specific configration.
 // +kubebuilder:validation:MinProperties=1
 // +kubebuilder:validation:MaxProperties=1
@@ -112,6 +167,18 @@ type SecretStoreRetrySettings struct {
 	RetryInterval *string `json:"retryInterval,omitempty"`
 }
 
+// SecretStoreRateLimit configures a token bucket which limits the requests to the provider.
+type SecretStoreRateLimit struct {
+	// QPS is the number of requests per second which may be sent to the provider.
+	// +kubebuilder:validation:Minimum=1
+	QPS int32 `json:"qps"`
+
+	// Burst is the number of requests which may be sent at once. Defaults to QPS.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
 type SecretStoreConditionType string
 
 const (
@@ -17,6 +17,8 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"strings"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -54,9 +56,57 @@ func (r *GenericStoreValidator) ValidateDelete(ctx context.Context, obj runtime.
 }
 
 func validateStore(store GenericStore) error {
+	if err := validateConditions(store); err != nil {
+		return err
+	}
+	if err := validateAccessRules(store); err != nil {
+		return err
+	}
+	if err := validateKeyPrefix(store); err != nil {
+		return err
+	}
+	if err := validateRateLimit(store); err != nil {
+		return err
+	}
 	provider, err := GetProvider(store)
 	if err != nil {
 		return err
 	}
 	return provider.ValidateStore(store)
 }
+
+// validateKeyPrefix ensures that the keyPrefix is a valid template
+// which ends with a path separator.
+// It is rendered for every namespace by the controller.
+func validateKeyPrefix(store GenericStore) error {
+	prefix := store.GetSpec().KeyPrefix
+	if prefix == "" {
+		return nil
+	}
+	if _, err := template.New("keyPrefix").Parse(prefix); err != nil {
+		return fmt.Errorf("invalid keyPrefix: %w", err)
+	}
+	// without a trailing separator one namespace could read the keys of
+	// another namespace whose name starts with its own name.
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("keyPrefix %q must end with \"/\"", prefix)
+	}
+	return nil
+}
+
+// validateRateLimit ensures that the rateLimit allows requests at all.
+// The CRD schema enforces the same minimum, this check also covers
+// clients which skip the schema validation.
+func validateRateLimit(store GenericStore) error {
+	rl := store.GetSpec().RateLimit
+	if rl == nil {
+		return nil
+	}
+	if rl.QPS <= 0 {
+		return fmt.Errorf("rateLimit.qps must be greater than 0, got %d", rl.QPS)
+	}
+	if rl.Burst < 0 {
+		return fmt.Errorf("rateLimit.burst must not be negative, got %d", rl.Burst)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// ECRAuthorizationTokenSpec configures how to authenticate with AWS
+// and where to request the authorization token.
+type ECRAuthorizationTokenSpec struct {
+	// Region specifies the region to operate in.
+	Region string `json:"region"`
+
+	// Auth defines how to authenticate with AWS.
+	// Referenced secrets and service accounts are read from the namespace of the generator.
+	// If empty, the default credential chain of the controller is used.
+	// +optional
+	Auth esv1beta1.AWSAuth `json:"auth,omitempty"`
+
+	// You can assume a role before making calls to the
+	// desired AWS service.
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
+// ECRAuthorizationToken uses the GetAuthorizationToken API to retrieve an
+// authorization token for a private ECR registry.
+// The authorization token is valid for 12 hours, it is renewed before it expires.
+// The generated keys are registry, username, password and expiry.
+// For more information, see <https://docs.aws.amazon.com/AmazonECR/latest/userguide/registry_auth.html>
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,categories={ecrauthorizationtoken}
+type ECRAuthorizationToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ECRAuthorizationTokenSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ECRAuthorizationTokenList contains a list of ECRAuthorizationToken resources.
+type ECRAuthorizationTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ECRAuthorizationToken `json:"items"`
+}
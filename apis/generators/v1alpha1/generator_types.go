@@ -0,0 +1,45 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:object:generate=false
+
+// Generator creates secret values from the spec of a generator resource.
+type Generator interface {
+	// Generate returns the generated key/value pairs.
+	// obj is the JSON representation of the generator resource,
+	// kube and namespace may be used to read referenced resources.
+	Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error)
+}
+
+// +kubebuilder:object:generate=false
+
+// ExpiringGenerator is implemented by generators whose values are only
+// valid for a limited time, e.g. authorization tokens.
+// The values are renewed before they expire.
+type ExpiringGenerator interface {
+	Generator
+
+	// ExpiresAt returns the time the values returned by Generate expire.
+	ExpiresAt(data map[string][]byte) (time.Time, error)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2022 ESO Maintainer Team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+var (
+	forceSyncNamespace     string
+	forceSyncSelector      string
+	forceSyncAllNamespaces bool
+)
+
+var forceSyncCmd = &cobra.Command{
+	Use:   "force-sync [name]",
+	Short: "Trigger an immediate refresh of ExternalSecrets",
+	Long: `Sets the force-sync annotation on a single ExternalSecret or on all ExternalSecrets matching a label selector.
+	The controller refreshes them immediately, regardless of their refreshInterval and refreshPolicy.
+	For more information visit https://external-secrets.io`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if (name == "") == (forceSyncSelector == "") {
+			return errors.New("either a name or a --selector must be given")
+		}
+		if name != "" && forceSyncAllNamespaces {
+			return errors.New("--all-namespaces can only be used with --selector")
+		}
+		selector, err := labels.Parse(forceSyncSelector)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+
+		ns := forceSyncNamespace
+		if ns == "" && !forceSyncAllNamespaces {
+			ns, _, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+				clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).Namespace()
+			if err != nil {
+				return err
+			}
+		}
+		if forceSyncAllNamespaces {
+			ns = ""
+		}
+
+		kube, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			return err
+		}
+		value := strconv.FormatInt(time.Now().Unix(), 10)
+		return forceSync(cmd.Context(), kube, cmd.OutOrStdout(), ns, name, selector, value)
+	},
+}
+
+// forceSync sets the force-sync annotation on the named ExternalSecret
+// or on all ExternalSecrets matching the selector.
+func forceSync(ctx context.Context, kube client.Client, out io.Writer, namespace, name string, selector labels.Selector, value string) error {
+	var list esv1beta1.ExternalSecretList
+	if name != "" {
+		var es esv1beta1.ExternalSecret
+		if err := kube.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &es); err != nil {
+			return err
+		}
+		list.Items = append(list.Items, es)
+	} else {
+		err := kube.List(ctx, &list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return err
+		}
+	}
+	for i := range list.Items {
+		es := &list.Items[i]
+		patch := client.MergeFrom(es.DeepCopy())
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string)
+		}
+		es.Annotations[esv1beta1.AnnotationForceSync] = value
+		if err := kube.Patch(ctx, es, patch); err != nil {
+			return fmt.Errorf("could not annotate ExternalSecret %s/%s: %w", es.Namespace, es.Name, err)
+		}
+		fmt.Fprintf(out, "externalsecret %s/%s annotated\n", es.Namespace, es.Name)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(forceSyncCmd)
+
+	forceSyncCmd.Flags().StringVarP(&forceSyncNamespace, "namespace", "n", "", "namespace of the ExternalSecrets, defaults to the namespace of the current context")
+	forceSyncCmd.Flags().StringVarP(&forceSyncSelector, "selector", "l", "", "label selector of the ExternalSecrets to refresh")
+	forceSyncCmd.Flags().BoolVarP(&forceSyncAllNamespaces, "all-namespaces", "A", false, "refresh matching ExternalSecrets in all namespaces")
+}
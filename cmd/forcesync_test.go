@@ -0,0 +1,121 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const forceSyncValue = "1660000000"
+
+func newExternalSecret(namespace, name, app string) *esv1beta1.ExternalSecret {
+	return &esv1beta1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": app},
+		},
+	}
+}
+
+func TestForceSync(t *testing.T) {
+	tbl := []struct {
+		test      string
+		namespace string
+		name      string
+		selector  string
+		expSynced []string
+		expErr    bool
+	}{
+		{
+			test:      "should annotate the named ExternalSecret",
+			namespace: "team-a",
+			name:      "db",
+			expSynced: []string{"team-a/db"},
+		},
+		{
+			test:      "should fail if the named ExternalSecret does not exist",
+			namespace: "team-a",
+			name:      "missing",
+			expErr:    true,
+		},
+		{
+			test:      "should annotate matching ExternalSecrets in the namespace",
+			namespace: "team-a",
+			selector:  "app=web",
+			expSynced: []string{"team-a/api"},
+		},
+		{
+			test:      "should annotate matching ExternalSecrets in all namespaces",
+			selector:  "app=web",
+			expSynced: []string{"team-a/api", "team-b/api"},
+		},
+		{
+			test:      "should annotate nothing if no ExternalSecret matches",
+			namespace: "team-a",
+			selector:  "app=none",
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newExternalSecret("team-a", "db", "db"),
+				newExternalSecret("team-a", "api", "web"),
+				newExternalSecret("team-b", "api", "web"),
+			).Build()
+			selector, err := labels.Parse(row.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			err = forceSync(context.Background(), kube, &out, row.namespace, row.name, selector, forceSyncValue)
+			if row.expErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			synced := make(map[string]bool)
+			for _, key := range row.expSynced {
+				synced[key] = true
+			}
+			var list esv1beta1.ExternalSecretList
+			if err := kube.List(context.Background(), &list); err != nil {
+				t.Fatal(err)
+			}
+			for i := range list.Items {
+				es := &list.Items[i]
+				key := client.ObjectKeyFromObject(es).String()
+				value, ok := es.Annotations[esv1beta1.AnnotationForceSync]
+				if synced[key] && value != forceSyncValue {
+					t.Errorf("%s: unexpected annotation value: got %q, want %q", key, value, forceSyncValue)
+				}
+				if !synced[key] && ok {
+					t.Errorf("%s: unexpected annotation %q", key, value)
+				}
+				if synced[key] && !bytes.Contains(out.Bytes(), []byte("externalsecret "+key+" annotated")) {
+					t.Errorf("%s: missing from output %q", key, out.String())
+				}
+			}
+		})
+	}
+}
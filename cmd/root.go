@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -35,10 +36,15 @@ import (
 
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/clientpool"
 	"github.com/external-secrets/external-secrets/pkg/controllers/clusterexternalsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	awsauth "github.com/external-secrets/external-secrets/pkg/provider/aws/auth"
+	"github.com/external-secrets/external-secrets/pkg/tracing/otlp"
 )
 
 var (
@@ -53,6 +59,7 @@ var (
 	enableSecretsCache                    bool
 	enableConfigMapsCache                 bool
 	concurrent                            int
+	concurrentFetches                     int
 	port                                  int
 	clientQPS                             float32
 	clientBurst                           int
@@ -60,6 +67,7 @@ var (
 	namespace                             string
 	enableClusterStoreReconciler          bool
 	enableClusterExternalSecretReconciler bool
+	enablePushSecretReconciler            bool
 	enableFloodGate                       bool
 	storeRequeueInterval                  time.Duration
 	serviceName, serviceNamespace         string
@@ -68,6 +76,12 @@ var (
 	certCheckInterval                     time.Duration
 	certLookaheadInterval                 time.Duration
 	enableAWSSession                      bool
+	enableClientPool                      bool
+	clientPoolTTL                         time.Duration
+	rateLimitMaxWait                      time.Duration
+	tracingEndpoint                       string
+	tracingInsecure                       bool
+	tracingSampleRatio                    float64
 )
 
 const (
@@ -78,6 +92,7 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = esv1beta1.AddToScheme(scheme)
 	_ = esv1alpha1.AddToScheme(scheme)
+	_ = genv1alpha1.AddToScheme(scheme)
 	_ = apiextensionsv1.AddToScheme(scheme)
 }
 
@@ -123,12 +138,16 @@ var rootCmd = &cobra.Command{
 			setupLog.Error(err, "unable to start manager")
 			os.Exit(1)
 		}
+		// the token buckets of the stores are shared by ExternalSecrets and PushSecrets
+		// and dropped by the store reconcilers once a store is deleted
+		rateLimiters := ratelimit.New(rateLimitMaxWait)
 		if err = (&secretstore.StoreReconciler{
 			Client:          mgr.GetClient(),
 			Log:             ctrl.Log.WithName("controllers").WithName("SecretStore"),
 			Scheme:          mgr.GetScheme(),
 			ControllerClass: controllerClass,
 			RequeueInterval: storeRequeueInterval,
+			RateLimiters:    rateLimiters,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "SecretStore")
 			os.Exit(1)
@@ -140,11 +159,36 @@ var rootCmd = &cobra.Command{
 				Scheme:          mgr.GetScheme(),
 				ControllerClass: controllerClass,
 				RequeueInterval: storeRequeueInterval,
+				RateLimiters:    rateLimiters,
 			}).SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "ClusterSecretStore")
 				os.Exit(1)
 			}
 		}
+		if tracingEndpoint != "" {
+			tp, err := otlp.Setup(context.Background(), otlp.Options{
+				Endpoint:    tracingEndpoint,
+				Insecure:    tracingInsecure,
+				SampleRatio: tracingSampleRatio,
+				ServiceName: "external-secrets",
+			})
+			if err != nil {
+				setupLog.Error(err, "unable to set up tracing")
+				os.Exit(1)
+			}
+			if err = mgr.Add(tp); err != nil {
+				setupLog.Error(err, "unable to add tracer provider")
+				os.Exit(1)
+			}
+		}
+		var pool *clientpool.Pool
+		if enableClientPool {
+			pool = clientpool.New(clientPoolTTL, ctrl.Log.WithName("clientpool"))
+			if err = mgr.Add(pool); err != nil {
+				setupLog.Error(err, "unable to add client pool")
+				os.Exit(1)
+			}
+		}
 		if err = (&externalsecret.Reconciler{
 			Client:                    mgr.GetClient(),
 			Log:                       ctrl.Log.WithName("controllers").WithName("ExternalSecret"),
@@ -153,6 +197,9 @@ var rootCmd = &cobra.Command{
 			RequeueInterval:           time.Hour,
 			ClusterSecretStoreEnabled: enableClusterStoreReconciler,
 			EnableFloodGate:           enableFloodGate,
+			MaxConcurrentFetches:      concurrentFetches,
+			ClientPool:                pool,
+			RateLimiters:              rateLimiters,
 		}).SetupWithManager(mgr, controller.Options{
 			MaxConcurrentReconciles: concurrent,
 		}); err != nil {
@@ -172,7 +219,29 @@ var rootCmd = &cobra.Command{
 				os.Exit(1)
 			}
 		}
-		if enableAWSSession {
+		if enablePushSecretReconciler {
+			if err = (&pushsecret.Reconciler{
+				Client:                    mgr.GetClient(),
+				Log:                       ctrl.Log.WithName("controllers").WithName("PushSecret"),
+				Scheme:                    mgr.GetScheme(),
+				ControllerClass:           controllerClass,
+				RequeueInterval:           time.Hour,
+				ClusterSecretStoreEnabled: enableClusterStoreReconciler,
+				EnableFloodGate:           enableFloodGate,
+				ClientPool:                pool,
+				RateLimiters:              rateLimiters,
+			}).SetupWithManager(mgr, controller.Options{
+				MaxConcurrentReconciles: concurrent,
+			}); err != nil {
+				setupLog.Error(err, errCreateController, "controller", "PushSecret")
+				os.Exit(1)
+			}
+		}
+		// pooled AWS clients already keep their session alive,
+		// the session cache is only used without the client pool.
+		if enableAWSSession && enableClientPool {
+			setupLog.Info("the AWS session cache is superseded by the client pool and will not be used")
+		} else if enableAWSSession {
 			awsauth.EnableCache = true
 		}
 		setupLog.Info("starting manager")
@@ -195,15 +264,23 @@ func init() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	rootCmd.Flags().IntVar(&concurrent, "concurrent", 1, "The number of concurrent ExternalSecret reconciles.")
+	rootCmd.Flags().IntVar(&concurrentFetches, "max-concurrent-fetches", 1, "The number of data entries fetched concurrently within a single ExternalSecret reconcile.")
 	rootCmd.Flags().Float32Var(&clientQPS, "client-qps", 0, "QPS configuration to be passed to rest.Client")
 	rootCmd.Flags().IntVar(&clientBurst, "client-burst", 0, "Maximum Burst allowed to be passed to rest.Client")
 	rootCmd.Flags().StringVar(&loglevel, "loglevel", "info", "loglevel to use, one of: debug, info, warn, error, dpanic, panic, fatal")
 	rootCmd.Flags().StringVar(&namespace, "namespace", "", "watch external secrets scoped in the provided namespace only. ClusterSecretStore can be used but only work if it doesn't reference resources from other namespaces")
 	rootCmd.Flags().BoolVar(&enableClusterStoreReconciler, "enable-cluster-store-reconciler", true, "Enable cluster store reconciler.")
 	rootCmd.Flags().BoolVar(&enableClusterExternalSecretReconciler, "enable-cluster-external-secret-reconciler", true, "Enable cluster external secret reconciler.")
+	rootCmd.Flags().BoolVar(&enablePushSecretReconciler, "enable-push-secret-reconciler", true, "Enable push secret reconciler.")
 	rootCmd.Flags().BoolVar(&enableSecretsCache, "enable-secrets-caching", false, "Enable secrets caching for external-secrets pod.")
 	rootCmd.Flags().BoolVar(&enableConfigMapsCache, "enable-configmaps-caching", false, "Enable secrets caching for external-secrets pod.")
 	rootCmd.Flags().DurationVar(&storeRequeueInterval, "store-requeue-interval", time.Minute*5, "Default Time duration between reconciling (Cluster)SecretStores")
-	rootCmd.Flags().BoolVar(&enableFloodGate, "enable-flood-gate", true, "Enable flood gate. External secret will be reconciled only if the ClusterStore or Store have an healthy or unknown state.")
-	rootCmd.Flags().BoolVar(&enableAWSSession, "experimental-enable-aws-session-cache", false, "Enable experimental AWS session cache. External secret will reuse the AWS session without creating a new one on each request.")
+	rootCmd.Flags().BoolVar(&enableFloodGate, "enable-flood-gate", true, "Enable flood gate. External secrets and push secrets will be reconciled only if the ClusterStore or Store have an healthy or unknown state.")
+	rootCmd.Flags().BoolVar(&enableAWSSession, "experimental-enable-aws-session-cache", false, "Enable experimental AWS session cache. External secret will reuse the AWS session without creating a new one on each request. Not used together with the client pool.")
+	rootCmd.Flags().BoolVar(&enableClientPool, "experimental-enable-client-pool", false, "Enable experimental provider client pool. External secrets and push secrets will reuse provider clients across reconciles instead of authenticating on each refresh.")
+	rootCmd.Flags().DurationVar(&clientPoolTTL, "client-pool-ttl", time.Minute*10, "Time duration a pooled provider client is kept alive before it is closed and a new one is created")
+	rootCmd.Flags().DurationVar(&rateLimitMaxWait, "rate-limit-max-wait", time.Second*5, "Maximum time a provider request waits for the rate limit of its store. Reconciles which would have to wait longer are requeued")
+	rootCmd.Flags().StringVar(&tracingEndpoint, "tracing-endpoint", "", "host:port of an OTLP gRPC receiver spans of reconciles and provider calls are exported to. Tracing is disabled if empty")
+	rootCmd.Flags().BoolVar(&tracingInsecure, "tracing-insecure", false, "Disable TLS towards the OTLP receiver")
+	rootCmd.Flags().Float64Var(&tracingSampleRatio, "tracing-sample-ratio", 1, "Fraction of reconciles which are traced")
 }
@@ -0,0 +1,219 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package accesspolicy restricts the remote keys which ExternalSecrets and
+// PushSecrets may access in a ClusterSecretStore according to its accessRules.
+package accesspolicy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errInvalidRegExp = "invalid regexp in accessRules[%d]: %w"
+)
+
+// AccessDeniedError is returned instead of accessing a remote key
+// which the access rules of the store do not allow.
+type AccessDeniedError struct {
+	Store     string
+	Namespace string
+	Key       string
+	// Find is set if Key is the path of a dataFrom.find request.
+	Find bool
+}
+
+func (e *AccessDeniedError) Error() string {
+	if e.Find {
+		return fmt.Sprintf("ClusterSecretStore %q does not allow namespace %q to find secrets in path %q, find.path has to start with an allowed prefix", e.Store, e.Namespace, e.Key)
+	}
+	return fmt.Sprintf("ClusterSecretStore %q does not allow namespace %q to access key %q", e.Store, e.Namespace, e.Key)
+}
+
+// Policy holds the remote keys which ExternalSecrets and PushSecrets in a
+// namespace may access in a store. A nil Policy allows all keys.
+type Policy struct {
+	store     string
+	namespace string
+	prefixes  []string
+	regexps   []*regexp.Regexp
+}
+
+// ForNamespace returns the policy of the store for the namespace.
+// It returns nil if the store has no access rules. Rules which do not select
+// the namespace are ignored, so no key may be accessed if none of them does.
+func ForNamespace(store esv1beta1.GenericStore, namespace *corev1.Namespace) (*Policy, error) {
+	rules := store.GetSpec().AccessRules
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	p := &Policy{
+		store:     store.GetName(),
+		namespace: namespace.Name,
+	}
+	for i, rule := range rules {
+		ok, err := rule.Matches(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		p.prefixes = append(p.prefixes, rule.Prefixes...)
+		for _, expr := range rule.RegExps {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf(errInvalidRegExp, i, err)
+			}
+			p.regexps = append(p.regexps, re)
+		}
+	}
+	return p, nil
+}
+
+// Allowed returns true if the remote key may be accessed. Keys which contain
+// a ".." segment are never allowed, since path based providers would resolve
+// them outside of the allowed prefixes.
+func (p *Policy) Allowed(key string) bool {
+	if p == nil {
+		return true
+	}
+	if hasParent(key) {
+		return false
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for _, re := range p.regexps {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathAllowed returns true if all secrets below the find path may be accessed.
+// Regexps can not bound a path, so the path has to start with an allowed prefix.
+func (p *Policy) pathAllowed(path *string) bool {
+	if path == nil || hasParent(*path) {
+		return false
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(*path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasParent returns true if a path segment of the key is "..".
+func hasParent(key string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) check(key string) error {
+	if p.Allowed(key) {
+		return nil
+	}
+	return &AccessDeniedError{Store: p.store, Namespace: p.namespace, Key: key}
+}
+
+// Wrap denies requests of the client for remote keys which are not allowed
+// and removes those keys from the results of GetAllSecrets.
+// The client is returned as is if the policy is nil.
+func (p *Policy) Wrap(secretClient esv1beta1.SecretsClient) esv1beta1.SecretsClient {
+	if p == nil {
+		return secretClient
+	}
+	return &client{SecretsClient: secretClient, policy: p}
+}
+
+// client checks the remote keys of all requests against the policy.
+type client struct {
+	esv1beta1.SecretsClient
+	policy *Policy
+}
+
+func (c *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if err := c.policy.check(ref.Key); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetSecret(ctx, ref)
+}
+
+func (c *client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if err := c.policy.check(ref.Key); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetSecretMap(ctx, ref)
+}
+
+func (c *client) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	if err := c.policy.check(ref.Key); err != nil {
+		return "", err
+	}
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+// GetAllSecrets denies requests without a path inside an allowed prefix,
+// so that the provider does not list secrets outside of them. It returns
+// only the found keys which may be read. The keys are the names of the
+// secrets as returned by the provider.
+func (c *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if !c.policy.pathAllowed(ref.Path) {
+		denied := &AccessDeniedError{Store: c.policy.store, Namespace: c.policy.namespace, Find: true}
+		if ref.Path != nil {
+			denied.Key = *ref.Path
+		}
+		return nil, denied
+	}
+	secretMap, err := c.SecretsClient.GetAllSecrets(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	for key := range secretMap {
+		if !c.policy.Allowed(key) {
+			delete(secretMap, key)
+		}
+	}
+	return secretMap, nil
+}
+
+func (c *client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	if err := c.policy.check(remoteRef.GetRemoteKey()); err != nil {
+		return err
+	}
+	return c.SecretsClient.SetSecret(ctx, value, remoteRef)
+}
+
+func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	if err := c.policy.check(remoteRef.GetRemoteKey()); err != nil {
+		return err
+	}
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
@@ -0,0 +1,234 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accesspolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// countingClient counts the requests it has received.
+type countingClient struct {
+	esv1beta1.SecretsClient
+	calls int
+}
+
+func (c *countingClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls++
+	return []byte("value"), nil
+}
+
+func (c *countingClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	c.calls++
+	return map[string][]byte{
+		"team-a/db":  []byte("a"),
+		"team-b/db":  []byte("b"),
+		"shared-api": []byte("shared"),
+	}, nil
+}
+
+func (c *countingClient) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	c.calls++
+	return nil
+}
+
+func (c *countingClient) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	c.calls++
+	return nil
+}
+
+// remoteRef is a PushRemoteRef without a property.
+type remoteRef string
+
+func (r remoteRef) GetRemoteKey() string {
+	return string(r)
+}
+
+func (r remoteRef) GetProperty() string {
+	return ""
+}
+
+func makeStore() *esv1beta1.ClusterSecretStore {
+	return &esv1beta1.ClusterSecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault"},
+		Spec: esv1beta1.SecretStoreSpec{
+			AccessRules: []esv1beta1.ClusterSecretStoreAccessRule{
+				{
+					ClusterSecretStoreCondition: esv1beta1.ClusterSecretStoreCondition{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					},
+					Prefixes: []string{"team-a/"},
+				},
+				{
+					ClusterSecretStoreCondition: esv1beta1.ClusterSecretStoreCondition{
+						Namespaces: []string{"team-a", "team-b"},
+					},
+					RegExps: []string{"shared-[a-z]+"},
+				},
+			},
+		},
+	}
+}
+
+func makeNamespace(name, team string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{"team": team},
+	}}
+}
+
+func TestAllowed(t *testing.T) {
+	tbl := []struct {
+		namespace *corev1.Namespace
+		key       string
+		allowed   bool
+	}{
+		{namespace: makeNamespace("team-a", "a"), key: "team-a/db", allowed: true},
+		{namespace: makeNamespace("team-a", "a"), key: "team-b/db"},
+		{namespace: makeNamespace("team-a", "a"), key: "shared-api", allowed: true},
+		{namespace: makeNamespace("team-b", "b"), key: "team-a/db"},
+		{namespace: makeNamespace("team-b", "b"), key: "shared-api", allowed: true},
+		// regexps have to match the whole key
+		{namespace: makeNamespace("team-b", "b"), key: "shared-api/v2"},
+		{namespace: makeNamespace("other", "c"), key: "shared-api"},
+		// parent segments could escape an allowed prefix
+		{namespace: makeNamespace("team-a", "a"), key: "team-a/../team-b/db"},
+		{namespace: makeNamespace("team-a", "a"), key: "team-a/.."},
+		{namespace: makeNamespace("team-a", "a"), key: "team-a/..db", allowed: true},
+	}
+	for _, row := range tbl {
+		p, err := ForNamespace(makeStore(), row.namespace)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := p.Allowed(row.key); got != row.allowed {
+			t.Errorf("namespace %s, key %s: want allowed %v, got %v", row.namespace.Name, row.key, row.allowed, got)
+		}
+	}
+}
+
+func TestWithoutRules(t *testing.T) {
+	p, err := ForNamespace(&esv1beta1.ClusterSecretStore{}, makeNamespace("team-a", "a"))
+	if err != nil || p != nil {
+		t.Fatalf("want nil policy, got %v, %v", p, err)
+	}
+	inner := &countingClient{}
+	if c := p.Wrap(inner); c != inner {
+		t.Errorf("client must not be wrapped without access rules")
+	}
+}
+
+func TestClient(t *testing.T) {
+	p, err := ForNamespace(makeStore(), makeNamespace("team-b", "b"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inner := &countingClient{}
+	c := p.Wrap(inner)
+
+	_, err = c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "team-a/db"})
+	var denied *AccessDeniedError
+	if !errors.As(err, &denied) || denied.Key != "team-a/db" {
+		t.Errorf("want AccessDeniedError for team-a/db, got %v", err)
+	}
+	if inner.calls != 0 {
+		t.Errorf("denied request must not be sent to the provider")
+	}
+
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "shared-api"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"team-a/db", "shared-api/../team-a/db"} {
+		if err := c.SetSecret(context.Background(), []byte("value"), remoteRef(key)); !errors.As(err, &denied) {
+			t.Errorf("want AccessDeniedError for SetSecret of %s, got %v", key, err)
+		}
+		if err := c.DeleteSecret(context.Background(), remoteRef(key)); !errors.As(err, &denied) {
+			t.Errorf("want AccessDeniedError for DeleteSecret of %s, got %v", key, err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("denied requests must not be sent to the provider")
+	}
+	if err := c.SetSecret(context.Background(), []byte("value"), remoteRef("shared-api")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// team-b has no prefix which could bound a find path
+	if _, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{}); !errors.As(err, &denied) || !denied.Find {
+		t.Errorf("want AccessDeniedError for find without path, got %v", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("denied find must not be sent to the provider")
+	}
+}
+
+// findClient fails if a find request lists secrets outside of the prefixes.
+type findClient struct {
+	esv1beta1.SecretsClient
+	prefixes []string
+}
+
+func (c *findClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if ref.Path == nil {
+		return nil, errors.New("find without path")
+	}
+	for _, prefix := range c.prefixes {
+		if strings.HasPrefix(*ref.Path, prefix) {
+			return map[string][]byte{
+				"team-a/db":  []byte("a"),
+				"team-b/db":  []byte("b"),
+				"shared-api": []byte("shared"),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("find outside of the allowed prefixes: %s", *ref.Path)
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	p, err := ForNamespace(makeStore(), makeNamespace("team-a", "a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := p.Wrap(&findClient{prefixes: []string{"team-a/"}})
+
+	for _, path := range []*string{nil, pointer("team-b/"), pointer(""), pointer("team-a/../team-b/")} {
+		var denied *AccessDeniedError
+		if _, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: path}); !errors.As(err, &denied) || !denied.Find {
+			t.Errorf("want AccessDeniedError for find path %v, got %v", path, err)
+		}
+	}
+
+	// found keys outside of the access rules are dropped
+	found, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{Path: pointer("team-a/")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 2 || found["team-a/db"] == nil || found["shared-api"] == nil {
+		t.Errorf("want only team-a/db and shared-api, got %v", found)
+	}
+}
+
+func pointer(s string) *string {
+	return &s
+}
@@ -0,0 +1,307 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clientpool keeps provider clients alive across reconciles,
+// so that providers do not have to authenticate on every refresh.
+package clientpool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// renewInterval is the minimum time between two renewals of a client.
+	renewInterval = time.Minute
+	// closeTimeout bounds the time spent closing clients on shutdown.
+	closeTimeout = 10 * time.Second
+
+	errCloseClient = "could not close provider client"
+)
+
+// resetter is implemented by clients which cache values for a single use,
+// e.g. the AWS Secrets Manager client. Reset is called before a pooled
+// client is used again, so that every refresh reads current values.
+type resetter interface {
+	Reset()
+}
+
+// key identifies the clients of a store. A client is only reused as long as
+// the store is not changed and for the namespace it has been created for,
+// since a ClusterSecretStore may resolve references in that namespace.
+type key struct {
+	storeUID        types.UID
+	resourceVersion string
+	namespace       string
+}
+
+func keyOf(store esv1beta1.GenericStore, namespace string) key {
+	meta := store.GetObjectMeta()
+	return key{
+		storeUID:        meta.UID,
+		resourceVersion: meta.ResourceVersion,
+		namespace:       namespace,
+	}
+}
+
+// entry is a pooled client. It is closed once it has been evicted
+// and is no longer used.
+type entry struct {
+	key     key
+	client  esv1beta1.SecretsClient
+	expires time.Time
+	renewed time.Time
+	refs    int
+	evicted bool
+}
+
+// Pool keeps provider clients alive for a TTL. Clients are evicted when
+// their TTL has passed or the store has changed, and closed on shutdown.
+// Credentials referenced by a store are read when the client is created,
+// changes to them are picked up once the client expires.
+// A nil Pool creates a new client on every call to Get.
+type Pool struct {
+	ttl time.Duration
+	log logr.Logger
+	now func() time.Time
+
+	mu      sync.Mutex
+	clients map[key]*entry
+}
+
+// New returns a pool which keeps clients alive for the given TTL.
+func New(ttl time.Duration, log logr.Logger) *Pool {
+	return &Pool{
+		ttl:     ttl,
+		log:     log,
+		now:     time.Now,
+		clients: make(map[key]*entry),
+	}
+}
+
+// Get returns a client for the store which is used in the given namespace.
+// A pooled client is renewed if it implements SecretsClientRenewer,
+// otherwise a new client is created by the provider.
+// Closing the returned client hands it back to the pool.
+func (p *Pool) Get(ctx context.Context, provider esv1beta1.Provider, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	if p == nil {
+		return provider.NewClient(ctx, store, kube, namespace)
+	}
+	k := keyOf(store, namespace)
+	e, renew, stale := p.acquire(k)
+	p.closeAll(ctx, stale)
+	if e != nil {
+		var err error
+		if renew {
+			err = e.client.(esv1beta1.SecretsClientRenewer).Renew(ctx)
+		}
+		if err == nil {
+			if r, ok := e.client.(resetter); ok {
+				r.Reset()
+			}
+			return &pooledClient{SecretsClient: e.client, pool: p, entry: e}, nil
+		}
+		p.log.V(1).Info("could not renew provider client", "store", store.GetName(), "namespace", namespace, "error", err.Error())
+		p.closeAll(ctx, p.discard(e))
+	}
+
+	secretClient, err := provider.NewClient(ctx, store, kube, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledClient{SecretsClient: secretClient, pool: p, entry: p.add(k, secretClient)}, nil
+}
+
+// acquire returns the pooled client for the key, if there is one, and whether
+// it has to be renewed before use. Expired clients and clients of previous
+// versions of the store are evicted and returned to be closed.
+func (p *Pool) acquire(k key) (*entry, bool, []*entry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var stale []*entry
+	for ek, e := range p.clients {
+		if ek.storeUID == k.storeUID && ek.resourceVersion != k.resourceVersion {
+			stale = append(stale, p.evict(e)...)
+		}
+	}
+	e, ok := p.clients[k]
+	if !ok {
+		return nil, false, stale
+	}
+	if !now.Before(e.expires) {
+		return nil, false, append(stale, p.evict(e)...)
+	}
+	e.refs++
+	_, renewer := e.client.(esv1beta1.SecretsClientRenewer)
+	renew := renewer && now.Sub(e.renewed) >= renewInterval
+	if renew {
+		// only one caller renews the client at a time
+		e.renewed = now
+	}
+	return e, renew, stale
+}
+
+// add puts a new client into the pool. If another client for the key has been
+// added in the meantime, the new client is not pooled and closed after use.
+func (p *Pool) add(k key, secretClient esv1beta1.SecretsClient) *entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	e := &entry{
+		key:     k,
+		client:  secretClient,
+		expires: now.Add(p.ttl),
+		renewed: now,
+		refs:    1,
+	}
+	if _, ok := p.clients[k]; ok {
+		e.evicted = true
+		return e
+	}
+	p.clients[k] = e
+	return e
+}
+
+// release hands a client back to the pool.
+// It is closed if it has been evicted and is no longer used.
+func (p *Pool) release(ctx context.Context, e *entry) error {
+	p.mu.Lock()
+	e.refs--
+	closeNow := e.evicted && e.refs == 0
+	p.mu.Unlock()
+	if closeNow {
+		return e.client.Close(ctx)
+	}
+	return nil
+}
+
+// discard evicts and releases a client which could not be renewed.
+func (p *Pool) discard(e *entry) []*entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.evict(e)
+	e.refs--
+	if e.refs == 0 {
+		return []*entry{e}
+	}
+	return nil
+}
+
+// evict removes a client from the pool. It returns the client
+// if it is not in use and can be closed right away.
+// The caller must hold the lock.
+func (p *Pool) evict(e *entry) []*entry {
+	if p.clients[e.key] == e {
+		delete(p.clients, e.key)
+	}
+	if e.evicted {
+		return nil
+	}
+	e.evicted = true
+	if e.refs == 0 {
+		return []*entry{e}
+	}
+	return nil
+}
+
+// evictExpired evicts all clients whose TTL has passed.
+func (p *Pool) evictExpired() []*entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var expired []*entry
+	for _, e := range p.clients {
+		if !now.Before(e.expires) {
+			expired = append(expired, p.evict(e)...)
+		}
+	}
+	return expired
+}
+
+// evictAll evicts all clients, e.g. on shutdown.
+func (p *Pool) evictAll() []*entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var all []*entry
+	for _, e := range p.clients {
+		all = append(all, p.evict(e)...)
+	}
+	return all
+}
+
+func (p *Pool) closeAll(ctx context.Context, entries []*entry) {
+	for _, e := range entries {
+		if err := e.client.Close(ctx); err != nil {
+			p.log.Error(err, errCloseClient)
+		}
+	}
+}
+
+// Len returns the number of pooled clients.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
+// Start evicts expired clients until the context is done,
+// then all clients are closed. It implements manager.Runnable.
+func (p *Pool) Start(ctx context.Context) error {
+	interval := p.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.closeAll(ctx, p.evictExpired())
+		case <-ctx.Done():
+			closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+			defer cancel()
+			p.closeAll(closeCtx, p.evictAll())
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable,
+// clients are closed on shutdown whether or not this instance is the leader.
+func (p *Pool) NeedLeaderElection() bool {
+	return false
+}
+
+// pooledClient hands the client back to the pool when it is closed.
+type pooledClient struct {
+	esv1beta1.SecretsClient
+	pool  *Pool
+	entry *entry
+}
+
+func (c *pooledClient) Close(ctx context.Context) error {
+	return c.pool.release(ctx, c.entry)
+}
+
+func (c *pooledClient) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
@@ -0,0 +1,235 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// countingClient counts how often it has been closed, renewed and reset.
+type countingClient struct {
+	esv1beta1.SecretsClient
+	closed   int
+	renewed  int
+	reset    int
+	renewErr error
+}
+
+func (c *countingClient) Close(ctx context.Context) error {
+	c.closed++
+	return nil
+}
+
+func (c *countingClient) Renew(ctx context.Context) error {
+	c.renewed++
+	return c.renewErr
+}
+
+func (c *countingClient) Reset() {
+	c.reset++
+}
+
+// countingProvider records the clients it has created.
+type countingProvider struct {
+	esv1beta1.Provider
+	clients []*countingClient
+}
+
+func (p *countingProvider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	c := &countingClient{}
+	p.clients = append(p.clients, c)
+	return c, nil
+}
+
+func makeStore(resourceVersion string) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "store",
+			Namespace:       "default",
+			UID:             "uid",
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+type testPool struct {
+	*Pool
+	clock time.Time
+}
+
+func newTestPool() *testPool {
+	tp := &testPool{clock: time.Now()}
+	tp.Pool = New(time.Hour, logr.Discard())
+	tp.Pool.now = func() time.Time { return tp.clock }
+	return tp
+}
+
+func get(t *testing.T, p *testPool, provider esv1beta1.Provider, store esv1beta1.GenericStore, namespace string) esv1beta1.SecretsClient {
+	t.Helper()
+	c, err := p.Get(context.Background(), provider, store, nil, namespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func release(t *testing.T, c esv1beta1.SecretsClient) {
+	t.Helper()
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReuseClient(t *testing.T) {
+	p := newTestPool()
+	provider := &countingProvider{}
+	store := makeStore("1")
+
+	release(t, get(t, p, provider, store, "default"))
+	release(t, get(t, p, provider, store, "default"))
+	if len(provider.clients) != 1 {
+		t.Fatalf("want 1 client, got %d", len(provider.clients))
+	}
+	if provider.clients[0].closed != 0 {
+		t.Errorf("pooled client must not be closed")
+	}
+	if provider.clients[0].reset != 1 {
+		t.Errorf("pooled client must be reset before it is reused")
+	}
+
+	// clients are not shared across namespaces
+	release(t, get(t, p, provider, store, "other"))
+	if len(provider.clients) != 2 {
+		t.Errorf("want 2 clients, got %d", len(provider.clients))
+	}
+}
+
+func TestEvictChangedStore(t *testing.T) {
+	p := newTestPool()
+	provider := &countingProvider{}
+
+	inUse := get(t, p, provider, makeStore("1"), "default")
+	release(t, get(t, p, provider, makeStore("2"), "default"))
+	if len(provider.clients) != 2 {
+		t.Fatalf("want 2 clients, got %d", len(provider.clients))
+	}
+	if provider.clients[0].closed != 0 {
+		t.Errorf("client in use must not be closed")
+	}
+	release(t, inUse)
+	if provider.clients[0].closed != 1 {
+		t.Errorf("client of the previous store version must be closed once released")
+	}
+	if p.Len() != 1 {
+		t.Errorf("want 1 pooled client, got %d", p.Len())
+	}
+}
+
+func TestExpireClient(t *testing.T) {
+	p := newTestPool()
+	provider := &countingProvider{}
+	store := makeStore("1")
+
+	release(t, get(t, p, provider, store, "default"))
+	p.clock = p.clock.Add(2 * time.Hour)
+	release(t, get(t, p, provider, store, "default"))
+	if len(provider.clients) != 2 {
+		t.Fatalf("want 2 clients, got %d", len(provider.clients))
+	}
+	if provider.clients[0].closed != 1 {
+		t.Errorf("expired client must be closed")
+	}
+
+	p.clock = p.clock.Add(2 * time.Hour)
+	p.closeAll(context.Background(), p.evictExpired())
+	if provider.clients[1].closed != 1 || p.Len() != 0 {
+		t.Errorf("expired client must be evicted and closed")
+	}
+}
+
+func TestRenewClient(t *testing.T) {
+	p := newTestPool()
+	provider := &countingProvider{}
+	store := makeStore("1")
+
+	release(t, get(t, p, provider, store, "default"))
+	release(t, get(t, p, provider, store, "default"))
+	if provider.clients[0].renewed != 0 {
+		t.Errorf("client must not be renewed within the renew interval")
+	}
+
+	p.clock = p.clock.Add(renewInterval)
+	release(t, get(t, p, provider, store, "default"))
+	if provider.clients[0].renewed != 1 {
+		t.Errorf("client must be renewed after the renew interval")
+	}
+
+	// a client which can not be renewed is replaced
+	provider.clients[0].renewErr = errors.New("boom")
+	p.clock = p.clock.Add(renewInterval)
+	release(t, get(t, p, provider, store, "default"))
+	if len(provider.clients) != 2 {
+		t.Fatalf("want 2 clients, got %d", len(provider.clients))
+	}
+	if provider.clients[0].closed != 1 {
+		t.Errorf("client which could not be renewed must be closed")
+	}
+}
+
+func TestCloseOnShutdown(t *testing.T) {
+	p := newTestPool()
+	provider := &countingProvider{}
+
+	release(t, get(t, p, provider, makeStore("1"), "default"))
+	inUse := get(t, p, provider, makeStore("1"), "other")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.clients[0].closed != 1 {
+		t.Errorf("idle client must be closed on shutdown")
+	}
+	if provider.clients[1].closed != 0 {
+		t.Errorf("client in use must not be closed on shutdown")
+	}
+	release(t, inUse)
+	if provider.clients[1].closed != 1 {
+		t.Errorf("client in use must be closed once released")
+	}
+}
+
+func TestNilPool(t *testing.T) {
+	var p *Pool
+	provider := &countingProvider{}
+	c, err := p.Get(context.Background(), provider, makeStore("1"), nil, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release(t, c)
+	if provider.clients[0].closed != 1 {
+		t.Errorf("client of a nil pool must be closed")
+	}
+}
@@ -16,6 +16,8 @@ package clusterexternalsecret
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
@@ -52,6 +55,7 @@ const (
 	errSecretAlreadyExists  = "external secret already exists in namespace"
 	errNamespacesFailed     = "one or more namespaces failed"
 	errFailedToDelete       = "external secret in non matching namespace could not be deleted"
+	errRenderSpec           = "could not render ExternalSecret spec"
 )
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -94,7 +98,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	failedNamespaces := r.removeOldNamespaces(ctx, namespaceList, esName, clusterExternalSecret.Status.ProvisionedNamespaces)
+	// namespaces which have been provisioned before are kept if they fail,
+	// so that their ExternalSecret is still removed once they no longer match.
+	wasProvisioned := make(map[string]bool, len(clusterExternalSecret.Status.ProvisionedNamespaces))
+	for _, namespace := range clusterExternalSecret.Status.ProvisionedNamespaces {
+		wasProvisioned[namespace] = true
+	}
 	provisionedNamespaces := []string{}
+	for namespace := range failedNamespaces {
+		provisionedNamespaces = append(provisionedNamespaces, namespace)
+	}
 
 	for _, namespace := range namespaceList.Items {
 		var existingES esv1beta1.ExternalSecret
@@ -106,12 +119,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if result := checkForError(err, &existingES); result != "" {
 			log.Error(err, result)
 			failedNamespaces[namespace.Name] = result
+			if wasProvisioned[namespace.Name] {
+				provisionedNamespaces = append(provisionedNamespaces, namespace.Name)
+			}
 			continue
 		}
 
 		if result, err := r.resolveExternalSecret(ctx, &clusterExternalSecret, &existingES, namespace, esName); err != nil {
 			log.Error(err, result)
 			failedNamespaces[namespace.Name] = result
+			if wasProvisioned[namespace.Name] {
+				provisionedNamespaces = append(provisionedNamespaces, namespace.Name)
+			}
 			continue
 		}
 
@@ -129,7 +148,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	SetClusterExternalSecretCondition(&clusterExternalSecret, *condition)
 	setFailedNamespaces(&clusterExternalSecret, failedNamespaces)
 
+	clusterExternalSecret.Status.ProvisionedNamespaces = nil
 	if len(provisionedNamespaces) > 0 {
+		sort.Strings(provisionedNamespaces)
 		clusterExternalSecret.Status.ProvisionedNamespaces = provisionedNamespaces
 	}
 
@@ -142,12 +163,17 @@ func (r *Reconciler) resolveExternalSecret(ctx context.Context, clusterExternalS
 		return errSetCtrlReference, err
 	}
 
+	spec, err := renderSpec(clusterExternalSecret, &namespace)
+	if err != nil {
+		return fmt.Sprintf("%s: %v", errRenderSpec, err), err
+	}
+
 	externalSecret := esv1beta1.ExternalSecret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      esName,
 			Namespace: namespace.Name,
 		},
-		Spec: clusterExternalSecret.Spec.ExternalSecretSpec,
+		Spec: *spec,
 	}
 
 	if err := controllerutil.SetControllerReference(clusterExternalSecret, &externalSecret, r.Scheme); err != nil {
@@ -155,7 +181,7 @@ func (r *Reconciler) resolveExternalSecret(ctx context.Context, clusterExternalS
 	}
 
 	mutateFunc := func() error {
-		externalSecret.Spec = clusterExternalSecret.Spec.ExternalSecretSpec
+		externalSecret.Spec = *spec
 		return nil
 	}
 
@@ -250,6 +276,7 @@ func getRemovedNamespaces(nsList v1.NamespaceList, provisionedNs []string) []str
 
 func setFailedNamespaces(ces *esv1beta1.ClusterExternalSecret, failedNamespaces map[string]string) {
 	if len(failedNamespaces) == 0 {
+		ces.Status.FailedNamespaces = nil
 		return
 	}
 
@@ -265,9 +292,22 @@ func setFailedNamespaces(ces *esv1beta1.ClusterExternalSecret, failedNamespaces
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ClusterExternalSecret{}, selectorLabelField, indexSelectorLabels)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ClusterExternalSecret{}, statusNamespaceField, indexStatusNamespaces)
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&esv1beta1.ClusterExternalSecret{}).
 		Owns(&esv1beta1.ExternalSecret{}, builder.OnlyMetadata).
+		Watches(
+			&source.Kind{Type: &v1.Namespace{}},
+			newNamespaceEventHandler(mgr.GetCache(), r.Log),
+		).
 		Complete(r)
 }
@@ -0,0 +1,175 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// selectorLabelField indexes ClusterExternalSecrets by the label keys
+	// a namespace must have to match their namespaceSelector.
+	selectorLabelField = "spec.namespaceSelector.labels"
+	// statusNamespaceField indexes ClusterExternalSecrets by the namespaces
+	// listed in their status, i.e. the namespaces they have to clean up.
+	statusNamespaceField = "status.namespaces"
+
+	// anyLabel is indexed for selectors which may match
+	// namespaces without any particular label.
+	anyLabel = "*"
+
+	errListForNamespace = "unable to list ClusterExternalSecrets for namespace"
+)
+
+// indexSelectorLabels returns the label keys a namespace must have to match
+// the namespaceSelector. A namespace matches only if it has all of them,
+// so it is enough to look up the ClusterExternalSecrets indexed by its keys.
+func indexSelectorLabels(obj client.Object) []string {
+	ces, ok := obj.(*esv1beta1.ClusterExternalSecret)
+	if !ok {
+		return nil
+	}
+	selector := ces.Spec.NamespaceSelector
+	keys := make([]string, 0, len(selector.MatchLabels)+len(selector.MatchExpressions))
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	for _, req := range selector.MatchExpressions {
+		if req.Operator == metav1.LabelSelectorOpIn || req.Operator == metav1.LabelSelectorOpExists {
+			keys = append(keys, req.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return []string{anyLabel}
+	}
+	return keys
+}
+
+// indexStatusNamespaces returns the namespaces the ClusterExternalSecret
+// has provisioned or failed to provision an ExternalSecret in.
+func indexStatusNamespaces(obj client.Object) []string {
+	ces, ok := obj.(*esv1beta1.ClusterExternalSecret)
+	if !ok {
+		return nil
+	}
+	namespaces := append([]string{}, ces.Status.ProvisionedNamespaces...)
+	for _, failure := range ces.Status.FailedNamespaces {
+		namespaces = append(namespaces, failure.Namespace)
+	}
+	return namespaces
+}
+
+// namespaceEventHandler enqueues the ClusterExternalSecrets whose
+// namespaceSelector matches a namespace when it is created, relabeled or reannotated,
+// and the ClusterExternalSecrets which have to clean up a namespace
+// when it is relabeled or deleted.
+type namespaceEventHandler struct {
+	reader client.Reader
+	log    logr.Logger
+}
+
+// newNamespaceEventHandler returns a handler for namespaces. The reader must
+// support the selectorLabelField and statusNamespaceField indexes, i.e. it must be the cache.
+func newNamespaceEventHandler(reader client.Reader, log logr.Logger) handler.EventHandler {
+	return &namespaceEventHandler{
+		reader: reader,
+		log:    log,
+	}
+}
+
+func (h *namespaceEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, h.matching(e.Object, e.Object.GetLabels()))
+}
+
+func (h *namespaceEventHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldLabels, newLabels := e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()
+	// annotations may be used by the templates of the externalSecretSpec
+	if labels.Equals(oldLabels, newLabels) && labels.Equals(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) {
+		return
+	}
+	// selectors matching the new labels are indexed by the new label keys,
+	// the ClusterExternalSecrets which do not match anymore are found by their status.
+	h.enqueue(q, h.matching(e.ObjectNew, newLabels), h.provisioned(e.ObjectNew))
+}
+
+func (h *namespaceEventHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, h.provisioned(e.Object))
+}
+
+func (h *namespaceEventHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {}
+
+// matching returns the ClusterExternalSecrets whose namespaceSelector matches the labels.
+func (h *namespaceEventHandler) matching(ns client.Object, nsLabels map[string]string) []string {
+	keys := []string{anyLabel}
+	for key := range nsLabels {
+		keys = append(keys, key)
+	}
+	var names []string
+	for _, key := range keys {
+		for _, ces := range h.list(ns, client.MatchingFields{selectorLabelField: key}) {
+			selector, err := metav1.LabelSelectorAsSelector(&ces.Spec.NamespaceSelector)
+			if err != nil {
+				continue
+			}
+			if selector.Matches(labels.Set(nsLabels)) {
+				names = append(names, ces.Name)
+			}
+		}
+	}
+	return names
+}
+
+// provisioned returns the ClusterExternalSecrets which list the namespace in their status.
+func (h *namespaceEventHandler) provisioned(ns client.Object) []string {
+	var names []string
+	for _, ces := range h.list(ns, client.MatchingFields{statusNamespaceField: ns.GetName()}) {
+		names = append(names, ces.Name)
+	}
+	return names
+}
+
+func (h *namespaceEventHandler) list(ns client.Object, opt client.ListOption) []esv1beta1.ClusterExternalSecret {
+	var list esv1beta1.ClusterExternalSecretList
+	if err := h.reader.List(context.Background(), &list, opt); err != nil {
+		h.log.Error(err, errListForNamespace, "namespace", ns.GetName())
+		return nil
+	}
+	return list.Items
+}
+
+func (h *namespaceEventHandler) enqueue(q workqueue.RateLimitingInterface, names ...[]string) {
+	seen := make(map[string]bool)
+	for _, list := range names {
+		for _, name := range list {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errOverrideSelector = "invalid namespaceSelector of override %d: %w"
+	errApplyOverride    = "could not apply override %d: %w"
+	errRenderField      = "could not render %s: %w"
+	errDecodeSpec       = "invalid externalSecretSpec: %w"
+)
+
+// passedOnFields matches the paths of the fields which are templates of the
+// ExternalSecret itself, they are passed on without being rendered.
+var passedOnFields = regexp.MustCompile(`^(target\.template|dataFrom\[\d+\]\.rewrite\[\d+\]\.transform\.template)$`)
+
+// templateData is available to the templates of the externalSecretSpec.
+type templateData struct {
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// renderSpec returns the externalSecretSpec for the namespace: the overrides matching
+// the namespace are applied in order, then the templates are rendered for the namespace.
+// target.template and the rewrite templates of dataFrom are left as is, since they
+// are rendered by the ExternalSecret itself.
+func renderSpec(ces *esv1beta1.ClusterExternalSecret, namespace *v1.Namespace) (*esv1beta1.ExternalSecretSpec, error) {
+	doc, err := json.Marshal(ces.Spec.ExternalSecretSpec)
+	if err != nil {
+		return nil, err
+	}
+	for i, override := range ces.Spec.Overrides {
+		selector, err := metav1.LabelSelectorAsSelector(&override.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf(errOverrideSelector, i, err)
+		}
+		if !selector.Matches(labels.Set(namespace.Labels)) {
+			continue
+		}
+		doc, err = jsonpatch.MergePatch(doc, override.ExternalSecretSpec.Raw)
+		if err != nil {
+			return nil, fmt.Errorf(errApplyOverride, i, err)
+		}
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+	data := templateData{
+		Namespace:   namespace.Name,
+		Labels:      namespace.Labels,
+		Annotations: namespace.Annotations,
+	}
+	rendered, err := renderValue("", fields, data)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = json.Marshal(rendered); err != nil {
+		return nil, err
+	}
+
+	// unknown fields can only have been added by an override
+	var spec esv1beta1.ExternalSecretSpec
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf(errDecodeSpec, err)
+	}
+	return &spec, nil
+}
+
+// renderValue renders the templates in all strings of the value, path is
+// the path of the value in the externalSecretSpec used in errors.
+func renderValue(path string, value interface{}, data templateData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderString(path, v, data)
+	case map[string]interface{}:
+		for key, field := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			if passedOnFields.MatchString(fieldPath) {
+				continue
+			}
+			rendered, err := renderValue(fieldPath, field, data)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+	case []interface{}:
+		for i, item := range v {
+			rendered, err := renderValue(fmt.Sprintf("%s[%d]", path, i), item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+	}
+	return value, nil
+}
+
+func renderString(path, text string, data templateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tpl, err := template.New(path).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf(errRenderField, path, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf(errRenderField, path, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,167 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterexternalsecret
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func makeCES(spec esv1beta1.ExternalSecretSpec, overrides ...esv1beta1.ClusterExternalSecretOverride) *esv1beta1.ClusterExternalSecret {
+	return &esv1beta1.ClusterExternalSecret{
+		Spec: esv1beta1.ClusterExternalSecretSpec{
+			ExternalSecretSpec: spec,
+			Overrides:          overrides,
+		},
+	}
+}
+
+func override(matchLabels map[string]string, patch string) esv1beta1.ClusterExternalSecretOverride {
+	return esv1beta1.ClusterExternalSecretOverride{
+		NamespaceSelector:  metav1.LabelSelector{MatchLabels: matchLabels},
+		ExternalSecretSpec: apiextensions.JSON{Raw: []byte(patch)},
+	}
+}
+
+func TestRenderSpec(t *testing.T) {
+	namespace := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Labels:      map[string]string{"tier": "prod"},
+			Annotations: map[string]string{"owner": "alice"},
+		},
+	}
+	data := func(key string) []esv1beta1.ExternalSecretData {
+		return []esv1beta1.ExternalSecretData{{
+			SecretKey: "password",
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: key},
+		}}
+	}
+	rewrite := func(key, transform string) esv1beta1.ExternalSecretDataFromRemoteRef {
+		return esv1beta1.ExternalSecretDataFromRemoteRef{
+			Extract: &esv1beta1.ExternalSecretDataRemoteRef{Key: key},
+			Rewrite: []esv1beta1.ExternalSecretRewrite{{
+				Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: transform},
+			}},
+		}
+	}
+	tbl := []struct {
+		name    string
+		ces     *esv1beta1.ClusterExternalSecret
+		want    *esv1beta1.ExternalSecretSpec
+		wantErr bool
+	}{
+		{
+			name: "render namespace, labels and annotations",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{Name: "{{ .Annotations.owner }}-secret"},
+				Data:   data("{{ .Labels.tier }}/{{ .Namespace }}/db"),
+			}),
+			want: &esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{Name: "alice-secret"},
+				Data:   data("prod/team-a/db"),
+			},
+		},
+		{
+			name: "leave target.template to the ExternalSecret",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{
+					Template: &esv1beta1.ExternalSecretTemplate{
+						Data: map[string]string{"url": "https://{{ .password }}@example.com"},
+					},
+				},
+			}),
+			want: &esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{
+					Template: &esv1beta1.ExternalSecretTemplate{
+						Data: map[string]string{"url": "https://{{ .password }}@example.com"},
+					},
+				},
+			},
+		},
+		{
+			name: "leave rewrite templates to the ExternalSecret",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{rewrite("{{ .Namespace }}/db", "{{ .value | upper }}")},
+			}),
+			want: &esv1beta1.ExternalSecretSpec{
+				DataFrom: []esv1beta1.ExternalSecretDataFromRemoteRef{rewrite("team-a/db", "{{ .value | upper }}")},
+			},
+		},
+		{
+			name: "apply matching overrides in order",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{Name: "secret"},
+				Data:   data("db"),
+			},
+				override(map[string]string{"tier": "prod"}, `{"target": {"name": "prod-secret"}, "data": [{"secretKey": "password", "remoteRef": {"key": "{{ .Namespace }}/db"}}]}`),
+				override(map[string]string{"tier": "dev"}, `{"target": {"name": "dev-secret"}}`),
+				override(nil, `{"target": {"deletionPolicy": "Delete"}}`),
+			),
+			want: &esv1beta1.ExternalSecretSpec{
+				Target: esv1beta1.ExternalSecretTarget{Name: "prod-secret", DeletionPolicy: esv1beta1.DeletionPolicyDelete},
+				Data:   data("team-a/db"),
+			},
+		},
+		{
+			name: "fail on missing label",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				Data: data("{{ .Labels.team }}/db"),
+			}),
+			wantErr: true,
+		},
+		{
+			name: "fail on invalid template",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{
+				Data: data("{{ .Namespace"),
+			}),
+			wantErr: true,
+		},
+		{
+			name: "fail on unknown field added by override",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{},
+				override(nil, `{"target": {"nmae": "secret"}}`),
+			),
+			wantErr: true,
+		},
+		{
+			name: "fail on invalid override selector",
+			ces: makeCES(esv1beta1.ExternalSecretSpec{},
+				esv1beta1.ClusterExternalSecretOverride{
+					NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Is"}}},
+				},
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tbl {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderSpec(tt.ces, namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected spec (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
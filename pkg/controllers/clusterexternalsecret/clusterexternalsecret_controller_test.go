@@ -293,6 +293,94 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 		}
 	}
 
+	// namespaces are picked up on creation and relabeling without waiting for the refreshInterval
+	syncNamespaceChanges := func(tc *testCase) {
+		tc.clusterExternalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.beforeCheck = func(tc *testCase) {
+			ctx := context.Background()
+			name, err := ctest.CreateNamespaceWithLabels("test-ns-new", k8sClient, tc.namespaceLabels)
+			Expect(err).ToNot(HaveOccurred())
+			esKey := types.NamespacedName{Namespace: name, Name: ExternalSecretName}
+			cesKey := types.NamespacedName{Name: tc.clusterExternalSecret.Name}
+
+			Eventually(func() bool {
+				var ces esv1beta1.ClusterExternalSecret
+				if err := k8sClient.Get(ctx, cesKey, &ces); err != nil {
+					return false
+				}
+				return sliceContainsString(name, ces.Status.ProvisionedNamespaces) &&
+					k8sClient.Get(ctx, esKey, &esv1beta1.ExternalSecret{}) == nil
+			}, timeout, interval).Should(BeTrue())
+
+			var ns v1.Namespace
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name}, &ns)).To(Succeed())
+			ns.Labels = map[string]string{}
+			Expect(k8sClient.Update(ctx, &ns)).To(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, esKey, &esv1beta1.ExternalSecret{})
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	renderSpecForNamespace := func(tc *testCase) {
+		tc.clusterExternalSecret.Spec.ExternalSecretSpec.Data[0].RemoteRef.Key = "{{ .Namespace }}/" + remoteKey
+		tc.checkExternalSecret = func(ces *esv1beta1.ClusterExternalSecret, es *esv1beta1.ExternalSecret) {
+			Expect(es.Spec.Data[0].RemoteRef.Key).To(Equal(es.Namespace + "/" + remoteKey))
+		}
+	}
+
+	failRenderingSpec := func(tc *testCase) {
+		tc.clusterExternalSecret.Spec.ExternalSecretSpec.Data[0].RemoteRef.Key = "{{ .Labels.missing }}"
+		tc.checkCondition = func(ces *esv1beta1.ClusterExternalSecret) bool {
+			cond := GetClusterExternalSecretCondition(ces.Status, esv1beta1.ClusterExternalSecretNotReady)
+			return cond != nil
+		}
+		tc.checkClusterExternalSecret = func(ces *esv1beta1.ClusterExternalSecret) {
+			Expect(ces.Status.FailedNamespaces).To(HaveLen(len(tc.externalSecretNamespaces)))
+			for _, failure := range ces.Status.FailedNamespaces {
+				Expect(failure.Reason).To(HavePrefix(errRenderSpec))
+			}
+		}
+		tc.checkExternalSecret = nil
+	}
+
+	// a provisioned namespace which fails is kept in the status,
+	// so that its ExternalSecret is still removed once it no longer matches
+	keepFailedProvisionedNamespaces := func(tc *testCase) {
+		tc.beforeCheck = func(tc *testCase) {
+			ctx := context.Background()
+			cesKey := types.NamespacedName{Name: tc.clusterExternalSecret.Name}
+			name := tc.externalSecretNamespaces[0].namespace.Name
+			esKey := types.NamespacedName{Namespace: name, Name: ExternalSecretName}
+
+			var ces esv1beta1.ClusterExternalSecret
+			Expect(k8sClient.Get(ctx, cesKey, &ces)).To(Succeed())
+			ces.Spec.ExternalSecretSpec.Data[0].RemoteRef.Key = "{{ .Labels.missing }}"
+			Expect(k8sClient.Update(ctx, &ces)).To(Succeed())
+
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, cesKey, &ces); err != nil {
+					return false
+				}
+				return len(ces.Status.FailedNamespaces) == len(tc.externalSecretNamespaces) &&
+					sliceContainsString(name, ces.Status.ProvisionedNamespaces)
+			}, timeout, interval).Should(BeTrue())
+
+			var ns v1.Namespace
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name}, &ns)).To(Succeed())
+			ns.Labels = map[string]string{}
+			Expect(k8sClient.Update(ctx, &ns)).To(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, esKey, &esv1beta1.ExternalSecret{})
+				return apierrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+		}
+		tc.checkExternalSecret = nil
+	}
+
 	DescribeTable("When reconciling a ClusterExternal Secret",
 		func(tweaks ...testTweaks) {
 			tc := makeDefaultTestCase()
@@ -367,7 +455,11 @@ var _ = Describe("ClusterExternalSecret controller", func() {
 		Entry("Should not overwrite existing external secrets and error out if one is present", doNotOverwriteExistingES),
 		Entry("Should have list of all provisioned namespaces", populatedProvisionedNamespaces),
 		Entry("Should delete external secrets when namespaces no longer match", deleteESInNonMatchingNS),
-		Entry("Should sync with label selector", syncWithMatchExpressions))
+		Entry("Should sync with label selector", syncWithMatchExpressions),
+		Entry("Should sync namespaces when they are created or relabeled", syncNamespaceChanges),
+		Entry("Should render the spec for each namespace", renderSpecForNamespace),
+		Entry("Should report namespaces the spec could not be rendered for", failRenderingSpec),
+		Entry("Should keep provisioned namespaces which fail", keepFailedProvisionedNamespaces))
 })
 
 func sliceContainsString(toFind string, collection []string) bool {
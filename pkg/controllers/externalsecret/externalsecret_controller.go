@@ -36,9 +36,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/clientpool"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/tracing"
 
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
@@ -46,35 +51,37 @@ import (
 )
 
 const (
-	requeueAfter             = time.Second * 30
-	fieldOwnerTemplate       = "externalsecrets.external-secrets.io/%v"
-	errGetES                 = "could not get ExternalSecret"
-	errConvert               = "could not apply conversion strategy to keys: %v"
-	errDecode                = "could not apply decoding strategy to %v[%d]: %v"
-	errUpdateSecret          = "could not update Secret"
-	errPatchStatus           = "unable to patch status"
-	errGetSecretStore        = "could not get SecretStore %q, %w"
-	errSecretStoreNotReady   = "the desired SecretStore %s is not ready"
-	errGetClusterSecretStore = "could not get ClusterSecretStore %q, %w"
-	errStoreRef              = "could not get store reference"
-	errStoreUsability        = "could not use store reference"
-	errStoreProvider         = "could not get store provider"
-	errStoreClient           = "could not get provider client"
-	errGetExistingSecret     = "could not get existing secret: %w"
-	errCloseStoreClient      = "could not close provider client"
-	errSetCtrlReference      = "could not set ExternalSecret controller reference: %w"
-	errFetchTplFrom          = "error fetching templateFrom data: %w"
-	errGetSecretData         = "could not get secret data from provider"
-	errDeleteSecret          = "could not delete secret"
-	errApplyTemplate         = "could not apply template: %w"
-	errExecTpl               = "could not execute template: %w"
-	errInvalidCreatePolicy   = "invalid creationPolicy=%s. Can not delete secret i do not own"
-	errPolicyMergeNotFound   = "the desired secret %s was not found. With creationPolicy=Merge the secret won't be created"
-	errPolicyMergeGetSecret  = "unable to get secret %s: %w"
-	errPolicyMergeMutate     = "unable to mutate secret %s: %w"
-	errPolicyMergePatch      = "unable to patch secret %s: %w"
-	errTplCMMissingKey       = "error in configmap %s: missing key %s"
-	errTplSecMissingKey      = "error in secret %s: missing key %s"
+	requeueAfter            = time.Second * 30
+	fieldOwnerTemplate      = "externalsecrets.external-secrets.io/%v"
+	errGetES                = "could not get ExternalSecret"
+	errConvert              = "could not apply conversion strategy to keys: %v"
+	errDecode               = "could not apply decoding strategy to %v[%d]: %v"
+	errRewrite              = "could not rewrite keys of %v[%d]: %w"
+	errRewriteCollision     = "%w: key %q of %v[%d] already exists"
+	errUpdateSecret         = "could not update Secret"
+	errPatchStatus          = "unable to patch status"
+	errGetStore             = "could not get store: %w"
+	errGetNamespace         = "could not get namespace: %w"
+	errStoreNotReady        = "store is not ready"
+	errStoreRef             = "could not get store reference"
+	errStoreUsability       = "could not use store reference"
+	errStoreProvider        = "could not get store provider"
+	errStoreClient          = "could not get provider client"
+	errGetExistingSecret    = "could not get existing secret: %w"
+	errCloseStoreClient     = "could not close provider client"
+	errSetCtrlReference     = "could not set ExternalSecret controller reference: %w"
+	errFetchTplFrom         = "error fetching templateFrom data: %w"
+	errGetSecretData        = "could not get secret data from provider"
+	errDeleteSecret         = "could not delete secret"
+	errApplyTemplate        = "could not apply template: %w"
+	errExecTpl              = "could not execute template: %w"
+	errInvalidCreatePolicy  = "invalid creationPolicy=%s. Can not delete secret i do not own"
+	errPolicyMergeNotFound  = "the desired secret %s was not found. With creationPolicy=Merge the secret won't be created"
+	errPolicyMergeGetSecret = "unable to get secret %s: %w"
+	errPolicyMergeMutate    = "unable to mutate secret %s: %w"
+	errPolicyMergePatch     = "unable to patch secret %s: %w"
+	errTplCMMissingKey      = "error in configmap %s: missing key %s"
+	errTplSecMissingKey     = "error in secret %s: missing key %s"
 )
 
 // Reconciler reconciles a ExternalSecret object.
@@ -86,7 +93,22 @@ type Reconciler struct {
 	RequeueInterval           time.Duration
 	ClusterSecretStoreEnabled bool
 	EnableFloodGate           bool
-	recorder                  record.EventRecorder
+	// MaxConcurrentFetches limits the number of entries
+	// which are fetched at the same time within a reconcile.
+	MaxConcurrentFetches int
+	// ClientPool keeps provider clients alive across reconciles.
+	// If nil, a new client is created for every reconcile.
+	ClientPool *clientpool.Pool
+	// RateLimiters limits the requests to stores which have a rateLimit.
+	// If nil, requests are not limited.
+	RateLimiters *ratelimit.Limiters
+	recorder     record.EventRecorder
+	// metadataReader reads from the manager cache,
+	// it supports the field indices and metadata-only objects.
+	metadataReader client.Reader
+	// apiReader reads workloads in spec.target.rolloutTargets
+	// without caching them.
+	apiReader client.Reader
 }
 
 // Reconcile implements the main reconciliation loop
@@ -94,6 +116,8 @@ type Reconciler struct {
 // and updates/creates a Kubernetes secret based on them.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("ExternalSecret", req.NamespacedName)
+	ctx, span := tracing.Start(ctx, "Reconcile", tracing.ExternalSecretAttributes(req.Name, req.Namespace)...)
+	defer span.End()
 
 	syncCallsMetricLabels := prometheus.Labels{"name": req.Name, "namespace": req.Namespace}
 
@@ -130,37 +154,52 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}()
 
-	store, err := r.getStore(ctx, &externalSecret)
+	externalSecret.Status.RefreshPolicy = refreshPolicy(externalSecret)
+
+	storesCtx, storesSpan := tracing.Start(ctx, "GetStores")
+	stores, err := r.getStores(storesCtx, &externalSecret)
+	tracing.End(storesSpan, err)
 	if err != nil {
 		log.Error(err, errStoreRef)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonInvalidStoreRef, err.Error())
-		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreRef)
+		reason := esv1beta1.ConditionReasonSecretSyncedError
+		// a store which is not allowed in the namespace is checked again
+		// when it changes or after requeueAfter, since namespace labels may change
+		notAllowed := errors.As(err, &esv1beta1.NamespaceNotAllowedError{})
+		if notAllowed {
+			reason = esv1beta1.ConditionReasonStoreNotAllowed
+		}
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, reason, storeConditionMessage(errStoreRef, err))
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
+		if notAllowed {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
 		return ctrl.Result{}, err
 	}
 
-	log = log.WithValues("SecretStore", store.GetNamespacedName())
+	if len(stores) > 0 {
+		log = log.WithValues("SecretStore", stores[0].store.GetNamespacedName())
+	}
 
-	// check if store should be handled by this controller instance
-	if !secretstore.ShouldProcessStore(store, r.ControllerClass) {
-		log.Info("skipping unmanaged store")
+	// check if all stores should be handled by this controller instance
+	if unmanaged := stores.unmanaged(r.ControllerClass); unmanaged != nil {
+		log.Info("skipping unmanaged store", "kind", unmanaged.ref.Kind, "name", unmanaged.ref.Name)
 		return ctrl.Result{}, nil
 	}
 
 	if r.EnableFloodGate {
-		if err = assertStoreIsUsable(store); err != nil {
+		if err = stores.assertUsable(); err != nil {
 			log.Error(err, errStoreUsability)
 			r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUnavailableStore, err.Error())
-			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreUsability)
+			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, storeConditionMessage(errStoreUsability, err))
 			SetExternalSecretCondition(&externalSecret, *conditionSynced)
 			syncCallsError.With(syncCallsMetricLabels).Inc()
 			return ctrl.Result{}, err
 		}
 	}
 
-	storeProvider, err := esv1beta1.GetProvider(store)
-	if err != nil {
+	if err = stores.getProviders(); err != nil {
 		log.Error(err, errStoreProvider)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
@@ -178,11 +217,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// fetch external secret, we need to ensure that it exists, and it's hashmap corresponds
-	var existingSecret v1.Secret
-	err = r.Get(ctx, types.NamespacedName{
-		Name:      secretName,
-		Namespace: externalSecret.Namespace,
-	}, &existingSecret)
+	existingSecret, err := r.getExistingTarget(ctx, &externalSecret, secretName)
 	if err != nil && !apierrors.IsNotFound(err) {
 		log.Error(err, errGetExistingSecret)
 	}
@@ -191,11 +226,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// 1. resource generation hasn't changed
 	// 2. refresh interval is 0
 	// 3. if we're still within refresh-interval
-	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret) {
+	// 4. templateFrom sources did not change and generated values are not about to expire,
+	//    both are only considered with refreshPolicy=Periodic
+	genExpiry := r.getGeneratorExpiry(ctx, &externalSecret)
+	periodic := refreshPolicy(externalSecret) == esv1beta1.RefreshPolicyPeriodic
+	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret, externalSecret) &&
+		!(periodic && (r.templateFromChanged(ctx, &externalSecret, &existingSecret) || generatorRenewalDue(genExpiry))) {
 		log.V(1).Info("skipping refresh", "rv", getResourceVersion(externalSecret))
-		return ctrl.Result{RequeueAfter: refreshInt}, nil
+		return ctrl.Result{RequeueAfter: requeueInterval(externalSecret, refreshInt, genExpiry)}, nil
 	}
-	if !shouldReconcile(externalSecret) {
+	if !shouldReconcile(externalSecret, existingSecret) {
 		log.V(1).Info("stopping reconciling", "rv", getResourceVersion(externalSecret))
 		return ctrl.Result{
 			RequeueAfter: 0,
@@ -203,25 +243,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}, nil
 	}
 
-	// secret client is created only if we are going to refresh
+	// secret clients are created only if we are going to refresh
 	// this skip an unnecessary check/request in the case we are not going to do anything
-	secretClient, err := storeProvider.NewClient(ctx, store, r.Client, req.Namespace)
+	defer stores.close(ctx, log)
+	err = stores.open(ctx, r.ClientPool, r.RateLimiters, r.Client, req.Namespace)
 	if err != nil {
 		log.Error(err, errStoreClient)
-		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreClient)
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, storeConditionMessage(errStoreClient, err))
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonProviderClientConfig, err.Error())
 		syncCallsError.With(syncCallsMetricLabels).Inc()
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	defer func() {
-		err = secretClient.Close(ctx)
-		if err != nil {
-			log.Error(err, errCloseStoreClient)
-		}
-	}()
-
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
@@ -231,14 +265,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		Data:      make(map[string][]byte),
 	}
 
-	dataMap, err := r.getProviderSecretData(ctx, secretClient, &externalSecret)
+	dataMap, sources, err := r.getProviderSecretData(ctx, stores, &externalSecret)
+	var throttled *ratelimit.ThrottledError
+	if errors.As(err, &throttled) {
+		log.V(1).Info("store rate limit exceeded, requeueing", "retryAfter", throttled.RetryAfter)
+		return ctrl.Result{RequeueAfter: throttled.RetryAfter}, nil
+	}
 	if err != nil {
 		log.Error(err, errGetSecretData)
-		r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
-		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errGetSecretData)
+		reason := providerErrorReason(err)
+		eventReason := esv1beta1.ReasonUpdateFailed
+		if reason == esv1beta1.ConditionReasonForbidden {
+			eventReason = esv1beta1.ReasonForbidden
+		}
+		r.recorder.Event(&externalSecret, v1.EventTypeWarning, eventReason, err.Error())
+		conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, reason, storeConditionMessage(errGetSecretData, err))
+		if errors.Is(err, utils.ErrKeyCollision) {
+			reason = esv1beta1.ConditionReasonSecretKeyCollision
+			conditionSynced = NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, reason, err.Error())
+		}
 		SetExternalSecretCondition(&externalSecret, *conditionSynced)
 		syncCallsError.With(syncCallsMetricLabels).Inc()
-		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		syncErrors.WithLabelValues(req.Name, req.Namespace, reason).Inc()
+		return providerErrorResult(err, requeueAfter), nil
 	}
 
 	// if no data was found we can delete the secret if needed.
@@ -257,7 +306,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				syncCallsError.With(syncCallsMetricLabels).Inc()
 				return ctrl.Result{RequeueAfter: requeueAfter}, nil
 			}
-			err = r.Delete(ctx, secret)
+			err = r.Delete(ctx, newTargetObject(&externalSecret, secret))
 			if err != nil && !apierrors.IsNotFound(err) {
 				log.Error(err, errDeleteSecret)
 				r.recorder.Event(&externalSecret, v1.EventTypeWarning, esv1beta1.ReasonUpdateFailed, err.Error())
@@ -268,6 +317,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 			conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretDeleted, "secret deleted due to DeletionPolicy")
 			SetExternalSecretCondition(&externalSecret, *conditionSynced)
+			externalSecret.Status.SyncedKeys = nil
 			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 
 		case esv1beta1.DeletionPolicyMerge:
@@ -279,6 +329,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	// targetData is the data written to the target, which is
+	// rendered by target.template if it defines any data.
+	targetData := dataMap
 	mutationFunc := func() error {
 		if externalSecret.Spec.Target.CreationPolicy == esv1beta1.CreatePolicyOwner {
 			err = controllerutil.SetControllerReference(&externalSecret, &secret.ObjectMeta, r.Scheme)
@@ -289,10 +342,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if secret.Data == nil {
 			secret.Data = make(map[string][]byte)
 		}
-		err = r.applyTemplate(ctx, &externalSecret, secret, dataMap)
+		templateCtx, templateSpan := tracing.Start(ctx, "ApplyTemplate")
+		rendered, err := r.applyTemplate(templateCtx, &externalSecret, secret, dataMap)
+		tracing.End(templateSpan, err)
 		if err != nil {
 			return fmt.Errorf(errApplyTemplate, err)
 		}
+		if rendered != nil {
+			targetData = rendered
+			sources.addTemplate(rendered)
+		}
 
 		// diff existing keys
 		if externalSecret.Spec.Target.DeletionPolicy == esv1beta1.DeletionPolicyMerge {
@@ -301,7 +360,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				return err
 			}
 			for _, key := range keys {
-				if dataMap[key] == nil {
+				if targetData[key] == nil {
 					secret.Data[key] = nil
 				}
 			}
@@ -309,16 +368,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return nil
 	}
 
+	// a ConfigMap target is rendered into the secret and copied over
+	var target client.Object = secret
+	targetKind := esv1beta1.TargetKindSecret
+	if isConfigMapTarget(&externalSecret) {
+		cm := newTargetObject(&externalSecret, secret).(*v1.ConfigMap)
+		mutationFunc = configMapMutation(secret, cm, mutationFunc)
+		target = cm
+		targetKind = esv1beta1.TargetKindConfigMap
+	}
+
+	writeCtx, writeSpan := tracing.Start(ctx, "WriteTarget",
+		tracing.TargetKindKey.String(string(targetKind)),
+		tracing.TargetNameKey.String(secretName),
+	)
 	// nolint
 	switch externalSecret.Spec.Target.CreationPolicy {
 	case esv1beta1.CreatePolicyMerge:
-		err = patchSecret(ctx, r.Client, r.Scheme, secret, mutationFunc, externalSecret.Name)
+		err = patchTarget(writeCtx, r.Client, r.Scheme, target, mutationFunc, externalSecret.Name)
 	case esv1beta1.CreatePolicyNone:
 		log.V(1).Info("secret creation skipped due to creationPolicy=None")
 		err = nil
 	default:
-		_, err = ctrl.CreateOrUpdate(ctx, r.Client, secret, mutationFunc)
+		_, err = ctrl.CreateOrUpdate(writeCtx, r.Client, target, mutationFunc)
 	}
+	tracing.End(writeSpan, err)
 
 	if err != nil {
 		log.Error(err, errUpdateSecret)
@@ -330,11 +404,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	r.recorder.Event(&externalSecret, v1.EventTypeNormal, esv1beta1.ReasonUpdated, "Updated Secret")
+	r.rolloutWorkloads(ctx, log, &externalSecret, existingSecret.Annotations[esv1beta1.AnnotationDataHash], secret.Annotations[esv1beta1.AnnotationDataHash])
 	conditionSynced := NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionTrue, esv1beta1.ConditionReasonSecretSynced, "Secret was synced")
 	currCond := GetExternalSecretCondition(externalSecret.Status, esv1beta1.ExternalSecretReady)
 	SetExternalSecretCondition(&externalSecret, *conditionSynced)
 	externalSecret.Status.RefreshTime = metav1.NewTime(time.Now())
 	externalSecret.Status.SyncedResourceVersion = getResourceVersion(externalSecret)
+	externalSecret.Status.SyncedKeys = sources.syncedKeys(externalSecret.UID, targetData)
+	if forceSyncRequested(externalSecret) {
+		log.Info("handled force-sync", "value", externalSecret.Annotations[esv1beta1.AnnotationForceSync])
+		externalSecret.Status.ForceSync = externalSecret.Annotations[esv1beta1.AnnotationForceSync]
+	}
 	syncCallsTotal.With(syncCallsMetricLabels).Inc()
 	if currCond == nil || currCond.Status != conditionSynced.Status {
 		log.Info("reconciled secret") // Log once if on success in any verbosity
@@ -343,24 +423,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	return ctrl.Result{
-		RequeueAfter: refreshInt,
+		RequeueAfter: requeueInterval(externalSecret, refreshInt, r.getGeneratorExpiry(ctx, &externalSecret)),
 	}, nil
 }
 
-func patchSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, secret *v1.Secret, mutationFunc func() error, fieldOwner string) error {
+func patchTarget(ctx context.Context, c client.Client, scheme *runtime.Scheme, target client.Object, mutationFunc func() error, fieldOwner string) error {
 	fqdn := fmt.Sprintf(fieldOwnerTemplate, fieldOwner)
-	err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret.DeepCopy())
+	err := c.Get(ctx, client.ObjectKeyFromObject(target), target.DeepCopyObject().(client.Object))
 	if apierrors.IsNotFound(err) {
-		return fmt.Errorf(errPolicyMergeNotFound, secret.Name)
+		return fmt.Errorf(errPolicyMergeNotFound, target.GetName())
 	}
 	if err != nil {
-		return fmt.Errorf(errPolicyMergeGetSecret, secret.Name, err)
+		return fmt.Errorf(errPolicyMergeGetSecret, target.GetName(), err)
 	}
-	existing := secret.DeepCopyObject()
+	existing := target.DeepCopyObject()
 
 	err = mutationFunc()
 	if err != nil {
-		return fmt.Errorf(errPolicyMergeMutate, secret.Name, err)
+		return fmt.Errorf(errPolicyMergeMutate, target.GetName(), err)
 	}
 
 	// GVK is missing in the Secret, see:
@@ -368,23 +448,23 @@ func patchSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, s
 	// https://github.com/kubernetes-sigs/controller-runtime/issues/1517
 	// https://github.com/kubernetes/kubernetes/issues/80609
 	// we need to manually set it before doing a Patch() as it depends on the GVK
-	gvks, unversioned, err := scheme.ObjectKinds(secret)
+	gvks, unversioned, err := scheme.ObjectKinds(target)
 	if err != nil {
 		return err
 	}
 	if !unversioned && len(gvks) == 1 {
-		secret.SetGroupVersionKind(gvks[0])
+		target.GetObjectKind().SetGroupVersionKind(gvks[0])
 	}
 
-	if equality.Semantic.DeepEqual(existing, secret) {
+	if equality.Semantic.DeepEqual(existing, target) {
 		return nil
 	}
 
 	// we're not able to resolve conflicts so we force ownership
 	// see: https://kubernetes.io/docs/reference/using-api/server-side-apply/#using-server-side-apply-in-a-controller
-	err = c.Patch(ctx, secret, client.Apply, client.FieldOwner(fqdn), client.ForceOwnership)
+	err = c.Patch(ctx, target, client.Apply, client.FieldOwner(fqdn), client.ForceOwnership)
 	if err != nil {
-		return fmt.Errorf(errPolicyMergePatch, secret.Name, err)
+		return fmt.Errorf(errPolicyMergePatch, target.GetName(), err)
 	}
 	return nil
 }
@@ -401,19 +481,18 @@ func getManagedKeys(secret *v1.Secret, fieldOwner string) ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("error unmarshaling managed fields: %w", err)
 		}
-		dataFields := fields["f:data"]
-		if dataFields == nil {
-			continue
-		}
-		df, ok := dataFields.(map[string]string)
-		if !ok {
-			continue
-		}
-		for k := range df {
-			if k == "." {
+		// the keys of a ConfigMap target are also owned in binaryData
+		for _, field := range []string{"f:data", "f:binaryData"} {
+			df, ok := fields[field].(map[string]interface{})
+			if !ok {
 				continue
 			}
-			keys = append(keys, strings.TrimPrefix(k, "f:"))
+			for k := range df {
+				if k == "." {
+					continue
+				}
+				keys = append(keys, strings.TrimPrefix(k, "f:"))
+			}
 		}
 	}
 	return keys, nil
@@ -435,15 +514,66 @@ func hashMeta(m metav1.ObjectMeta) string {
 }
 
 func shouldSkipClusterSecretStore(r *Reconciler, es esv1beta1.ExternalSecret) bool {
-	return !r.ClusterSecretStoreEnabled && es.Spec.SecretStoreRef.Kind == esv1beta1.ClusterSecretStoreKind
+	if r.ClusterSecretStoreEnabled {
+		return false
+	}
+	for _, ref := range storeRefs(&es) {
+		if ref.Kind == esv1beta1.ClusterSecretStoreKind {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshPolicy returns the refresh policy of the ExternalSecret, defaulting to Periodic.
+func refreshPolicy(es esv1beta1.ExternalSecret) esv1beta1.ExternalSecretRefreshPolicy {
+	if es.Spec.RefreshPolicy == "" {
+		return esv1beta1.RefreshPolicyPeriodic
+	}
+	return es.Spec.RefreshPolicy
+}
+
+// requeueInterval returns the delay until the next periodic refresh.
+// Only refreshPolicy=Periodic requeues, the other policies wait for changes.
+func requeueInterval(es esv1beta1.ExternalSecret, refreshInt time.Duration, genExpiry time.Time) time.Duration {
+	if refreshPolicy(es) != esv1beta1.RefreshPolicyPeriodic {
+		return 0
+	}
+	return requeueBeforeExpiry(refreshInt, genExpiry)
+}
+
+// forceSyncRequested returns true if the force-sync annotation
+// has a value which has not been handled yet.
+func forceSyncRequested(es esv1beta1.ExternalSecret) bool {
+	value, ok := es.Annotations[esv1beta1.AnnotationForceSync]
+	return ok && value != es.Status.ForceSync
 }
 
 func shouldRefresh(es esv1beta1.ExternalSecret) bool {
+	// a force-sync is a one-shot refresh regardless of the refresh policy
+	if forceSyncRequested(es) {
+		return true
+	}
+
+	switch refreshPolicy(es) {
+	case esv1beta1.RefreshPolicyCreatedOnce:
+		// refresh until the target has been synced once
+		return es.Status.SyncedResourceVersion == ""
+	case esv1beta1.RefreshPolicyOnChange:
+		// refresh if resource version changed
+		return es.Status.SyncedResourceVersion != getResourceVersion(es)
+	}
+
 	// refresh if resource version changed
 	if es.Status.SyncedResourceVersion != getResourceVersion(es) {
 		return true
 	}
 
+	// refresh if the last sync failed, e.g. because the store was not ready
+	if cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady); cond != nil && cond.Status == v1.ConditionFalse {
+		return true
+	}
+
 	// skip refresh if refresh interval is 0
 	if es.Spec.RefreshInterval.Duration == 0 && es.Status.SyncedResourceVersion != "" {
 		return false
@@ -454,11 +584,12 @@ func shouldRefresh(es esv1beta1.ExternalSecret) bool {
 	return !es.Status.RefreshTime.Add(es.Spec.RefreshInterval.Duration).After(time.Now())
 }
 
-func shouldReconcile(es esv1beta1.ExternalSecret) bool {
-	if es.Spec.Target.Immutable && hasSyncedCondition(es) {
-		return false
+func shouldReconcile(es esv1beta1.ExternalSecret, existingSecret v1.Secret) bool {
+	if !es.Spec.Target.Immutable || !hasSyncedCondition(es) {
+		return true
 	}
-	return true
+	// with refreshPolicy=CreatedOnce a deleted immutable target is created again
+	return refreshPolicy(es) == esv1beta1.RefreshPolicyCreatedOnce && existingSecret.UID == ""
 }
 
 func hasSyncedCondition(es esv1beta1.ExternalSecret) bool {
@@ -471,7 +602,12 @@ func hasSyncedCondition(es esv1beta1.ExternalSecret) bool {
 }
 
 // isSecretValid checks if the secret exists, and it's data is consistent with the calculated hash.
-func isSecretValid(existingSecret v1.Secret) bool {
+// With refreshPolicy=CreatedOnce an existing secret is always valid.
+func isSecretValid(existingSecret v1.Secret, es esv1beta1.ExternalSecret) bool {
+	if refreshPolicy(es) == esv1beta1.RefreshPolicyCreatedOnce && existingSecret.UID != "" {
+		return true
+	}
+
 	// if target secret doesn't exist, or annotations as not set, we need to refresh
 	if existingSecret.UID == "" || existingSecret.Annotations == nil {
 		return false
@@ -488,107 +624,154 @@ func isSecretValid(existingSecret v1.Secret) bool {
 func assertStoreIsUsable(store esv1beta1.GenericStore) error {
 	condition := secretstore.GetSecretStoreCondition(store.GetStatus(), esv1beta1.SecretStoreReady)
 	if condition == nil || condition.Status != v1.ConditionTrue {
-		return fmt.Errorf(errSecretStoreNotReady, store.GetName())
+		return errors.New(errStoreNotReady)
 	}
 	return nil
 }
 
-func (r *Reconciler) getStore(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (esv1beta1.GenericStore, error) {
+func (r *Reconciler) getStore(ctx context.Context, namespace string, storeRef esv1beta1.SecretStoreRef) (esv1beta1.GenericStore, error) {
 	ref := types.NamespacedName{
-		Name: externalSecret.Spec.SecretStoreRef.Name,
+		Name: storeRef.Name,
 	}
 
-	if externalSecret.Spec.SecretStoreRef.Kind == esv1beta1.ClusterSecretStoreKind {
+	if storeRef.Kind == esv1beta1.ClusterSecretStoreKind {
 		var store esv1beta1.ClusterSecretStore
 		err := r.Get(ctx, ref, &store)
 		if err != nil {
-			return nil, fmt.Errorf(errGetClusterSecretStore, ref.Name, err)
+			return nil, fmt.Errorf(errGetStore, err)
+		}
+		if len(store.Spec.Conditions) > 0 {
+			var ns v1.Namespace
+			if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+				return nil, fmt.Errorf(errGetNamespace, err)
+			}
+			if err := esv1beta1.ValidateNamespace(&store, &ns); err != nil {
+				return nil, err
+			}
 		}
 		return &store, nil
 	}
 
-	ref.Namespace = externalSecret.Namespace
+	ref.Namespace = namespace
 
 	var store esv1beta1.SecretStore
 	err := r.Get(ctx, ref, &store)
 	if err != nil {
-		return nil, fmt.Errorf(errGetSecretStore, ref.Name, err)
+		return nil, fmt.Errorf(errGetStore, err)
 	}
 	return &store, nil
 }
 
-// getProviderSecretData returns the provider's secret data with the provided ExternalSecret.
-func (r *Reconciler) getProviderSecretData(ctx context.Context, providerClient esv1beta1.SecretsClient, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, error) {
-	providerData := make(map[string][]byte)
+// getProviderSecretData returns the provider's secret data with the provided ExternalSecret
+// and the entry of the spec every key has been fetched with.
+// Every entry is fetched with the client of the store it refers to.
+// Entries are fetched concurrently and merged in the order of the spec.
+func (r *Reconciler) getProviderSecretData(ctx context.Context, stores storeClients, externalSecret *esv1beta1.ExternalSecret) (map[string][]byte, keySources, error) {
+	dataFrom, data := r.fetchEntries(ctx, stores, externalSecret)
+	var errs []error
+	for _, res := range append(dataFrom, data...) {
+		if res.err != nil {
+			errs = append(errs, res.err)
+		}
+	}
+	if err := combineErrors(errs); err != nil {
+		return nil, nil, err
+	}
 
+	providerData := make(map[string][]byte)
+	sources := make(keySources)
+	var genState *generatorState
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
-		var secretMap map[string][]byte
-		var err error
-		if remoteRef.Find != nil {
-			secretMap, err = providerClient.GetAllSecrets(ctx, *remoteRef.Find)
-			if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
-				r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .dataFrom[%d]", i))
-				continue
-			}
-			if err != nil {
-				return nil, err
-			}
-			secretMap, err = utils.ConvertKeys(remoteRef.Find.ConversionStrategy, secretMap)
-			if err != nil {
-				return nil, fmt.Errorf(errConvert, err)
-			}
-			secretMap, err = utils.DecodeMap(remoteRef.Find.DecodingStrategy, secretMap)
-			if err != nil {
-				return nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
-			}
-		} else if remoteRef.Extract != nil {
-			secretMap, err = providerClient.GetSecretMap(ctx, *remoteRef.Extract)
-			if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
-				r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .dataFrom[%d]", i))
-				continue
-			}
-			if err != nil {
-				return nil, err
+		secretMap := dataFrom[i].data
+		if dataFrom[i].notFound {
+			r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .dataFrom[%d]", i))
+			continue
+		}
+		if remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil {
+			var err error
+			if genState == nil {
+				genState, err = r.getGeneratorState(ctx, externalSecret)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
-			secretMap, err = utils.ConvertKeys(remoteRef.Extract.ConversionStrategy, secretMap)
+			secretMap, err = r.getGeneratorData(ctx, externalSecret, i, remoteRef.SourceRef.GeneratorRef, genState)
 			if err != nil {
-				return nil, fmt.Errorf(errConvert, err)
+				return nil, nil, err
 			}
-			secretMap, err = utils.DecodeMap(remoteRef.Extract.DecodingStrategy, secretMap)
+			secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
 			if err != nil {
-				return nil, fmt.Errorf(errDecode, "spec.dataFrom", i, err)
+				return nil, nil, fmt.Errorf(errRewrite, "spec.dataFrom", i, err)
 			}
 		}
 
+		// rewritten keys must not silently overwrite keys of other dataFrom entries.
+		if len(remoteRef.Rewrite) > 0 {
+			for k := range secretMap {
+				if _, exists := providerData[k]; exists {
+					return nil, nil, fmt.Errorf(errRewriteCollision, utils.ErrKeyCollision, k, "spec.dataFrom", i)
+				}
+			}
+		}
 		providerData = utils.MergeByteMap(providerData, secretMap)
+		sources.addDataFrom(i, remoteRef, secretMap, dataFrom[i].version)
 	}
 
 	for i, secretRef := range externalSecret.Spec.Data {
-		secretData, err := providerClient.GetSecret(ctx, secretRef.RemoteRef)
-		if errors.Is(err, esv1beta1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain {
+		if data[i].notFound {
 			r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1beta1.ReasonDeleted, fmt.Sprintf("secret does not exist at provider using .data[%d] key=%s", i, secretRef.RemoteRef.Key))
 			continue
 		}
-		if err != nil {
-			return nil, err
-		}
-		secretData, err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
-		if err != nil {
-			return nil, fmt.Errorf(errDecode, "spec.data", i, err)
-		}
-		providerData[secretRef.SecretKey] = secretData
+		providerData[secretRef.SecretKey] = data[i].value
+		sources.addData(i, secretRef, data[i].version)
+	}
+
+	if err := r.saveGeneratorState(ctx, externalSecret, genState); err != nil {
+		return nil, nil, err
 	}
 
-	return providerData, nil
+	return providerData, sources, nil
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("external-secrets")
 
+	r.metadataReader = mgr.GetCache()
+	r.apiReader = mgr.GetAPIReader()
+
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ExternalSecret{}, storeRefField, indexStoreRef)
+	if err != nil {
+		return err
+	}
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &esv1beta1.ExternalSecret{}, templateFromField, indexTemplateFrom)
+	if err != nil {
+		return err
+	}
+
+	limiter := newStoreEventLimiter()
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&esv1beta1.ExternalSecret{}).
 		Owns(&v1.Secret{}, builder.OnlyMetadata).
+		Owns(&v1.ConfigMap{}, builder.OnlyMetadata).
+		Watches(
+			&source.Kind{Type: &esv1beta1.SecretStore{}},
+			newStoreEventHandler(mgr.GetCache(), r.Log, esv1beta1.SecretStoreKind, limiter),
+		).
+		Watches(
+			&source.Kind{Type: &esv1beta1.ClusterSecretStore{}},
+			newStoreEventHandler(mgr.GetCache(), r.Log, esv1beta1.ClusterSecretStoreKind, limiter),
+		).
+		Watches(
+			&source.Kind{Type: &v1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findExternalSecretsForTemplate("ConfigMap")),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findExternalSecretsForTemplate("Secret")),
+			builder.OnlyMetadata,
+		).
 		Complete(r)
 }
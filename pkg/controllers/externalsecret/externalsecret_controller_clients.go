@@ -0,0 +1,274 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/accesspolicy"
+	"github.com/external-secrets/external-secrets/pkg/controllers/clientpool"
+	"github.com/external-secrets/external-secrets/pkg/controllers/keyprefix"
+	"github.com/external-secrets/external-secrets/pkg/controllers/providermetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
+	"github.com/external-secrets/external-secrets/pkg/controllers/retry"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/tracing"
+)
+
+const (
+	errNoStoreClient = "no provider client for %s %q"
+)
+
+// storeError annotates an error with the store it originates from.
+type storeError struct {
+	ref esv1beta1.SecretStoreRef
+	err error
+}
+
+func (e *storeError) Error() string {
+	return fmt.Sprintf("%s %q: %v", e.ref.Kind, e.ref.Name, e.err)
+}
+
+func (e *storeError) Unwrap() error {
+	return e.err
+}
+
+// storeConditionMessage appends the failing stores to a condition message.
+// If several entries failed, the entries are listed with their store,
+// e.g. `msg: SecretStore "a" (spec.data[0], spec.data[2]), SecretStore "b" (spec.dataFrom[0])`.
+func storeConditionMessage(msg string, err error) string {
+	errs, ok := err.(fetchErrors)
+	if !ok {
+		errs = fetchErrors{err}
+	}
+	var refs []esv1beta1.SecretStoreRef
+	fields := make(map[esv1beta1.SecretStoreRef][]string)
+	for _, err := range errs {
+		var sErr *storeError
+		if !errors.As(err, &sErr) {
+			continue
+		}
+		if _, ok := fields[sErr.ref]; !ok {
+			refs = append(refs, sErr.ref)
+			fields[sErr.ref] = nil
+		}
+		var eErr *entryError
+		if len(errs) > 1 && errors.As(err, &eErr) {
+			fields[sErr.ref] = append(fields[sErr.ref], eErr.field)
+		}
+	}
+	if len(refs) == 0 {
+		return msg
+	}
+	stores := make([]string, len(refs))
+	for i, ref := range refs {
+		stores[i] = fmt.Sprintf("%s %q", ref.Kind, ref.Name)
+		if len(fields[ref]) > 0 {
+			stores[i] += fmt.Sprintf(" (%s)", strings.Join(fields[ref], ", "))
+		}
+	}
+	return fmt.Sprintf("%s: %s", msg, strings.Join(stores, ", "))
+}
+
+// normalizeStoreRef defaults the kind of a store reference to SecretStore.
+func normalizeStoreRef(ref esv1beta1.SecretStoreRef) esv1beta1.SecretStoreRef {
+	if ref.Kind == "" {
+		ref.Kind = esv1beta1.SecretStoreKind
+	}
+	return ref
+}
+
+// dataStoreRef returns the store a spec.data entry is fetched from.
+func dataStoreRef(es *esv1beta1.ExternalSecret, data esv1beta1.ExternalSecretData) esv1beta1.SecretStoreRef {
+	if data.SourceRef != nil {
+		return normalizeStoreRef(data.SourceRef.StoreRef)
+	}
+	return normalizeStoreRef(es.Spec.SecretStoreRef)
+}
+
+// dataFromStoreRef returns the store a spec.dataFrom entry is fetched from.
+func dataFromStoreRef(es *esv1beta1.ExternalSecret, ref esv1beta1.ExternalSecretDataFromRemoteRef) esv1beta1.SecretStoreRef {
+	if ref.SourceRef != nil && ref.SourceRef.StoreRef != nil {
+		return normalizeStoreRef(*ref.SourceRef.StoreRef)
+	}
+	return normalizeStoreRef(es.Spec.SecretStoreRef)
+}
+
+// storeRefs returns the distinct stores an ExternalSecret fetches from.
+// spec.secretStoreRef is only included if an entry without its own
+// store uses it, entries with a generator do not use a store at all.
+func storeRefs(es *esv1beta1.ExternalSecret) []esv1beta1.SecretStoreRef {
+	var refs []esv1beta1.SecretStoreRef
+	seen := make(map[esv1beta1.SecretStoreRef]bool)
+	add := func(ref esv1beta1.SecretStoreRef) {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	for _, data := range es.Spec.Data {
+		add(dataStoreRef(es, data))
+	}
+	for _, ref := range es.Spec.DataFrom {
+		if ref.SourceRef != nil && ref.SourceRef.GeneratorRef != nil {
+			continue
+		}
+		add(dataFromStoreRef(es, ref))
+	}
+	return refs
+}
+
+// storeClient is a store referenced by an ExternalSecret
+// and the provider client which is used to fetch from it.
+type storeClient struct {
+	ref      esv1beta1.SecretStoreRef
+	store    esv1beta1.GenericStore
+	policy   *accesspolicy.Policy
+	prefix   *keyprefix.Prefix
+	provider esv1beta1.Provider
+	client   esv1beta1.SecretsClient
+}
+
+// storeClients holds one provider client per store referenced by an ExternalSecret.
+type storeClients []*storeClient
+
+// getStores returns the stores referenced by the ExternalSecret.
+// Errors are annotated with the store which could not be read.
+func (r *Reconciler) getStores(ctx context.Context, externalSecret *esv1beta1.ExternalSecret) (storeClients, error) {
+	refs := storeRefs(externalSecret)
+	stores := make(storeClients, 0, len(refs))
+	for _, ref := range refs {
+		store, err := r.getStore(ctx, externalSecret.Namespace, ref)
+		if err != nil {
+			return nil, &storeError{ref: ref, err: err}
+		}
+		policy, prefix, err := r.getStoreScope(ctx, externalSecret.Namespace, store)
+		if err != nil {
+			return nil, &storeError{ref: ref, err: err}
+		}
+		stores = append(stores, &storeClient{ref: ref, store: store, policy: policy, prefix: prefix})
+	}
+	return stores, nil
+}
+
+// getStoreScope returns the remote keys which ExternalSecrets in the
+// namespace may read from the store and the prefix of their keys.
+func (r *Reconciler) getStoreScope(ctx context.Context, namespace string, store esv1beta1.GenericStore) (*accesspolicy.Policy, *keyprefix.Prefix, error) {
+	spec := store.GetSpec()
+	if len(spec.AccessRules) == 0 && spec.KeyPrefix == "" {
+		return nil, nil, nil
+	}
+	var ns v1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return nil, nil, fmt.Errorf(errGetNamespace, err)
+	}
+	policy, err := accesspolicy.ForNamespace(store, &ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix, err := keyprefix.ForNamespace(store, &ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, prefix, nil
+}
+
+// unmanaged returns the first store which is not handled by this controller instance.
+func (s storeClients) unmanaged(controllerClass string) *storeClient {
+	for _, sc := range s {
+		if !secretstore.ShouldProcessStore(sc.store, controllerClass) {
+			return sc
+		}
+	}
+	return nil
+}
+
+// assertUsable ensures that all stores are ready to use.
+func (s storeClients) assertUsable() error {
+	for _, sc := range s {
+		if err := assertStoreIsUsable(sc.store); err != nil {
+			return &storeError{ref: sc.ref, err: err}
+		}
+	}
+	return nil
+}
+
+// getProviders looks up the provider of every store.
+func (s storeClients) getProviders() error {
+	for _, sc := range s {
+		provider, err := esv1beta1.GetProvider(sc.store)
+		if err != nil {
+			return &storeError{ref: sc.ref, err: err}
+		}
+		sc.provider = provider
+	}
+	return nil
+}
+
+// open gets one provider client per store from the pool and applies the
+// rate limit and retry settings of the store. Every retry waits for the rate
+// limit and is recorded in the provider metrics and traces. Remote keys are
+// prefixed with the keyPrefix of the store and requests for keys which its
+// access rules do not allow are denied before any of these. Clients which
+// have been opened before an error occurred are closed by close.
+func (s storeClients) open(ctx context.Context, pool *clientpool.Pool, limiters *ratelimit.Limiters, kube client.Client, namespace string) error {
+	for _, sc := range s {
+		provider := tracing.WrapProvider(providermetrics.WrapProvider(sc.provider))
+		secretClient, err := pool.Get(ctx, provider, sc.store, kube, namespace)
+		if err != nil {
+			return &storeError{ref: sc.ref, err: err}
+		}
+		sc.client = secretClient
+		instrumented := tracing.Wrap(sc.store, providermetrics.Wrap(sc.store, secretClient))
+		retryClient, err := retry.Wrap(sc.store, limiters.Wrap(sc.store, instrumented))
+		if err != nil {
+			return &storeError{ref: sc.ref, err: err}
+		}
+		sc.client = sc.prefix.Wrap(sc.policy.Wrap(retryClient))
+	}
+	return nil
+}
+
+// close closes all provider clients which have been opened.
+func (s storeClients) close(ctx context.Context, log logr.Logger) {
+	for _, sc := range s {
+		if sc.client == nil {
+			continue
+		}
+		if err := sc.client.Close(ctx); err != nil {
+			log.Error(err, errCloseStoreClient, "kind", sc.ref.Kind, "name", sc.ref.Name)
+		}
+		sc.client = nil
+	}
+}
+
+// get returns the provider client of a store.
+func (s storeClients) get(ref esv1beta1.SecretStoreRef) (*storeClient, error) {
+	for _, sc := range s {
+		if sc.ref == ref && sc.client != nil {
+			return sc, nil
+		}
+	}
+	return nil, fmt.Errorf(errNoStoreClient, ref.Kind, ref.Name)
+}
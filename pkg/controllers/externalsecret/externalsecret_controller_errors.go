@@ -0,0 +1,69 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"errors"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/accesspolicy"
+)
+
+// providerErrorReason returns the condition reason for an error returned by a provider
+// or for a request which the access rules of the store denied.
+// If several entries failed, the first classified error in the order below wins,
+// errors which need to be fixed by the user take precedence over temporary ones.
+func providerErrorReason(err error) string {
+	var denied *accesspolicy.AccessDeniedError
+	switch {
+	case errors.As(err, &denied):
+		return esv1beta1.ConditionReasonForbidden
+	case errors.As(err, &esv1beta1.AuthError{}):
+		return esv1beta1.ConditionReasonAuthError
+	case errors.As(err, &esv1beta1.PermissionDeniedError{}):
+		return esv1beta1.ConditionReasonPermissionDenied
+	case errors.As(err, &esv1beta1.InvalidRefError{}):
+		return esv1beta1.ConditionReasonInvalidRef
+	case errors.As(err, &esv1beta1.ThrottledError{}):
+		return esv1beta1.ConditionReasonThrottled
+	case errors.As(err, &esv1beta1.TransientError{}):
+		return esv1beta1.ConditionReasonTransientError
+	}
+	return esv1beta1.ConditionReasonSecretSyncedError
+}
+
+// providerErrorResult returns when to try again after a provider error.
+// Throttled and transient errors are retried with the backoff of the
+// controller's rate limiter, unless the provider tells when to retry.
+// Other errors need to be fixed by the user and are retried after
+// requeueAfter, independent of the refresh interval, so that a fix
+// outside of the cluster, e.g. of a provider policy, is picked up.
+func providerErrorResult(err error, requeueAfter time.Duration) ctrl.Result {
+	switch providerErrorReason(err) {
+	case esv1beta1.ConditionReasonThrottled:
+		var throttled esv1beta1.ThrottledError
+		errors.As(err, &throttled)
+		if throttled.RetryAfter > 0 {
+			return ctrl.Result{RequeueAfter: throttled.RetryAfter}
+		}
+		return ctrl.Result{Requeue: true}
+	case esv1beta1.ConditionReasonTransientError:
+		return ctrl.Result{Requeue: true}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
@@ -0,0 +1,227 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+// Entries of spec.data and spec.dataFrom which refer to a store are fetched
+// concurrently. The results are merged afterwards in the order of the spec,
+// so that the merge result does not depend on the order in which the
+// fetches complete. Generators are run sequentially during the merge.
+
+// entryError annotates an error with the entry it originates from.
+type entryError struct {
+	field string
+	err   error
+}
+
+func (e *entryError) Error() string {
+	return fmt.Sprintf("%s: %v", e.field, e.err)
+}
+
+func (e *entryError) Unwrap() error {
+	return e.err
+}
+
+// fetchErrors combines the errors of several entries.
+type fetchErrors []error
+
+func (e fetchErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the errors matches the target.
+func (e fetchErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error which matches the target.
+func (e fetchErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// combineErrors returns nil, the only error or all errors combined.
+func combineErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return fetchErrors(errs)
+}
+
+// fetchResult is the outcome of fetching a single entry.
+type fetchResult struct {
+	// data holds the values of a spec.dataFrom entry.
+	data map[string][]byte
+	// value holds the value of a spec.data entry.
+	value []byte
+	// version is the version of the secret which has been fetched
+	// by a spec.data or extract entry, if the provider reports it.
+	version string
+	// notFound is set if the secret does not exist at the provider
+	// and the deletionPolicy allows to drop it.
+	notFound bool
+	err      error
+}
+
+// runConcurrently runs the jobs with at most limit jobs at a time.
+func runConcurrently(limit int, jobs []func()) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(job func()) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			job()
+		}(job)
+	}
+	wg.Wait()
+}
+
+// isNotFound returns true if a secret which does not exist at the provider
+// is dropped from the target instead of failing the sync.
+func isNotFound(es *esv1beta1.ExternalSecret, err error) bool {
+	return errors.Is(err, esv1beta1.NoSecretErr) && es.Spec.Target.DeletionPolicy != esv1beta1.DeletionPolicyRetain
+}
+
+// fetchDataFrom fetches an extract or find entry of spec.dataFrom
+// and applies its rewrite, conversion and decoding strategies.
+func fetchDataFrom(ctx context.Context, stores storeClients, es *esv1beta1.ExternalSecret, i int) fetchResult {
+	remoteRef := es.Spec.DataFrom[i]
+	field := fmt.Sprintf("spec.dataFrom[%d]", i)
+	sc, err := stores.get(dataFromStoreRef(es, remoteRef))
+	if err != nil {
+		return fetchResult{err: &entryError{field: field, err: err}}
+	}
+
+	var secretMap map[string][]byte
+	var conversion esv1beta1.ExternalSecretConversionStrategy
+	var decoding esv1beta1.ExternalSecretDecodingStrategy
+	if remoteRef.Find != nil {
+		secretMap, err = sc.client.GetAllSecrets(ctx, *remoteRef.Find)
+		conversion, decoding = remoteRef.Find.ConversionStrategy, remoteRef.Find.DecodingStrategy
+	} else {
+		secretMap, err = sc.client.GetSecretMap(ctx, *remoteRef.Extract)
+		conversion, decoding = remoteRef.Extract.ConversionStrategy, remoteRef.Extract.DecodingStrategy
+	}
+	if isNotFound(es, err) {
+		return fetchResult{notFound: true}
+	}
+	if err != nil {
+		return fetchResult{err: &entryError{field: field, err: &storeError{ref: sc.ref, err: err}}}
+	}
+	var version string
+	if remoteRef.Extract != nil {
+		version, err = esv1beta1.GetSecretVersion(ctx, sc.client, *remoteRef.Extract)
+		if err != nil {
+			return fetchResult{err: &entryError{field: field, err: &storeError{ref: sc.ref, err: err}}}
+		}
+	}
+
+	secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
+	if err != nil {
+		return fetchResult{err: fmt.Errorf(errRewrite, "spec.dataFrom", i, err)}
+	}
+	secretMap, err = utils.ConvertKeys(conversion, secretMap)
+	if err != nil {
+		return fetchResult{err: fmt.Errorf(errConvert, err)}
+	}
+	secretMap, err = utils.DecodeMap(decoding, secretMap)
+	if err != nil {
+		return fetchResult{err: fmt.Errorf(errDecode, "spec.dataFrom", i, err)}
+	}
+	return fetchResult{data: secretMap, version: version}
+}
+
+// fetchData fetches an entry of spec.data and applies its decoding strategy.
+func fetchData(ctx context.Context, stores storeClients, es *esv1beta1.ExternalSecret, i int) fetchResult {
+	secretRef := es.Spec.Data[i]
+	field := fmt.Sprintf("spec.data[%d]", i)
+	sc, err := stores.get(dataStoreRef(es, secretRef))
+	if err != nil {
+		return fetchResult{err: &entryError{field: field, err: err}}
+	}
+	secretData, err := sc.client.GetSecret(ctx, secretRef.RemoteRef)
+	if isNotFound(es, err) {
+		return fetchResult{notFound: true}
+	}
+	if err != nil {
+		return fetchResult{err: &entryError{field: field, err: &storeError{ref: sc.ref, err: err}}}
+	}
+	version, err := esv1beta1.GetSecretVersion(ctx, sc.client, secretRef.RemoteRef)
+	if err != nil {
+		return fetchResult{err: &entryError{field: field, err: &storeError{ref: sc.ref, err: err}}}
+	}
+	secretData, err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
+	if err != nil {
+		return fetchResult{err: fmt.Errorf(errDecode, "spec.data", i, err)}
+	}
+	return fetchResult{value: secretData, version: version}
+}
+
+// fetchEntries fetches all entries which refer to a store concurrently.
+// The results are indexed like spec.dataFrom and spec.data.
+func (r *Reconciler) fetchEntries(ctx context.Context, stores storeClients, es *esv1beta1.ExternalSecret) ([]fetchResult, []fetchResult) {
+	dataFrom := make([]fetchResult, len(es.Spec.DataFrom))
+	data := make([]fetchResult, len(es.Spec.Data))
+	jobs := make([]func(), 0, len(dataFrom)+len(data))
+	for i, remoteRef := range es.Spec.DataFrom {
+		if remoteRef.Find == nil && remoteRef.Extract == nil {
+			continue
+		}
+		i := i
+		jobs = append(jobs, func() {
+			dataFrom[i] = fetchDataFrom(ctx, stores, es, i)
+		})
+	}
+	for i := range es.Spec.Data {
+		i := i
+		jobs = append(jobs, func() {
+			data[i] = fetchData(ctx, stores, es, i)
+		})
+	}
+	runConcurrently(r.MaxConcurrentFetches, jobs)
+	return dataFrom, data
+}
@@ -0,0 +1,292 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+
+	// Loading registered generators.
+	_ "github.com/external-secrets/external-secrets/pkg/generator/register"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	generatorStateSuffix = "-generator-state"
+
+	// generatorRenewBefore is the time before their expiry
+	// at which generated values are renewed.
+	generatorRenewBefore = 10 * time.Minute
+
+	errGeneratorKind       = "generator kind %q is not supported"
+	errGetGenerator        = "could not get generator %s %q: %w"
+	errGenerate            = "could not generate secret data with %s %q: %w"
+	errGetGeneratorState   = "could not get generator state: %w"
+	errSaveGeneratorState  = "could not save generator state: %w"
+	errGeneratorStateOwner = "secret %q is not owned by the ExternalSecret"
+	errGeneratorExpiry     = "could not get expiry of generated values"
+)
+
+// generatorState holds the values created by the generators of an ExternalSecret.
+// It is stored in a Secret owned by the ExternalSecret, so the values
+// are reused on every refresh until a rotation is due. A Secret of the same
+// name which is not owned by the ExternalSecret is neither read nor written.
+type generatorState struct {
+	secret *v1.Secret
+	hash   string
+	dirty  bool
+	// expiry is the earliest expiry of the values, zero if none of them expire.
+	expiry time.Time
+}
+
+// generatorStateHash changes whenever the generated values must be rotated,
+// i.e. if the spec of the ExternalSecret or its rotation annotation changes.
+func generatorStateHash(es *esv1beta1.ExternalSecret) string {
+	return utils.ObjectHash(map[string]string{
+		"generation": strconv.FormatInt(es.Generation, 10),
+		"rotate":     es.Annotations[esv1beta1.AnnotationRotateGenerators],
+	})
+}
+
+func generatorStateKeyPrefix(i int) string {
+	return fmt.Sprintf("dataFrom.%d.", i)
+}
+
+// get returns the values stored for dataFrom[i] or nil if there are none.
+func (s *generatorState) get(i int) map[string][]byte {
+	prefix := generatorStateKeyPrefix(i)
+	var data map[string][]byte
+	for k, v := range s.secret.Data {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if data == nil {
+			data = make(map[string][]byte)
+		}
+		data[strings.TrimPrefix(k, prefix)] = v
+	}
+	return data
+}
+
+// set stores the values generated for dataFrom[i].
+func (s *generatorState) set(i int, data map[string][]byte) {
+	if s.secret.Data == nil {
+		s.secret.Data = make(map[string][]byte)
+	}
+	prefix := generatorStateKeyPrefix(i)
+	for k, v := range data {
+		s.secret.Data[prefix+k] = v
+	}
+	s.dirty = true
+}
+
+// observeExpiry records the expiry of a set of values.
+func (s *generatorState) observeExpiry(expiry time.Time) {
+	if expiry.IsZero() {
+		return
+	}
+	if s.expiry.IsZero() || expiry.Before(s.expiry) {
+		s.expiry = expiry
+	}
+}
+
+// getGeneratorState reads the generator state of the ExternalSecret.
+// Previously generated values are discarded if a rotation is due.
+func (r *Reconciler) getGeneratorState(ctx context.Context, es *esv1beta1.ExternalSecret) (*generatorState, error) {
+	state := &generatorState{
+		hash: generatorStateHash(es),
+		secret: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      es.Name + generatorStateSuffix,
+				Namespace: es.Namespace,
+			},
+		},
+	}
+	err := r.Get(ctx, client.ObjectKeyFromObject(state.secret), state.secret)
+	if apierrors.IsNotFound(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGetGeneratorState, err)
+	}
+	if !metav1.IsControlledBy(state.secret, es) {
+		return nil, fmt.Errorf(errGetGeneratorState, fmt.Errorf(errGeneratorStateOwner, state.secret.Name))
+	}
+	if state.secret.Annotations[esv1beta1.AnnotationGeneratorState] != state.hash {
+		state.secret.Data = nil
+	}
+	return state, nil
+}
+
+// saveGeneratorState persists the state if new values have been generated.
+func (r *Reconciler) saveGeneratorState(ctx context.Context, es *esv1beta1.ExternalSecret, state *generatorState) error {
+	if state == nil || !state.dirty {
+		return nil
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      state.secret.Name,
+			Namespace: state.secret.Namespace,
+		},
+	}
+	_, err := ctrl.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		// the Secret may have been created since the state was read
+		if secret.ResourceVersion != "" && !metav1.IsControlledBy(secret, es) {
+			return fmt.Errorf(errGeneratorStateOwner, secret.Name)
+		}
+		secret.Data = state.secret.Data
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		secret.Annotations[esv1beta1.AnnotationGeneratorState] = state.hash
+		if state.expiry.IsZero() {
+			delete(secret.Annotations, esv1beta1.AnnotationGeneratorExpiry)
+		} else {
+			secret.Annotations[esv1beta1.AnnotationGeneratorExpiry] = state.expiry.UTC().Format(time.RFC3339)
+		}
+		return controllerutil.SetControllerReference(es, &secret.ObjectMeta, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf(errSaveGeneratorState, err)
+	}
+	state.dirty = false
+	return nil
+}
+
+// getGeneratorData returns the values of the generator referenced by dataFrom[i].
+// Values stored in the state are reused, the generator is only called
+// if there are none or if they are about to expire.
+func (r *Reconciler) getGeneratorData(ctx context.Context, es *esv1beta1.ExternalSecret, i int, ref *esv1beta1.GeneratorRef, state *generatorState) (map[string][]byte, error) {
+	gen, ok := genv1alpha1.GetGeneratorByName(ref.Kind)
+	if !ok {
+		return nil, fmt.Errorf(errGeneratorKind, ref.Kind)
+	}
+	if data := state.get(i); data != nil {
+		expiry, err := generatedDataExpiry(gen, data)
+		if err != nil {
+			return nil, fmt.Errorf(errGenerate, ref.Kind, ref.Name, err)
+		}
+		if !generatorRenewalDue(expiry) {
+			state.observeExpiry(expiry)
+			return data, nil
+		}
+	}
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = genv1alpha1.SchemeGroupVersion.String()
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(ref.Kind)
+	err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: es.Namespace}, obj)
+	if err != nil {
+		return nil, fmt.Errorf(errGetGenerator, ref.Kind, ref.Name, err)
+	}
+	raw, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf(errGetGenerator, ref.Kind, ref.Name, err)
+	}
+	data, err := gen.Generate(ctx, &apiextensions.JSON{Raw: raw}, r.Client, es.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errGenerate, ref.Kind, ref.Name, err)
+	}
+	expiry, err := generatedDataExpiry(gen, data)
+	if err != nil {
+		return nil, fmt.Errorf(errGenerate, ref.Kind, ref.Name, err)
+	}
+	state.observeExpiry(expiry)
+	state.set(i, data)
+	return data, nil
+}
+
+// generatedDataExpiry returns the expiry of values created by gen
+// or the zero time if they do not expire.
+func generatedDataExpiry(gen genv1alpha1.Generator, data map[string][]byte) (time.Time, error) {
+	expiring, ok := gen.(genv1alpha1.ExpiringGenerator)
+	if !ok {
+		return time.Time{}, nil
+	}
+	return expiring.ExpiresAt(data)
+}
+
+// generatorRenewalDue returns true if values with the given expiry must be renewed.
+func generatorRenewalDue(expiry time.Time) bool {
+	return !expiry.IsZero() && time.Now().Add(generatorRenewBefore).After(expiry)
+}
+
+func hasGenerators(es *esv1beta1.ExternalSecret) bool {
+	for _, ref := range es.Spec.DataFrom {
+		if ref.SourceRef != nil && ref.SourceRef.GeneratorRef != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getGeneratorExpiry returns the earliest expiry of the values created by the
+// generators of the ExternalSecret or the zero time if none of them expire.
+// The values are considered expired if the state can not be read.
+func (r *Reconciler) getGeneratorExpiry(ctx context.Context, es *esv1beta1.ExternalSecret) time.Time {
+	if !hasGenerators(es) {
+		return time.Time{}
+	}
+	state, err := r.getGeneratorState(ctx, es)
+	if err != nil {
+		r.Log.Error(err, errGeneratorExpiry)
+		return time.Now()
+	}
+	raw, ok := state.secret.Annotations[esv1beta1.AnnotationGeneratorExpiry]
+	if !ok {
+		return time.Time{}
+	}
+	expiry, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		r.Log.Error(err, errGeneratorExpiry)
+		return time.Now()
+	}
+	return expiry
+}
+
+// requeueBeforeExpiry shortens the refresh interval,
+// so that generated values are renewed before they expire.
+func requeueBeforeExpiry(refreshInt time.Duration, expiry time.Time) time.Duration {
+	if expiry.IsZero() {
+		return refreshInt
+	}
+	renewIn := time.Until(expiry.Add(-generatorRenewBefore))
+	if renewIn < requeueAfter {
+		renewIn = requeueAfter
+	}
+	if refreshInt == 0 || renewIn < refreshInt {
+		return renewIn
+	}
+	return refreshInt
+}
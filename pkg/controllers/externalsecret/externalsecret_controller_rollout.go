@@ -0,0 +1,153 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	errUnknownRolloutKind = "unknown rollout target kind %q"
+	errGetRolloutTarget   = "could not get %s %q: %w"
+	errListRolloutTargets = "could not list %s: %w"
+	errPatchRolloutTarget = "could not restart %s %q: %w"
+	errRolloutTargets     = "could not restart workloads"
+)
+
+// rolloutWorkloads restarts the workloads referenced in spec.target.rolloutTargets
+// and records the outcome as event on the ExternalSecret.
+func (r *Reconciler) rolloutWorkloads(ctx context.Context, log logr.Logger, es *esv1beta1.ExternalSecret, oldHash, newHash string) {
+	restarted, err := r.restartWorkloads(ctx, es, oldHash, newHash)
+	if len(restarted) > 0 {
+		log.Info("restarted workloads", "workloads", restarted)
+		r.recorder.Eventf(es, v1.EventTypeNormal, esv1beta1.ReasonRolloutTriggered, "Restarted %s", strings.Join(restarted, ", "))
+	}
+	if err != nil {
+		log.Error(err, errRolloutTargets)
+		r.recorder.Event(es, v1.EventTypeWarning, esv1beta1.ReasonRolloutFailed, err.Error())
+	}
+}
+
+// restartWorkloads sets the data hash of the target as pod template
+// annotation. A workload is restarted if the data has changed with this
+// sync, or if it has been restarted before and missed a later change.
+// Workloads are not restarted when the target has just been created.
+func (r *Reconciler) restartWorkloads(ctx context.Context, es *esv1beta1.ExternalSecret, oldHash, newHash string) ([]string, error) {
+	if len(es.Spec.Target.RolloutTargets) == 0 || newHash == "" {
+		return nil, nil
+	}
+	changed := oldHash != "" && oldHash != newHash
+	var restarted []string
+	seen := make(map[string]bool)
+	for _, target := range es.Spec.Target.RolloutTargets {
+		workloads, err := r.getRolloutWorkloads(ctx, es.Namespace, target)
+		if err != nil {
+			return restarted, err
+		}
+		for _, workload := range workloads {
+			name := fmt.Sprintf("%s/%s", target.Kind, workload.GetName())
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			template := podTemplate(workload)
+			current, ok := template.Annotations[esv1beta1.AnnotationDataHash]
+			if current == newHash || (!ok && !changed) {
+				continue
+			}
+			patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+			if template.Annotations == nil {
+				template.Annotations = make(map[string]string)
+			}
+			template.Annotations[esv1beta1.AnnotationDataHash] = newHash
+			if err := r.Patch(ctx, workload, patch); err != nil {
+				return restarted, fmt.Errorf(errPatchRolloutTarget, target.Kind, workload.GetName(), err)
+			}
+			restarted = append(restarted, name)
+		}
+	}
+	return restarted, nil
+}
+
+// getRolloutWorkloads returns the workloads selected by a rollout target.
+// Workloads are read from the API server, so that they are not cached by the controller.
+func (r *Reconciler) getRolloutWorkloads(ctx context.Context, namespace string, target esv1beta1.RolloutTarget) ([]client.Object, error) {
+	var (
+		obj  client.Object
+		list client.ObjectList
+	)
+	switch target.Kind {
+	case esv1beta1.RolloutTargetKindDeployment:
+		obj, list = &appsv1.Deployment{}, &appsv1.DeploymentList{}
+	case esv1beta1.RolloutTargetKindStatefulSet:
+		obj, list = &appsv1.StatefulSet{}, &appsv1.StatefulSetList{}
+	case esv1beta1.RolloutTargetKindDaemonSet:
+		obj, list = &appsv1.DaemonSet{}, &appsv1.DaemonSetList{}
+	default:
+		return nil, fmt.Errorf(errUnknownRolloutKind, target.Kind)
+	}
+
+	if target.Name != "" {
+		err := r.apiReader.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: namespace}, obj)
+		if err != nil {
+			return nil, fmt.Errorf(errGetRolloutTarget, target.Kind, target.Name, err)
+		}
+		return []client.Object{obj}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(target.Selector)
+	if err != nil {
+		return nil, err
+	}
+	err = r.apiReader.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, fmt.Errorf(errListRolloutTargets, target.Kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	workloads := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		workloads = append(workloads, item.(client.Object))
+	}
+	return workloads, nil
+}
+
+// podTemplate returns the pod template of a workload.
+func podTemplate(workload client.Object) *v1.PodTemplateSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template
+	}
+	return nil
+}
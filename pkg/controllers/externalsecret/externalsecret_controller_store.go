@@ -0,0 +1,152 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	// storeRefField indexes ExternalSecrets by the stores they refer to.
+	storeRefField = "spec.secretStoreRef"
+
+	// storeEventQPS and storeEventBurst limit how fast the dependents
+	// of a changed store are enqueued, so a ClusterSecretStore used by
+	// thousands of ExternalSecrets does not flood its provider.
+	storeEventQPS   = 10
+	storeEventBurst = 100
+
+	errListDependents = "unable to list ExternalSecrets referring to store"
+)
+
+// storeRefKey returns the index value for a store reference.
+func storeRefKey(kind, name string) string {
+	if kind == "" {
+		kind = esv1beta1.SecretStoreKind
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// indexStoreRef returns the stores an ExternalSecret refers to,
+// including the stores referenced by individual data and dataFrom entries.
+func indexStoreRef(obj client.Object) []string {
+	es, ok := obj.(*esv1beta1.ExternalSecret)
+	if !ok {
+		return nil
+	}
+	refs := storeRefs(es)
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, storeRefKey(ref.Kind, ref.Name))
+	}
+	return keys
+}
+
+// storeChanged returns true if the spec or the Ready condition of a store changed.
+func storeChanged(oldObj, newObj client.Object) bool {
+	if oldObj.GetGeneration() != newObj.GetGeneration() {
+		return true
+	}
+	oldStore, ok := oldObj.(esv1beta1.GenericStore)
+	if !ok {
+		return true
+	}
+	newStore, ok := newObj.(esv1beta1.GenericStore)
+	if !ok {
+		return true
+	}
+	oldReady := secretstore.GetSecretStoreCondition(oldStore.GetStatus(), esv1beta1.SecretStoreReady)
+	newReady := secretstore.GetSecretStoreCondition(newStore.GetStatus(), esv1beta1.SecretStoreReady)
+	if oldReady == nil || newReady == nil {
+		return oldReady != newReady
+	}
+	return oldReady.Status != newReady.Status
+}
+
+// storeEventHandler enqueues the ExternalSecrets which depend on a store
+// whenever the store is created, deleted or its spec or Ready condition changes.
+// Requests are delayed by a token bucket shared by all stores.
+type storeEventHandler struct {
+	reader  client.Reader
+	limiter workqueue.RateLimiter
+	kind    string
+	log     logr.Logger
+}
+
+// newStoreEventHandler returns a handler for stores of the given kind.
+// The reader must support the storeRefField index, i.e. it must be the cache.
+func newStoreEventHandler(reader client.Reader, log logr.Logger, kind string, limiter workqueue.RateLimiter) handler.EventHandler {
+	return &storeEventHandler{
+		reader:  reader,
+		limiter: limiter,
+		kind:    kind,
+		log:     log,
+	}
+}
+
+func newStoreEventLimiter() workqueue.RateLimiter {
+	return &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(storeEventQPS), storeEventBurst)}
+}
+
+func (h *storeEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueueDependents(e.Object, q)
+}
+
+func (h *storeEventHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if !storeChanged(e.ObjectOld, e.ObjectNew) {
+		return
+	}
+	h.enqueueDependents(e.ObjectNew, q)
+}
+
+func (h *storeEventHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.enqueueDependents(e.Object, q)
+}
+
+func (h *storeEventHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {}
+
+func (h *storeEventHandler) enqueueDependents(store client.Object, q workqueue.RateLimitingInterface) {
+	opts := []client.ListOption{
+		client.MatchingFields{storeRefField: storeRefKey(h.kind, store.GetName())},
+	}
+	if h.kind == esv1beta1.SecretStoreKind {
+		opts = append(opts, client.InNamespace(store.GetNamespace()))
+	}
+	var list esv1beta1.ExternalSecretList
+	if err := h.reader.List(context.Background(), &list, opts...); err != nil {
+		h.log.Error(err, errListDependents, "kind", h.kind, "name", store.GetName(), "namespace", store.GetNamespace())
+		return
+	}
+	for i := range list.Items {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      list.Items[i].Name,
+			Namespace: list.Items[i].Namespace,
+		}}
+		q.AddAfter(req, h.limiter.When(req))
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// templateSource is the source of keys rendered by target.template.
+const templateSource = "spec.target.template"
+
+// keySources records the entry of the spec every key has been fetched with.
+// Later entries overwrite the keys of earlier ones, like the fetched data.
+type keySources map[string]esv1beta1.SyncedKey
+
+func (s keySources) addDataFrom(i int, ref esv1beta1.ExternalSecretDataFromRemoteRef, data map[string][]byte, version string) {
+	var remoteKey string
+	if ref.Extract != nil {
+		remoteKey = ref.Extract.Key
+	}
+	for k := range data {
+		s[k] = esv1beta1.SyncedKey{
+			Source:    fmt.Sprintf("spec.dataFrom[%d]", i),
+			RemoteKey: remoteKey,
+			Version:   version,
+		}
+	}
+}
+
+func (s keySources) addData(i int, ref esv1beta1.ExternalSecretData, version string) {
+	s[ref.SecretKey] = esv1beta1.SyncedKey{
+		Source:    fmt.Sprintf("spec.data[%d]", i),
+		RemoteKey: ref.RemoteRef.Key,
+		Version:   version,
+	}
+}
+
+// addTemplate records the keys rendered by target.template,
+// they replace the fetched data in the target.
+func (s keySources) addTemplate(data map[string][]byte) {
+	for k := range data {
+		s[k] = esv1beta1.SyncedKey{
+			Source: templateSource,
+		}
+	}
+}
+
+// syncedKeys returns the status entries of the target data sorted by key.
+// Values are hashed with the UID of the ExternalSecret, so that equal values
+// of different ExternalSecrets can not be correlated.
+func (s keySources) syncedKeys(uid types.UID, data map[string][]byte) []esv1beta1.SyncedKey {
+	if len(data) == 0 {
+		return nil
+	}
+	keys := make([]esv1beta1.SyncedKey, 0, len(data))
+	for k, v := range data {
+		synced := s[k]
+		synced.Key = k
+		synced.Hash = hashValue(uid, v)
+		keys = append(keys, synced)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+	return keys
+}
+
+func hashValue(uid types.UID, value []byte) string {
+	h := sha256.New()
+	h.Write([]byte(uid))
+	h.Write(value)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+// The target of an ExternalSecret is always rendered into a Secret.
+// If the target kind is ConfigMap the rendered Secret is copied to
+// a ConfigMap before it is written, and an existing ConfigMap is read
+// back as a Secret, so that creation and deletion policies, templates
+// and the data hash work the same way for both kinds.
+
+// isConfigMapTarget returns true if the ExternalSecret manages a ConfigMap.
+func isConfigMapTarget(es *esv1beta1.ExternalSecret) bool {
+	return es.Spec.Target.Kind == esv1beta1.TargetKindConfigMap
+}
+
+// getExistingTarget fetches the target resource.
+// A ConfigMap is returned as a Secret holding its data and binaryData.
+func (r *Reconciler) getExistingTarget(ctx context.Context, es *esv1beta1.ExternalSecret, name string) (v1.Secret, error) {
+	key := types.NamespacedName{Name: name, Namespace: es.Namespace}
+	var secret v1.Secret
+	if !isConfigMapTarget(es) {
+		err := r.Get(ctx, key, &secret)
+		return secret, err
+	}
+	var cm v1.ConfigMap
+	if err := r.Get(ctx, key, &cm); err != nil {
+		return secret, err
+	}
+	secret.ObjectMeta = cm.ObjectMeta
+	secret.Immutable = cm.Immutable
+	secret.Data = configMapData(&cm)
+	return secret, nil
+}
+
+// newTargetObject returns an empty object of the target kind.
+func newTargetObject(es *esv1beta1.ExternalSecret, secret *v1.Secret) client.Object {
+	meta := metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace}
+	if isConfigMapTarget(es) {
+		return &v1.ConfigMap{ObjectMeta: meta}
+	}
+	return &v1.Secret{ObjectMeta: meta}
+}
+
+// configMapMutation returns a mutation func for a ConfigMap target.
+// It runs the Secret based mutation on the rendered secret
+// and copies the result to the ConfigMap.
+func configMapMutation(secret *v1.Secret, cm *v1.ConfigMap, mutate func() error) func() error {
+	return func() error {
+		secret.ObjectMeta = *cm.ObjectMeta.DeepCopy()
+		secret.Data = configMapData(cm)
+		if err := mutate(); err != nil {
+			return err
+		}
+		cm.ObjectMeta = secret.ObjectMeta
+		cm.Immutable = secret.Immutable
+		setConfigMapData(cm, secret.Data)
+		// nil values are not written, the hash has to match the written data
+		cm.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(configMapData(cm))
+		return nil
+	}
+}
+
+// configMapData returns data and binaryData of a ConfigMap as one map.
+func configMapData(cm *v1.ConfigMap) map[string][]byte {
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+	return data
+}
+
+// setConfigMapData writes values which are valid UTF-8 to data
+// and all other values to binaryData.
+func setConfigMapData(cm *v1.ConfigMap, data map[string][]byte) {
+	cm.Data = make(map[string]string)
+	cm.BinaryData = make(map[string][]byte)
+	for k, v := range data {
+		if v == nil {
+			continue
+		}
+		if utf8.Valid(v) {
+			cm.Data[k] = string(v)
+			continue
+		}
+		cm.BinaryData[k] = v
+	}
+}
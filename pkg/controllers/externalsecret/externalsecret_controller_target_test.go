@@ -0,0 +1,97 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+func TestGetManagedKeys(t *testing.T) {
+	managedFields := func(manager, fields string) []metav1.ManagedFieldsEntry {
+		return []metav1.ManagedFieldsEntry{{
+			Manager:  manager,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(fields)},
+		}}
+	}
+	owner := fmt.Sprintf(fieldOwnerTemplate, "es")
+	tbl := []struct {
+		test    string
+		fields  []metav1.ManagedFieldsEntry
+		expKeys []string
+	}{
+		{
+			test:    "should return the keys of a Secret",
+			fields:  managedFields(owner, `{"f:data":{".":{},"f:foo":{},"f:bar":{}}}`),
+			expKeys: []string{"bar", "foo"},
+		},
+		{
+			test:    "should return the data and binaryData keys of a ConfigMap",
+			fields:  managedFields(owner, `{"f:data":{".":{},"f:foo":{}},"f:binaryData":{".":{},"f:bin":{}}}`),
+			expKeys: []string{"bin", "foo"},
+		},
+		{
+			test:   "should ignore keys of other managers",
+			fields: managedFields("other", `{"f:data":{"f:foo":{}},"f:binaryData":{"f:bin":{}}}`),
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{ManagedFields: row.fields}}
+			keys, err := getManagedKeys(secret, "es")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sort.Strings(keys)
+			if diff := cmp.Diff(row.expKeys, keys); diff != "" {
+				t.Errorf("unexpected keys (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigMapMutationHash(t *testing.T) {
+	secret := &v1.Secret{}
+	cm := &v1.ConfigMap{}
+	mutate := configMapMutation(secret, cm, func() error {
+		secret.Annotations = map[string]string{}
+		secret.Data = map[string][]byte{
+			"foo":     []byte("bar"),
+			"bin":     {0xff, 0xfe},
+			"removed": nil,
+		}
+		secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
+		return nil
+	})
+	if err := mutate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cm.Data["removed"]; ok {
+		t.Errorf("nil value must not be written")
+	}
+	// the target is only valid if the hash matches the data read back
+	if got, want := cm.Annotations[esv1beta1.AnnotationDataHash], utils.ObjectHash(configMapData(cm)); got != want {
+		t.Errorf("hash of the written data: got %s, want %s", got, want)
+	}
+}
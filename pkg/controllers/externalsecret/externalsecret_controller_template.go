@@ -19,8 +19,11 @@ import (
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 
@@ -34,20 +37,29 @@ import (
 // * template.Data (highest precedence)
 // * template.templateFrom
 // * secret via es.data or es.dataFrom.
-func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte) error {
+// The data rendered by the template is returned, it is nil if the
+// fetched data has been copied to the secret as it is.
+func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte) (map[string][]byte, error) {
 	mergeMetadata(secret, es)
 
 	// no template: copy data and return
 	if es.Spec.Target.Template == nil {
 		secret.Data = dataMap
 		secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
-		return nil
+		return nil, nil
 	}
 
 	// fetch templates defined in template.templateFrom
 	tplMap, err := r.getTemplateData(ctx, es)
 	if err != nil {
-		return fmt.Errorf(errFetchTplFrom, err)
+		return nil, fmt.Errorf(errFetchTplFrom, err)
+	}
+	if len(es.Spec.Target.Template.TemplateFrom) > 0 {
+		tplHash, err := r.templateFromHash(ctx, es)
+		if err != nil {
+			return nil, fmt.Errorf(errFetchTplFrom, err)
+		}
+		secret.Annotations[esv1beta1.AnnotationTemplateHash] = tplHash
 	}
 
 	// explicitly defined template.Data takes precedence over templateFrom
@@ -58,21 +70,27 @@ func (r *Reconciler) applyTemplate(ctx context.Context, es *esv1beta1.ExternalSe
 
 	execute, err := template.EngineForVersion(es.Spec.Target.Template.EngineVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = execute(tplMap, dataMap, secret)
 	if err != nil {
-		return fmt.Errorf(errExecTpl, err)
+		return nil, fmt.Errorf(errExecTpl, err)
 	}
 
 	// if no data was provided by template fallback
 	// to value from the provider
 	if len(es.Spec.Target.Template.Data) == 0 && len(es.Spec.Target.Template.TemplateFrom) == 0 {
 		secret.Data = dataMap
+		secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
+		return nil, nil
 	}
 	secret.Annotations[esv1beta1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
 
-	return nil
+	rendered := make(map[string][]byte, len(tplMap))
+	for k := range tplMap {
+		rendered[k] = secret.Data[k]
+	}
+	return rendered, nil
 }
 
 // we do not want to force-override the label/annotations
@@ -157,3 +175,112 @@ func mergeSecret(ctx context.Context, k8sClient client.Client, es *esv1beta1.Ext
 	}
 	return nil
 }
+
+const (
+	// templateFromField indexes ExternalSecrets by the
+	// ConfigMaps and Secrets they use in templateFrom.
+	templateFromField = "spec.target.template.templateFrom"
+
+	errListTplDependents = "unable to list ExternalSecrets using template"
+	errTplFromVersion    = "could not get resource versions of templateFrom"
+)
+
+// templateFromKey returns the index value for a templateFrom source.
+func templateFromKey(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// indexTemplateFrom returns the ConfigMaps and Secrets
+// referenced by the templateFrom of an ExternalSecret.
+func indexTemplateFrom(obj client.Object) []string {
+	es, ok := obj.(*esv1beta1.ExternalSecret)
+	if !ok || es.Spec.Target.Template == nil {
+		return nil
+	}
+	var keys []string
+	for _, tpl := range es.Spec.Target.Template.TemplateFrom {
+		if tpl.ConfigMap != nil {
+			keys = append(keys, templateFromKey("ConfigMap", tpl.ConfigMap.Name))
+		}
+		if tpl.Secret != nil {
+			keys = append(keys, templateFromKey("Secret", tpl.Secret.Name))
+		}
+	}
+	return keys
+}
+
+// findExternalSecretsForTemplate returns a map func which enqueues
+// all ExternalSecrets using the given ConfigMap or Secret in templateFrom.
+func (r *Reconciler) findExternalSecretsForTemplate(kind string) func(client.Object) []reconcile.Request {
+	return func(obj client.Object) []reconcile.Request {
+		var list esv1beta1.ExternalSecretList
+		err := r.metadataReader.List(context.Background(), &list,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{templateFromField: templateFromKey(kind, obj.GetName())})
+		if err != nil {
+			r.Log.Error(err, errListTplDependents, "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(list.Items))
+		for i := range list.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      list.Items[i].Name,
+				Namespace: list.Items[i].Namespace,
+			}})
+		}
+		return requests
+	}
+}
+
+// templateFromHash returns a hash of the resource versions of all ConfigMaps
+// and Secrets referenced by templateFrom. Only their metadata is read, which
+// is served by the metadata-only watches even if caching is disabled.
+func (r *Reconciler) templateFromHash(ctx context.Context, es *esv1beta1.ExternalSecret) (string, error) {
+	versions := make(map[string]string)
+	for _, tpl := range es.Spec.Target.Template.TemplateFrom {
+		if tpl.ConfigMap != nil {
+			rv, err := r.getTemplateSourceVersion(ctx, "ConfigMap", es.Namespace, tpl.ConfigMap.Name)
+			if err != nil {
+				return "", err
+			}
+			versions[templateFromKey("ConfigMap", tpl.ConfigMap.Name)] = rv
+		}
+		if tpl.Secret != nil {
+			rv, err := r.getTemplateSourceVersion(ctx, "Secret", es.Namespace, tpl.Secret.Name)
+			if err != nil {
+				return "", err
+			}
+			versions[templateFromKey("Secret", tpl.Secret.Name)] = rv
+		}
+	}
+	return utils.ObjectHash(versions), nil
+}
+
+// getTemplateSourceVersion returns the resource version of a ConfigMap
+// or Secret or an empty string if it does not exist.
+func (r *Reconciler) getTemplateSourceVersion(ctx context.Context, kind, namespace, name string) (string, error) {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind(kind))
+	err := r.metadataReader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return obj.GetResourceVersion(), nil
+}
+
+// templateFromChanged returns true if a ConfigMap or Secret used
+// in templateFrom changed since the target secret was rendered.
+func (r *Reconciler) templateFromChanged(ctx context.Context, es *esv1beta1.ExternalSecret, existingSecret *v1.Secret) bool {
+	if es.Spec.Target.Template == nil || len(es.Spec.Target.Template.TemplateFrom) == 0 {
+		return false
+	}
+	hash, err := r.templateFromHash(ctx, es)
+	if err != nil {
+		r.Log.Error(err, errTplFromVersion)
+		return true
+	}
+	return existingSecret.Annotations[esv1beta1.AnnotationTemplateHash] != hash
+}
@@ -24,15 +24,19 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	ctest "github.com/external-secrets/external-secrets/pkg/controllers/commontest"
 	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 var (
@@ -64,6 +68,7 @@ var _ = Describe("Kind=secret existence logic", func() {
 	type testCase struct {
 		Name           string
 		Input          v1.Secret
+		ExternalSecret esv1beta1.ExternalSecret
 		ExpectedOutput bool
 	}
 	tests := []testCase{
@@ -120,11 +125,35 @@ var _ = Describe("Kind=secret existence logic", func() {
 			},
 			ExpectedOutput: true,
 		},
+		{
+			Name: "An invalid annotation hash should be valid with refreshPolicy=CreatedOnce",
+			Input: v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "xxx",
+					Annotations: map[string]string{
+						esv1beta1.AnnotationDataHash: "xxxxxx",
+					},
+				},
+			},
+			ExternalSecret: esv1beta1.ExternalSecret{
+				Spec: esv1beta1.ExternalSecretSpec{RefreshPolicy: esv1beta1.RefreshPolicyCreatedOnce},
+			},
+			ExpectedOutput: true,
+		},
+		{
+			Name:  "A missing secret should not be valid with refreshPolicy=CreatedOnce",
+			Input: v1.Secret{},
+			ExternalSecret: esv1beta1.ExternalSecret{
+				Spec: esv1beta1.ExternalSecretSpec{RefreshPolicy: esv1beta1.RefreshPolicyCreatedOnce},
+			},
+			ExpectedOutput: false,
+		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		It(tt.Name, func() {
-			Expect(isSecretValid(tt.Input)).To(BeEquivalentTo(tt.ExpectedOutput))
+			Expect(isSecretValid(tt.Input, tt.ExternalSecret)).To(BeEquivalentTo(tt.ExpectedOutput))
 		})
 	}
 })
@@ -564,6 +593,57 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// a change of a templateFrom source must re-render the secret
+	// without waiting for the refresh interval
+	refreshOnTemplateFromChange := func(tc *testCase) {
+		const secretVal = "someValue"
+		const tplFromCMName = "template-cm"
+		const tplFromKey = "tpl-from-key"
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tplFromCMName,
+				Namespace: ExternalSecretNamespace,
+			},
+			Data: map[string]string{
+				tplFromKey: "old: {{ .targetProperty | toString }}",
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), cm)).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			Type: v1.SecretTypeOpaque,
+			TemplateFrom: []esv1beta1.TemplateFrom{
+				{
+					ConfigMap: &esv1beta1.TemplateRef{
+						Name: tplFromCMName,
+						Items: []esv1beta1.TemplateRefItem{
+							{
+								Key: tplFromKey,
+							},
+						},
+					},
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(secretVal), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[tplFromKey])).To(Equal("old: " + secretVal))
+			Expect(secret.Annotations).To(HaveKey(esv1beta1.AnnotationTemplateHash))
+
+			cm.Data[tplFromKey] = "new: {{ .targetProperty | toString }}"
+			Expect(k8sClient.Update(context.Background(), cm)).To(Succeed())
+
+			sec := &v1.Secret{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), types.NamespacedName{
+					Name:      ExternalSecretTargetSecretName,
+					Namespace: ExternalSecretNamespace,
+				}, sec)
+				return err == nil && string(sec.Data[tplFromKey]) == "new: "+secretVal
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
 	refreshWithTemplate := func(tc *testCase) {
 		const secretVal = "someValue"
 		const tplStaticKey = "tplstatickey"
@@ -967,6 +1047,66 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// the status lists the entry every key has been fetched with,
+	// data entries take precedence over dataFrom entries
+	syncedKeysStatus := func(tc *testCase) {
+		tc.externalSecret.Spec.Data[0].SecretKey = "foo"
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: "extracted",
+				},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"foo": []byte(BarValue),
+			"bar": []byte(BarValue),
+		}, nil)
+		fakeProvider.WithGetSecretVersion("v2")
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["foo"])).To(Equal(FooValue))
+			Expect(es.Status.SyncedKeys).To(HaveLen(2))
+			Expect(es.Status.SyncedKeys[0]).To(Equal(esv1beta1.SyncedKey{
+				Key:       "bar",
+				Source:    "spec.dataFrom[0]",
+				RemoteKey: "extracted",
+				Version:   "v2",
+				Hash:      hashValue(es.UID, []byte(BarValue)),
+			}))
+			Expect(es.Status.SyncedKeys[1]).To(Equal(esv1beta1.SyncedKey{
+				Key:       "foo",
+				Source:    "spec.data[0]",
+				RemoteKey: remoteKey,
+				Version:   "v2",
+				Hash:      hashValue(es.UID, []byte(FooValue)),
+			}))
+			// values are salted with the UID of the ExternalSecret
+			Expect(es.Status.SyncedKeys[0].Hash).NotTo(Equal(hashValue("", []byte(BarValue))))
+		}
+	}
+
+	// with a template the status lists the keys rendered into the target
+	syncedKeysTemplateStatus := func(tc *testCase) {
+		tc.externalSecret.Spec.Target.Template = &esv1beta1.ExternalSecretTemplate{
+			Type: v1.SecretTypeOpaque,
+			Data: map[string]string{
+				"greeting": "hello {{ .targetProperty }}",
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data["greeting"])).To(Equal("hello " + FooValue))
+			Expect(es.Status.SyncedKeys).To(Equal([]esv1beta1.SyncedKey{
+				{
+					Key:    "greeting",
+					Source: "spec.target.template",
+					Hash:   hashValue(es.UID, []byte("hello "+FooValue)),
+				},
+			}))
+		}
+	}
+
 	// with dataFrom.Find the change is on the called method GetAllSecrets
 	// all keys should be put into the secret
 	syncDataFromFind := func(tc *testCase) {
@@ -991,6 +1131,238 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// with dataFrom.Rewrite the keys are rewritten
+	// before they are put into the secret
+	syncDataFromRewrite := func(tc *testCase) {
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Find: &esv1beta1.ExternalSecretFind{
+					Name: &esv1beta1.FindName{
+						RegExp: "foobar",
+					},
+				},
+				Rewrite: []esv1beta1.ExternalSecretRewrite{
+					{
+						Regexp: &esv1beta1.ExternalSecretRewriteRegexp{
+							Source: "my/path/(.*)",
+							Target: "$1",
+						},
+					},
+					{
+						Transform: &esv1beta1.ExternalSecretRewriteTransform{
+							Template: "prefix-{{ .value }}",
+						},
+					},
+				},
+			},
+		}
+		fakeProvider.WithGetAllSecrets(map[string][]byte{
+			"my/path/foo": []byte(FooValue),
+			"my/path/bar": []byte(BarValue),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			// check values
+			Expect(string(secret.Data["prefix-foo"])).To(Equal(FooValue))
+			Expect(string(secret.Data["prefix-bar"])).To(Equal(BarValue))
+		}
+	}
+
+	// when rewritten keys collide with keys of another dataFrom entry
+	// a key collision condition must be set.
+	dataFromRewriteCollision := func(tc *testCase) {
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{
+					Key: remoteKey,
+				},
+			},
+			{
+				Find: &esv1beta1.ExternalSecretFind{
+					Name: &esv1beta1.FindName{
+						RegExp: "foobar",
+					},
+				},
+				Rewrite: []esv1beta1.ExternalSecretRewrite{
+					{
+						Regexp: &esv1beta1.ExternalSecretRewriteRegexp{
+							Source: "my/path/(.*)",
+							Target: "$1",
+						},
+					},
+				},
+			},
+		}
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"foo": []byte(FooValue),
+		}, nil)
+		fakeProvider.WithGetAllSecrets(map[string][]byte{
+			"my/path/foo": []byte(BarValue),
+		}, nil)
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretKeyCollision {
+				return false
+			}
+			return true
+		}
+	}
+
+	// with dataFrom.sourceRef.generatorRef the generated values
+	// must be kept on refresh and only change on rotation
+	syncWithGenerator := func(tc *testCase) {
+		const generatorName = "mypassword"
+		Expect(k8sClient.Create(context.Background(), &genv1alpha1.Password{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generatorName,
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.PasswordSpec{
+				Length: 32,
+			},
+		})).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+						Kind:       genv1alpha1.PasswordKind,
+						Name:       generatorName,
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			password := secret.Data["password"]
+			Expect(password).To(HaveLen(32))
+
+			// wait for the next refresh, the password must not change
+			esKey := types.NamespacedName{Name: ExternalSecretName, Namespace: ExternalSecretNamespace}
+			refreshTime := es.Status.RefreshTime
+			Eventually(func() bool {
+				var refreshed esv1beta1.ExternalSecret
+				err := k8sClient.Get(context.Background(), esKey, &refreshed)
+				return err == nil && refreshed.Status.RefreshTime.After(refreshTime.Time)
+			}, timeout, interval).Should(BeTrue())
+
+			secretKey := types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}
+			sec := &v1.Secret{}
+			Expect(k8sClient.Get(context.Background(), secretKey, sec)).To(Succeed())
+			Expect(sec.Data["password"]).To(Equal(password))
+
+			// request a rotation
+			var current esv1beta1.ExternalSecret
+			Expect(k8sClient.Get(context.Background(), esKey, &current)).To(Succeed())
+			clean := current.DeepCopy()
+			current.Annotations = map[string]string{
+				esv1beta1.AnnotationRotateGenerators: "1",
+			}
+			Expect(k8sClient.Patch(context.Background(), &current, client.MergeFrom(clean))).To(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), secretKey, sec)
+				return err == nil && len(sec.Data["password"]) == 32 && !bytes.Equal(sec.Data["password"], password)
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	// a Secret which is not owned by the ExternalSecret
+	// must not be used to store generated values
+	generatorStateNotOwned := func(tc *testCase) {
+		const generatorName = "mypassword"
+		Expect(k8sClient.Create(context.Background(), &genv1alpha1.Password{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generatorName,
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.PasswordSpec{
+				Length: 32,
+			},
+		})).To(Succeed())
+		foreign := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ExternalSecretName + generatorStateSuffix,
+				Namespace: ExternalSecretNamespace,
+			},
+			Data: map[string][]byte{
+				"foo": []byte(FooValue),
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), foreign)).To(Succeed())
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+						Kind:       genv1alpha1.PasswordKind,
+						Name:       generatorName,
+					},
+				},
+			},
+		}
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			return cond != nil && cond.Status == v1.ConditionFalse && cond.Reason == esv1beta1.ConditionReasonSecretSyncedError
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			secret := &v1.Secret{}
+			Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(foreign), secret)).To(Succeed())
+			Expect(secret.Data).To(Equal(foreign.Data))
+			Expect(secret.OwnerReferences).To(BeEmpty())
+		}
+	}
+
+	// values of an expiring generator must be renewed
+	// before they expire, regardless of the refresh interval
+	renewExpiringGeneratorValues := func(tc *testCase) {
+		const generatorName = "myregistry"
+		genv1alpha1.ForceRegister(genv1alpha1.ECRAuthorizationTokenKind, &expiringGenerator{})
+		Expect(k8sClient.Create(context.Background(), &genv1alpha1.ECRAuthorizationToken{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      generatorName,
+				Namespace: ExternalSecretNamespace,
+			},
+			Spec: genv1alpha1.ECRAuthorizationTokenSpec{
+				Region: "eu-west-1",
+			},
+		})).To(Succeed())
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				SourceRef: &esv1beta1.SourceRef{
+					GeneratorRef: &esv1beta1.GeneratorRef{
+						APIVersion: genv1alpha1.SchemeGroupVersion.String(),
+						Kind:       genv1alpha1.ECRAuthorizationTokenKind,
+						Name:       generatorName,
+					},
+				},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			// the first token is about to expire and must be renewed right away
+			secretKey := types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}
+			sec := &v1.Secret{}
+			Eventually(func() string {
+				if err := k8sClient.Get(context.Background(), secretKey, sec); err != nil {
+					return ""
+				}
+				return string(sec.Data["token"])
+			}, timeout, interval).Should(Equal("token-2"))
+
+			// the second token is valid long enough
+			Consistently(func() string {
+				if err := k8sClient.Get(context.Background(), secretKey, sec); err != nil {
+					return ""
+				}
+				return string(sec.Data["token"])
+			}, time.Second*2, interval).Should(Equal("token-2"))
+		}
+	}
+
 	// with dataFrom and using a template
 	// should be put into the secret
 	syncWithDataFromTemplate := func(tc *testCase) {
@@ -1106,8 +1478,218 @@ var _ = Describe("ExternalSecret controller", func() {
 		}
 	}
 
+	// when the store is fixed the ExternalSecret must be synced right away
+	// and not only after the requeue interval
+	resyncOnStoreChange := func(tc *testCase) {
+		const secretVal = "someValue"
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		fakeProvider.WithNew(func(context.Context, esv1beta1.GenericStore, client.Client,
+			string) (esv1beta1.SecretsClient, error) {
+			return nil, fmt.Errorf("artificial constructor error")
+		})
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			return cond != nil && cond.Status == v1.ConditionFalse
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			fakeProvider.Reset()
+			fakeProvider.WithGetSecret([]byte(secretVal), nil)
+
+			ctx := context.Background()
+			store := &esv1beta1.SecretStore{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ExternalSecretStore, Namespace: ExternalSecretNamespace}, store)).To(Succeed())
+			store.Spec.Provider.AWS.Region = "eu-central-1"
+			Expect(k8sClient.Update(ctx, store)).To(Succeed())
+
+			secret := &v1.Secret{}
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}, secret)
+				return err == nil && string(secret.Data[targetProp]) == secretVal
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
 	// when a SecretStore has a controller field set which we don't care about
 	// the externalSecret must not be touched
+	// with target kind ConfigMap the values are written to a ConfigMap,
+	// values which are not valid UTF-8 end up in binaryData
+	syncToConfigMap := func(tc *testCase) {
+		binaryValue := []byte{0xff, 0xfe, 0x00}
+		tc.externalSecret.Spec.Target.Kind = esv1beta1.TargetKindConfigMap
+		tc.externalSecret.Spec.Data = nil
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract: &esv1beta1.ExternalSecretDataRemoteRef{Key: remoteKey},
+			},
+		}
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"foo": []byte(FooValue),
+			"bin": binaryValue,
+		}, nil)
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			cm := &v1.ConfigMap{}
+			Eventually(func() error {
+				return k8sClient.Get(context.Background(), types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}, cm)
+			}, timeout, interval).Should(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"foo": FooValue}))
+			Expect(cm.BinaryData).To(Equal(map[string][]byte{"bin": binaryValue}))
+			Expect(cm.Annotations[esv1beta1.AnnotationDataHash]).To(Equal(utils.ObjectHash(configMapData(cm))))
+			Expect(metav1.IsControlledBy(cm, es)).To(BeTrue())
+
+			// no secret is created for a ConfigMap target
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}, &v1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	}
+
+	// workloads in rolloutTargets are restarted when the data changes
+	rolloutOnDataChange := func(tc *testCase) {
+		labels := map[string]string{"app": "rollout"}
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rollout",
+				Namespace: ExternalSecretNamespace,
+				Labels:    labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: "app", Image: "app"}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), deployment)).To(Succeed())
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.externalSecret.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		tc.externalSecret.Spec.Target.RolloutTargets = []esv1beta1.RolloutTarget{
+			{
+				Kind:     esv1beta1.RolloutTargetKindDeployment,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+			},
+		}
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+			deploymentKey := types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}
+
+			// the deployment is not restarted when the secret is created
+			Expect(k8sClient.Get(context.Background(), deploymentKey, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Annotations).ToNot(HaveKey(esv1beta1.AnnotationDataHash))
+
+			fakeProvider.WithGetSecret([]byte(BarValue), nil)
+			secretKey := types.NamespacedName{Name: ExternalSecretTargetSecretName, Namespace: ExternalSecretNamespace}
+			Eventually(func() bool {
+				if err := k8sClient.Get(context.Background(), secretKey, secret); err != nil {
+					return false
+				}
+				if err := k8sClient.Get(context.Background(), deploymentKey, deployment); err != nil {
+					return false
+				}
+				return string(secret.Data[targetProp]) == BarValue &&
+					deployment.Spec.Template.Annotations[esv1beta1.AnnotationDataHash] == secret.Annotations[esv1beta1.AnnotationDataHash]
+			}, timeout, interval).Should(BeTrue())
+		}
+	}
+
+	// entries with a sourceRef.storeRef are fetched from their own store
+	syncFromMultipleStores := func(tc *testCase) {
+		otherStore := tc.secretStore.DeepCopy()
+		otherStore.Name = "other-store"
+		Expect(k8sClient.Create(context.Background(), otherStore)).To(Succeed())
+		storeRef := esv1beta1.SecretStoreRef{Name: otherStore.Name, Kind: esv1beta1.SecretStoreKind}
+		tc.externalSecret.Spec.Data = append(tc.externalSecret.Spec.Data, esv1beta1.ExternalSecretData{
+			SecretKey: "other",
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: remoteKey},
+			SourceRef: &esv1beta1.StoreSourceRef{StoreRef: storeRef},
+		})
+		tc.externalSecret.Spec.DataFrom = []esv1beta1.ExternalSecretDataFromRemoteRef{
+			{
+				Extract:   &esv1beta1.ExternalSecretDataRemoteRef{Key: remoteKey},
+				SourceRef: &esv1beta1.SourceRef{StoreRef: &storeRef},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		fakeProvider.WithGetSecretMap(map[string][]byte{
+			"bar": []byte(BarValue),
+		}, nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+			Expect(string(secret.Data["other"])).To(Equal(FooValue))
+			Expect(string(secret.Data["bar"])).To(Equal(BarValue))
+			Expect(indexStoreRef(es)).To(ConsistOf(
+				storeRefKey(esv1beta1.SecretStoreKind, ExternalSecretStore),
+				storeRefKey(esv1beta1.SecretStoreKind, otherStore.Name),
+			))
+		}
+	}
+
+	// spec.secretStoreRef is not read if every entry uses its own store
+	syncWithUnusedSecretStoreRef := func(tc *testCase) {
+		sourceStore := tc.secretStore.DeepCopy()
+		sourceStore.Name = "source-store"
+		Expect(k8sClient.Create(context.Background(), sourceStore)).To(Succeed())
+		tc.externalSecret.Spec.SecretStoreRef = esv1beta1.SecretStoreRef{Name: "missing-store"}
+		tc.externalSecret.Spec.Data = []esv1beta1.ExternalSecretData{
+			{
+				SecretKey: targetProp,
+				RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: remoteKey},
+				SourceRef: &esv1beta1.StoreSourceRef{StoreRef: esv1beta1.SecretStoreRef{Name: sourceStore.Name}},
+			},
+		}
+		fakeProvider.WithGetSecret([]byte(FooValue), nil)
+		tc.checkSecret = func(es *esv1beta1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(FooValue))
+			Expect(indexStoreRef(es)).To(ConsistOf(storeRefKey(esv1beta1.SecretStoreKind, sourceStore.Name)))
+		}
+	}
+
+	// the condition reason reflects the type of the provider error
+	classifiedErrCondition := func(tc *testCase) {
+		fakeProvider.WithGetSecret(nil, esv1beta1.PermissionDeniedError{Err: fmt.Errorf("access denied")})
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			return cond != nil && cond.Status == v1.ConditionFalse && cond.Reason == esv1beta1.ConditionReasonPermissionDenied
+		}
+		tc.checkExternalSecret = func(es *esv1beta1.ExternalSecret) {
+			Eventually(func() float64 {
+				Expect(syncErrors.WithLabelValues(ExternalSecretName, ExternalSecretNamespace, esv1beta1.ConditionReasonPermissionDenied).Write(&metric)).To(Succeed())
+				return metric.GetCounter().GetValue()
+			}, timeout, interval).Should(BeNumerically(">=", 1.0))
+		}
+	}
+
+	// errors of several entries are combined into one condition message
+	combinedErrCondition := func(tc *testCase) {
+		fakeProvider.WithGetSecret(nil, fmt.Errorf("boom"))
+		tc.externalSecret.Spec.Data = append(tc.externalSecret.Spec.Data, esv1beta1.ExternalSecretData{
+			SecretKey: "other",
+			RemoteRef: esv1beta1.ExternalSecretDataRemoteRef{Key: remoteKey},
+		})
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
+				return false
+			}
+			return cond.Message == fmt.Sprintf("%s: %s %q (spec.data[0], spec.data[1])", errGetSecretData, esv1beta1.SecretStoreKind, ExternalSecretStore)
+		}
+	}
+
+	// the condition names the store which could not be read
+	storeOverrideMissingErrCondition := func(tc *testCase) {
+		tc.externalSecret.Spec.Data[0].SourceRef = &esv1beta1.StoreSourceRef{
+			StoreRef: esv1beta1.SecretStoreRef{Name: "nonexistent"},
+		}
+		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
+			cond := GetExternalSecretCondition(es.Status, esv1beta1.ExternalSecretReady)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1beta1.ConditionReasonSecretSyncedError {
+				return false
+			}
+			return cond.Message == fmt.Sprintf("%s: %s %q", errStoreRef, esv1beta1.SecretStoreKind, "nonexistent")
+		}
+	}
+
 	ignoreMismatchController := func(tc *testCase) {
 		tc.secretStore.Spec.Controller = "nop"
 		tc.checkCondition = func(es *esv1beta1.ExternalSecret) bool {
@@ -1278,6 +1860,7 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should sync with template engine v2", syncWithTemplateV2),
 		Entry("should sync template with correct value precedence", syncWithTemplatePrecedence),
 		Entry("should refresh secret from template", refreshWithTemplate),
+		Entry("should refresh secret when a templateFrom source changes", refreshOnTemplateFromChange),
 		Entry("should be able to use only metadata from template", onlyMetadataFromTemplate),
 		Entry("should refresh secret value when provider secret changes", refreshSecretValue),
 		Entry("should refresh secret map when provider secret changes", refreshSecretValueMap),
@@ -1285,10 +1868,25 @@ var _ = Describe("ExternalSecret controller", func() {
 		Entry("should not refresh secret value when provider secret changes but refreshInterval is zero", refreshintervalZero),
 		Entry("should fetch secret using dataFrom", syncWithDataFrom),
 		Entry("should fetch secret using dataFrom.find", syncDataFromFind),
+		Entry("should record the source of every synced key in the status", syncedKeysStatus),
+		Entry("should record the keys rendered by the template in the status", syncedKeysTemplateStatus),
+		Entry("should rewrite keys using dataFrom.rewrite", syncDataFromRewrite),
+		Entry("should set a key collision condition when rewritten keys collide", dataFromRewriteCollision),
+		Entry("should keep generated values until a rotation is requested", syncWithGenerator),
+		Entry("should not store generated values in a Secret owned by something else", generatorStateNotOwned),
+		Entry("should renew generated values before they expire", renewExpiringGeneratorValues),
 		Entry("should fetch secret using dataFrom and a template", syncWithDataFromTemplate),
 		Entry("should set error condition when provider errors", providerErrCondition),
 		Entry("should set an error condition when store does not exist", storeMissingErrCondition),
 		Entry("should set an error condition when store provider constructor fails", storeConstructErrCondition),
+		Entry("should fetch entries from the store referenced in their sourceRef", syncFromMultipleStores),
+		Entry("should not read spec.secretStoreRef if no entry uses it", syncWithUnusedSecretStoreRef),
+		Entry("should sync values to a ConfigMap", syncToConfigMap),
+		Entry("should restart rollout targets when the data changes", rolloutOnDataChange),
+		Entry("should name the missing store in the error condition", storeOverrideMissingErrCondition),
+		Entry("should combine the errors of several entries in the error condition", combinedErrCondition),
+		Entry("should set the condition reason from the type of the provider error", classifiedErrCondition),
+		Entry("should sync again when the store changes", resyncOnStoreChange),
 		Entry("should not process store with mismatching controller field", ignoreMismatchController),
 		Entry("should not process cluster secret store when it is disabled", ignoreClusterSecretStoreWhenDisabled),
 		Entry("should eventually delete target secret with deletionPolicy=Delete", deleteSecretPolicy),
@@ -1406,6 +2004,98 @@ var _ = Describe("ExternalSecret refresh logic", func() {
 			Expect(shouldRefresh(es)).To(BeTrue())
 		})
 
+		It("should refresh when the last sync failed", func() {
+			es := esv1beta1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+				Spec: esv1beta1.ExternalSecretSpec{
+					RefreshInterval: &metav1.Duration{Duration: time.Minute},
+				},
+				Status: esv1beta1.ExternalSecretStatus{
+					RefreshTime: metav1.Now(),
+				},
+			}
+			es.Status.SyncedResourceVersion = getResourceVersion(es)
+			Expect(shouldRefresh(es)).To(BeFalse())
+
+			es.Status.Conditions = []esv1beta1.ExternalSecretStatusCondition{
+				*NewExternalSecretCondition(esv1beta1.ExternalSecretReady, v1.ConditionFalse, esv1beta1.ConditionReasonSecretSyncedError, errStoreUsability),
+			}
+			Expect(shouldRefresh(es)).To(BeTrue())
+		})
+
+		It("should refresh only once with refreshPolicy=CreatedOnce", func() {
+			es := esv1beta1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+				Spec: esv1beta1.ExternalSecretSpec{
+					RefreshPolicy:   esv1beta1.RefreshPolicyCreatedOnce,
+					RefreshInterval: &metav1.Duration{Duration: time.Second},
+				},
+			}
+			Expect(shouldRefresh(es)).To(BeTrue())
+
+			es.Status.SyncedResourceVersion = getResourceVersion(es)
+			es.Status.RefreshTime = metav1.NewTime(metav1.Now().Add(-time.Second * 5))
+			Expect(shouldRefresh(es)).To(BeFalse())
+
+			// spec changes are not synced either
+			es.ObjectMeta.Generation = 2
+			Expect(shouldRefresh(es)).To(BeFalse())
+			Expect(requeueInterval(es, time.Hour, time.Time{})).To(BeZero())
+		})
+
+		It("should refresh only on changes with refreshPolicy=OnChange", func() {
+			es := esv1beta1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+				Spec: esv1beta1.ExternalSecretSpec{
+					RefreshPolicy:   esv1beta1.RefreshPolicyOnChange,
+					RefreshInterval: &metav1.Duration{Duration: time.Second},
+				},
+				Status: esv1beta1.ExternalSecretStatus{
+					RefreshTime: metav1.NewTime(metav1.Now().Add(-time.Second * 5)),
+				},
+			}
+			es.Status.SyncedResourceVersion = getResourceVersion(es)
+			// refresh interval has passed
+			Expect(shouldRefresh(es)).To(BeFalse())
+
+			es.ObjectMeta.Annotations = map[string]string{"foo": "bar"}
+			Expect(shouldRefresh(es)).To(BeTrue())
+			Expect(requeueInterval(es, time.Hour, time.Time{})).To(BeZero())
+		})
+
+		It("should refresh once per force-sync value", func() {
+			es := esv1beta1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+				Spec: esv1beta1.ExternalSecretSpec{
+					RefreshPolicy: esv1beta1.RefreshPolicyCreatedOnce,
+				},
+				Status: esv1beta1.ExternalSecretStatus{
+					RefreshTime: metav1.Now(),
+				},
+			}
+			es.Status.SyncedResourceVersion = getResourceVersion(es)
+			Expect(shouldRefresh(es)).To(BeFalse())
+
+			es.ObjectMeta.Annotations = map[string]string{esv1beta1.AnnotationForceSync: "1"}
+			Expect(shouldRefresh(es)).To(BeTrue())
+
+			// the value has been handled
+			es.Status.SyncedResourceVersion = getResourceVersion(es)
+			es.Status.ForceSync = "1"
+			Expect(shouldRefresh(es)).To(BeFalse())
+
+			es.ObjectMeta.Annotations[esv1beta1.AnnotationForceSync] = "2"
+			Expect(shouldRefresh(es)).To(BeTrue())
+		})
+
 		It("should refresh when no refresh time was set", func() {
 			es := esv1beta1.ExternalSecret{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1422,6 +2112,33 @@ var _ = Describe("ExternalSecret refresh logic", func() {
 		})
 
 	})
+	Context("generator expiry", func() {
+		It("should not renew values without expiry", func() {
+			Expect(generatorRenewalDue(time.Time{})).To(BeFalse())
+		})
+
+		It("should renew values which are about to expire", func() {
+			Expect(generatorRenewalDue(time.Now().Add(generatorRenewBefore / 2))).To(BeTrue())
+			Expect(generatorRenewalDue(time.Now().Add(generatorRenewBefore * 2))).To(BeFalse())
+		})
+
+		It("should keep the refresh interval if values do not expire", func() {
+			Expect(requeueBeforeExpiry(time.Hour, time.Time{})).To(Equal(time.Hour))
+			Expect(requeueBeforeExpiry(0, time.Time{})).To(Equal(time.Duration(0)))
+		})
+
+		It("should requeue before values expire", func() {
+			expiry := time.Now().Add(time.Hour)
+			Expect(requeueBeforeExpiry(time.Minute, expiry)).To(Equal(time.Minute))
+			Expect(requeueBeforeExpiry(2*time.Hour, expiry)).To(BeNumerically("~", time.Hour-generatorRenewBefore, time.Second))
+			Expect(requeueBeforeExpiry(0, expiry)).To(BeNumerically("~", time.Hour-generatorRenewBefore, time.Second))
+		})
+
+		It("should not requeue earlier than the default requeue interval", func() {
+			Expect(requeueBeforeExpiry(time.Hour, time.Now())).To(Equal(requeueAfter))
+		})
+	})
+
 	Context("objectmeta hash", func() {
 		It("should produce different hashes for different k/v pairs", func() {
 			h1 := hashMeta(metav1.ObjectMeta{
@@ -1495,7 +2212,7 @@ var _ = Describe("Controller Reconcile logic", func() {
 					SyncedResourceVersion: "some resource version",
 					Conditions:            []esv1beta1.ExternalSecretStatusCondition{{Reason: "NotASecretSynced"}},
 				},
-			})).To(BeTrue())
+			}, v1.Secret{})).To(BeTrue())
 		})
 
 		It("should reconcile when secret isn't immutable", func() {
@@ -1505,7 +2222,7 @@ var _ = Describe("Controller Reconcile logic", func() {
 						Immutable: false,
 					},
 				},
-			})).To(BeTrue())
+			}, v1.Secret{})).To(BeTrue())
 		})
 
 		It("should not reconcile if secret is immutable and has synced condition", func() {
@@ -1519,11 +2236,50 @@ var _ = Describe("Controller Reconcile logic", func() {
 					SyncedResourceVersion: "some resource version",
 					Conditions:            []esv1beta1.ExternalSecretStatusCondition{{Reason: "SecretSynced"}},
 				},
-			})).To(BeFalse())
+			}, v1.Secret{})).To(BeFalse())
+		})
+
+		It("should recreate a deleted immutable secret with refreshPolicy=CreatedOnce", func() {
+			es := esv1beta1.ExternalSecret{
+				Spec: esv1beta1.ExternalSecretSpec{
+					RefreshPolicy: esv1beta1.RefreshPolicyCreatedOnce,
+					Target: esv1beta1.ExternalSecretTarget{
+						Immutable: true,
+					},
+				},
+				Status: esv1beta1.ExternalSecretStatus{
+					SyncedResourceVersion: "some resource version",
+					Conditions:            []esv1beta1.ExternalSecretStatusCondition{{Reason: "SecretSynced"}},
+				},
+			}
+			Expect(shouldReconcile(es, v1.Secret{})).To(BeTrue())
+			Expect(shouldReconcile(es, v1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "xxx"}})).To(BeFalse())
 		})
 	})
 })
 
+// expiringGenerator returns a new token on every call,
+// the first one is about to expire.
+type expiringGenerator struct {
+	calls int
+}
+
+func (g *expiringGenerator) Generate(ctx context.Context, obj *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	g.calls++
+	expiry := time.Now().Add(time.Hour)
+	if g.calls == 1 {
+		expiry = time.Now().Add(time.Minute)
+	}
+	return map[string][]byte{
+		"token":  []byte(fmt.Sprintf("token-%d", g.calls)),
+		"expiry": []byte(expiry.UTC().Format(time.RFC3339)),
+	}, nil
+}
+
+func (g *expiringGenerator) ExpiresAt(data map[string][]byte) (time.Time, error) {
+	return time.Parse(time.RFC3339, string(data["expiry"]))
+}
+
 func externalSecretConditionShouldBe(name, ns string, ct esv1beta1.ExternalSecretConditionType, cs v1.ConditionStatus, v float64) bool {
 	return Eventually(func() float64 {
 		Expect(externalSecretCondition.WithLabelValues(name, ns, string(ct), string(cs)).Write(&metric)).To(Succeed())
@@ -26,6 +26,7 @@ const (
 	ExternalSecretSubsystem          = "externalsecret"
 	SyncCallsKey                     = "sync_calls_total"
 	SyncCallsErrorKey                = "sync_calls_error"
+	SyncErrorsKey                    = "sync_errors_total"
 	externalSecretStatusConditionKey = "status_condition"
 )
 
@@ -42,6 +43,12 @@ var (
 		Help:      "Total number of the External Secret sync errors",
 	}, []string{"name", "namespace"})
 
+	syncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      SyncErrorsKey,
+		Help:      "Total number of the External Secret sync errors by the reason of the provider error",
+	}, []string{"name", "namespace", "reason"})
+
 	externalSecretCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: ExternalSecretSubsystem,
 		Name:      externalSecretStatusConditionKey,
@@ -116,5 +123,5 @@ func updateExternalSecretCondition(es *esv1beta1.ExternalSecret, condition *esv1
 }
 
 func init() {
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition)
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, syncErrors, externalSecretCondition)
 }
@@ -0,0 +1,221 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keyprefix scopes the remote keys which ExternalSecrets and
+// PushSecrets use to the keyPrefix of a store rendered for their namespace.
+package keyprefix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errParse         = "invalid keyPrefix: %w"
+	errRender        = "could not render keyPrefix: %w"
+	errEmptyPrefix   = "keyPrefix rendered to an empty prefix"
+	errNoSeparator   = "keyPrefix %q must end with \"/\""
+	errPrefixParent  = "keyPrefix %q must not contain \"..\""
+	errAbsoluteKey   = "key %q must be relative to the keyPrefix of the store"
+	errKeyParent     = "key %q must not contain \"..\""
+	errConvertPrefix = "could not convert keyPrefix: %w"
+)
+
+// templateData is available to the keyPrefix template.
+type templateData struct {
+	Namespace string
+	Labels    map[string]string
+}
+
+// Prefix is the keyPrefix of a store rendered for a namespace.
+// A nil Prefix leaves keys unchanged.
+type Prefix struct {
+	prefix string
+}
+
+// ForNamespace renders the keyPrefix of the store for the namespace.
+// It returns nil if the store has no keyPrefix.
+func ForNamespace(store esv1beta1.GenericStore, namespace *corev1.Namespace) (*Prefix, error) {
+	text := store.GetSpec().KeyPrefix
+	if text == "" {
+		return nil, nil
+	}
+	tpl, err := template.New("keyPrefix").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf(errParse, err)
+	}
+	var buf bytes.Buffer
+	err = tpl.Execute(&buf, templateData{
+		Namespace: namespace.Name,
+		Labels:    namespace.Labels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errRender, err)
+	}
+	prefix := buf.String()
+	if prefix == "" {
+		return nil, fmt.Errorf(errEmptyPrefix)
+	}
+	// without a trailing separator the prefix of namespace "a" would
+	// give access to the keys of namespace "ab" with the key "b/...".
+	if !strings.HasSuffix(prefix, "/") {
+		return nil, fmt.Errorf(errNoSeparator, prefix)
+	}
+	if hasParent(prefix) {
+		return nil, fmt.Errorf(errPrefixParent, prefix)
+	}
+	return &Prefix{prefix: prefix}, nil
+}
+
+// hasParent returns true if a path segment of the key is "..".
+func hasParent(key string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the remote key with the prefix. It fails for absolute keys
+// and keys which contain "..", since they could escape the prefix.
+func (p *Prefix) Key(key string) (string, error) {
+	if p == nil {
+		return key, nil
+	}
+	if strings.HasPrefix(key, "/") {
+		return "", esv1beta1.InvalidRefError{Err: fmt.Errorf(errAbsoluteKey, key)}
+	}
+	if hasParent(key) {
+		return "", esv1beta1.InvalidRefError{Err: fmt.Errorf(errKeyParent, key)}
+	}
+	return p.prefix + key, nil
+}
+
+// Wrap prepends the prefix to the remote keys of all requests of the client
+// and strips it from the keys found by GetAllSecrets.
+// The client is returned as is if the prefix is nil.
+func (p *Prefix) Wrap(secretClient esv1beta1.SecretsClient) esv1beta1.SecretsClient {
+	if p == nil {
+		return secretClient
+	}
+	return &client{SecretsClient: secretClient, prefix: p}
+}
+
+// client prepends the prefix to the remote keys of all requests.
+type client struct {
+	esv1beta1.SecretsClient
+	prefix *Prefix
+}
+
+func (c *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	key, err := c.prefix.Key(ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	ref.Key = key
+	return c.SecretsClient.GetSecret(ctx, ref)
+}
+
+func (c *client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	key, err := c.prefix.Key(ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	ref.Key = key
+	return c.SecretsClient.GetSecretMap(ctx, ref)
+}
+
+func (c *client) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	key, err := c.prefix.Key(ref.Key)
+	if err != nil {
+		return "", err
+	}
+	ref.Key = key
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+// GetAllSecrets searches below the prefix. Found keys are returned without
+// the prefix, keys outside of the prefix are dropped in case the provider
+// does not support find.path. Providers which convert the found keys
+// convert the prefix as well, so the converted prefix is stripped too.
+func (c *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	path := strings.TrimSuffix(c.prefix.prefix, "/")
+	if ref.Path != nil {
+		var err error
+		path, err = c.prefix.Key(*ref.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ref.Path = &path
+	secretMap, err := c.SecretsClient.GetAllSecrets(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := utils.ConvertKeys(ref.ConversionStrategy, map[string][]byte{c.prefix.prefix: nil})
+	if err != nil {
+		return nil, fmt.Errorf(errConvertPrefix, err)
+	}
+	prefixes := []string{c.prefix.prefix}
+	for k := range converted {
+		if k != "" && k != c.prefix.prefix {
+			prefixes = append(prefixes, k)
+		}
+	}
+	out := make(map[string][]byte, len(secretMap))
+	for key, value := range secretMap {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+				out[strings.TrimPrefix(key, prefix)] = value
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (c *client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	key, err := c.prefix.Key(remoteRef.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	return c.SecretsClient.SetSecret(ctx, value, pushRemoteRef{PushRemoteRef: remoteRef, key: key})
+}
+
+func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	key, err := c.prefix.Key(remoteRef.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	return c.SecretsClient.DeleteSecret(ctx, pushRemoteRef{PushRemoteRef: remoteRef, key: key})
+}
+
+// pushRemoteRef replaces the remote key of a PushRemoteRef.
+type pushRemoteRef struct {
+	esv1beta1.PushRemoteRef
+	key string
+}
+
+func (r pushRemoteRef) GetRemoteKey() string {
+	return r.key
+}
@@ -0,0 +1,163 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyprefix
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// recordingClient records the keys and paths it has been called with.
+type recordingClient struct {
+	esv1beta1.SecretsClient
+	keys  []string
+	found map[string][]byte
+}
+
+func (c *recordingClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.keys = append(c.keys, ref.Key)
+	return []byte("value"), nil
+}
+
+func (c *recordingClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	c.keys = append(c.keys, *ref.Path)
+	return c.found, nil
+}
+
+func (c *recordingClient) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	c.keys = append(c.keys, remoteRef.GetRemoteKey())
+	return nil
+}
+
+type pushRef struct {
+	key string
+}
+
+func (r pushRef) GetRemoteKey() string { return r.key }
+func (r pushRef) GetProperty() string  { return "" }
+
+func makeStore(keyPrefix string) *esv1beta1.ClusterSecretStore {
+	return &esv1beta1.ClusterSecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault"},
+		Spec:       esv1beta1.SecretStoreSpec{KeyPrefix: keyPrefix},
+	}
+}
+
+func makeNamespace(labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: labels}}
+}
+
+func TestForNamespace(t *testing.T) {
+	tbl := []struct {
+		keyPrefix string
+		labels    map[string]string
+		want      string
+		wantErr   bool
+	}{
+		{keyPrefix: "tenants/{{ .Namespace }}/", want: "tenants/team-a/"},
+		{keyPrefix: "/{{ .Labels.env }}/{{ .Namespace }}/", labels: map[string]string{"env": "prod"}, want: "/prod/team-a/"},
+		{keyPrefix: "{{ .Labels.env }}/", wantErr: true},
+		{keyPrefix: "tenants/{{ .Labels.env }}/", labels: map[string]string{"env": ".."}, wantErr: true},
+		{keyPrefix: "{{ if false }}x{{ end }}", wantErr: true},
+		{keyPrefix: "{{ .Namespace", wantErr: true},
+		{keyPrefix: "tenants/{{ .Namespace }}", wantErr: true},
+	}
+	for _, row := range tbl {
+		p, err := ForNamespace(makeStore(row.keyPrefix), makeNamespace(row.labels))
+		if (err != nil) != row.wantErr {
+			t.Errorf("%s: want error %v, got %v", row.keyPrefix, row.wantErr, err)
+			continue
+		}
+		if err == nil && p.prefix != row.want {
+			t.Errorf("%s: want %s, got %s", row.keyPrefix, row.want, p.prefix)
+		}
+	}
+	if p, err := ForNamespace(makeStore(""), makeNamespace(nil)); p != nil || err != nil {
+		t.Errorf("want nil prefix without keyPrefix, got %v, %v", p, err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	p := &Prefix{prefix: "tenants/team-a/"}
+	tbl := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "db/password", want: "tenants/team-a/db/password"},
+		{key: "db..password", want: "tenants/team-a/db..password"},
+		{key: "/tenants/team-b/db", wantErr: true},
+		{key: "../team-b/db", wantErr: true},
+		{key: "db/../../team-b/db", wantErr: true},
+	}
+	for _, row := range tbl {
+		got, err := p.Key(row.key)
+		if row.wantErr {
+			if !errors.As(err, &esv1beta1.InvalidRefError{}) {
+				t.Errorf("%s: want InvalidRefError, got %v", row.key, err)
+			}
+			continue
+		}
+		if err != nil || got != row.want {
+			t.Errorf("%s: want %s, got %s, %v", row.key, row.want, got, err)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	p := &Prefix{prefix: "tenants/team-a/"}
+	inner := &recordingClient{found: map[string][]byte{
+		"tenants/team-a/db":  []byte("raw"),
+		"tenants_team-a_api": []byte("converted"),
+		"tenants/team-b/db":  []byte("outside"),
+	}}
+	c := p.Wrap(inner)
+
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.SetSecret(context.Background(), []byte("value"), pushRef{key: "pushed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found, err := c.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{
+		ConversionStrategy: esv1beta1.ExternalSecretConversionDefault,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"tenants/team-a/db", "tenants/team-a/pushed", "tenants/team-a"}
+	for i, key := range want {
+		if i >= len(inner.keys) || inner.keys[i] != key {
+			t.Errorf("want request %d for %s, got %v", i, key, inner.keys)
+		}
+	}
+	if len(found) != 2 || string(found["db"]) != "raw" || string(found["api"]) != "converted" {
+		t.Errorf("want db and api without prefix, got %v", found)
+	}
+
+	if p.Wrap(inner) == esv1beta1.SecretsClient(inner) {
+		t.Errorf("client must be wrapped")
+	}
+	var nilPrefix *Prefix
+	if nilPrefix.Wrap(inner) != esv1beta1.SecretsClient(inner) {
+		t.Errorf("nil prefix must not wrap the client")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providermetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	ProviderSubsystem = "provider"
+	CallsKey          = "calls_total"
+	ErrorsKey         = "errors_total"
+	CallDurationKey   = "call_duration_seconds"
+)
+
+var (
+	callsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ProviderSubsystem,
+		Name:      CallsKey,
+		Help:      "Total number of calls to a provider",
+	}, []string{"provider", "kind", "name", "namespace", "method"})
+
+	errorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ProviderSubsystem,
+		Name:      ErrorsKey,
+		Help:      "Total number of failed calls to a provider by the class of the error",
+	}, []string{"provider", "kind", "name", "namespace", "method", "class"})
+
+	callDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: ProviderSubsystem,
+		Name:      CallDurationKey,
+		Help:      "Duration of calls to a provider",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "kind", "name", "namespace", "method"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(callsTotal, errorsTotal, callDuration)
+}
@@ -0,0 +1,171 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package providermetrics records the calls to the providers
+// of the stores as Prometheus metrics.
+package providermetrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	ClassAuth             = "auth"
+	ClassPermissionDenied = "permission_denied"
+	ClassInvalidRef       = "invalid_ref"
+	ClassThrottled        = "throttled"
+	ClassTransient        = "transient"
+	ClassNotFound         = "not_found"
+	ClassUnknown          = "unknown"
+)
+
+// ErrorClass returns the class of a provider error which is used as the
+// class label of the errors metric.
+func ErrorClass(err error) string {
+	switch {
+	case errors.As(err, &esv1beta1.AuthError{}):
+		return ClassAuth
+	case errors.As(err, &esv1beta1.PermissionDeniedError{}):
+		return ClassPermissionDenied
+	case errors.As(err, &esv1beta1.InvalidRefError{}):
+		return ClassInvalidRef
+	case errors.As(err, &esv1beta1.ThrottledError{}):
+		return ClassThrottled
+	case errors.As(err, &esv1beta1.TransientError{}):
+		return ClassTransient
+	case errors.Is(err, esv1beta1.NoSecretErr):
+		return ClassNotFound
+	}
+	return ClassUnknown
+}
+
+// labels identifies the provider and the store of a call.
+type labels struct {
+	provider, kind, name, namespace string
+}
+
+func labelsOf(store esv1beta1.GenericStore) labels {
+	// the provider has already been looked up by name, so this does not fail
+	provider, _ := esv1beta1.GetProviderName(store)
+	kind := esv1beta1.SecretStoreKind
+	if _, ok := store.(*esv1beta1.ClusterSecretStore); ok {
+		kind = esv1beta1.ClusterSecretStoreKind
+	}
+	return labels{
+		provider:  provider,
+		kind:      kind,
+		name:      store.GetName(),
+		namespace: store.GetNamespace(),
+	}
+}
+
+func (l labels) observe(method string, start time.Time, err error) {
+	callLabels := prometheus.Labels{
+		"provider":  l.provider,
+		"kind":      l.kind,
+		"name":      l.name,
+		"namespace": l.namespace,
+		"method":    method,
+	}
+	callsTotal.With(callLabels).Inc()
+	callDuration.With(callLabels).Observe(time.Since(start).Seconds())
+	if err != nil {
+		callLabels["class"] = ErrorClass(err)
+		errorsTotal.With(callLabels).Inc()
+	}
+}
+
+// WrapProvider records the creation of clients by the provider.
+// The clients themselves are not wrapped, see Wrap.
+func WrapProvider(p esv1beta1.Provider) esv1beta1.Provider {
+	return &provider{Provider: p}
+}
+
+type provider struct {
+	esv1beta1.Provider
+}
+
+func (p *provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	start := time.Now()
+	secretClient, err := p.Provider.NewClient(ctx, store, kube, namespace)
+	labelsOf(store).observe("NewClient", start, err)
+	return secretClient, err
+}
+
+// Wrap records the calls of the client to the provider of the store.
+func Wrap(store esv1beta1.GenericStore, secretClient esv1beta1.SecretsClient) esv1beta1.SecretsClient {
+	return &secretsClient{
+		SecretsClient: secretClient,
+		labels:        labelsOf(store),
+	}
+}
+
+type secretsClient struct {
+	esv1beta1.SecretsClient
+	labels labels
+}
+
+func (c *secretsClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	start := time.Now()
+	secret, err := c.SecretsClient.GetSecret(ctx, ref)
+	c.labels.observe("GetSecret", start, err)
+	return secret, err
+}
+
+func (c *secretsClient) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	start := time.Now()
+	secretMap, err := c.SecretsClient.GetSecretMap(ctx, ref)
+	c.labels.observe("GetSecretMap", start, err)
+	return secretMap, err
+}
+
+// GetSecretVersion does not call the provider and is not observed.
+func (c *secretsClient) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+func (c *secretsClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	start := time.Now()
+	secretMap, err := c.SecretsClient.GetAllSecrets(ctx, ref)
+	c.labels.observe("GetAllSecrets", start, err)
+	return secretMap, err
+}
+
+func (c *secretsClient) Validate() (esv1beta1.ValidationResult, error) {
+	start := time.Now()
+	result, err := c.SecretsClient.Validate()
+	c.labels.observe("Validate", start, err)
+	return result, err
+}
+
+func (c *secretsClient) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	start := time.Now()
+	err := c.SecretsClient.SetSecret(ctx, value, remoteRef)
+	c.labels.observe("SetSecret", start, err)
+	return err
+}
+
+func (c *secretsClient) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	start := time.Now()
+	err := c.SecretsClient.DeleteSecret(ctx, remoteRef)
+	c.labels.observe("DeleteSecret", start, err)
+	return err
+}
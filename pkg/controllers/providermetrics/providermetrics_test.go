@@ -0,0 +1,113 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providermetrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+type fakeProvider struct {
+	esv1beta1.Provider
+	err error
+}
+
+func (p *fakeProvider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	return &fakeClient{err: p.err}, p.err
+}
+
+type fakeClient struct {
+	esv1beta1.SecretsClient
+	err error
+}
+
+func (c *fakeClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	return nil, c.err
+}
+
+func makeStore(name string) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Fake: &esv1beta1.FakeProvider{},
+			},
+		},
+	}
+}
+
+func counterValue(t *testing.T, c interface{ Write(*dto.Metric) error }) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := c.Write(&metric); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestWrap(t *testing.T) {
+	store := makeStore("wrap")
+	errDenied := esv1beta1.PermissionDeniedError{Err: errors.New("access denied")}
+	c := Wrap(store, &fakeClient{err: errDenied})
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{}); !errors.Is(err, errDenied) {
+			t.Fatalf("want the error of the client, got %v", err)
+		}
+	}
+	if v := counterValue(t, callsTotal.WithLabelValues("fake", esv1beta1.SecretStoreKind, "wrap", "default", "GetSecret")); v != 2 {
+		t.Errorf("want 2 calls, got %v", v)
+	}
+	if v := counterValue(t, errorsTotal.WithLabelValues("fake", esv1beta1.SecretStoreKind, "wrap", "default", "GetSecret", ClassPermissionDenied)); v != 2 {
+		t.Errorf("want 2 errors, got %v", v)
+	}
+}
+
+func TestWrapProvider(t *testing.T) {
+	store := makeStore("provider")
+	if _, err := WrapProvider(&fakeProvider{}).NewClient(context.Background(), store, nil, "default"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := counterValue(t, callsTotal.WithLabelValues("fake", esv1beta1.SecretStoreKind, "provider", "default", "NewClient")); v != 1 {
+		t.Errorf("want 1 call, got %v", v)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tbl := []struct {
+		err  error
+		want string
+	}{
+		{err: esv1beta1.AuthError{Err: errors.New("expired")}, want: ClassAuth},
+		{err: esv1beta1.ThrottledError{Err: errors.New("slow down")}, want: ClassThrottled},
+		{err: esv1beta1.TransientError{Err: errors.New("unavailable")}, want: ClassTransient},
+		{err: esv1beta1.NoSecretError{}, want: ClassNotFound},
+		{err: errors.New("boom"), want: ClassUnknown},
+	}
+	for _, tc := range tbl {
+		if got := ErrorClass(tc.err); got != tc.want {
+			t.Errorf("%v: want class %s, got %s", tc.err, tc.want, got)
+		}
+	}
+}
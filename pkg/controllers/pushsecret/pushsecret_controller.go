@@ -0,0 +1,546 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/accesspolicy"
+	"github.com/external-secrets/external-secrets/pkg/controllers/clientpool"
+	"github.com/external-secrets/external-secrets/pkg/controllers/keyprefix"
+	"github.com/external-secrets/external-secrets/pkg/controllers/providermetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
+	"github.com/external-secrets/external-secrets/pkg/controllers/retry"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+
+	// Loading registered providers.
+	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
+	"github.com/external-secrets/external-secrets/pkg/tracing"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	pushSecretFinalizer = "pushsecret.externalsecrets.io/finalizer"
+
+	errGetPushSecret         = "could not get PushSecret"
+	errPatchStatus           = "unable to patch status"
+	errUpdateFinalizer       = "could not update finalizer"
+	errGetSecret             = "could not get source secret %q: %w"
+	errGetSecretStore        = "could not get SecretStore %q, %w"
+	errGetClusterSecretStore = "could not get ClusterSecretStore %q, %w"
+	errGetNamespace          = "could not get namespace %q: %w"
+	errClusterStoreDisabled  = "ClusterSecretStore %q is disabled"
+	errUnmanagedStore        = "store %q is not handled by this controller"
+	errStoreNotReady         = "store %q is not ready"
+	errStoreProvider         = "could not get store provider: %w"
+	errStoreClient           = "could not get provider client: %w"
+	errCloseStoreClient      = "could not close provider client"
+	errMissingSecretKey      = "key %q does not exist in secret %q"
+	errDeleteStale           = "could not delete stale secret: %w"
+	errDeleteSynced          = "could not delete pushed secrets"
+	errPushFailed            = "%d of %d keys could not be pushed"
+)
+
+// Reconciler reconciles a PushSecret object.
+type Reconciler struct {
+	client.Client
+	Log                       logr.Logger
+	Scheme                    *runtime.Scheme
+	ControllerClass           string
+	RequeueInterval           time.Duration
+	ClusterSecretStoreEnabled bool
+	// EnableFloodGate denies pushes to stores which are not ready.
+	EnableFloodGate bool
+	// ClientPool keeps provider clients alive across reconciles.
+	// It is shared with the ExternalSecret reconciler. If nil,
+	// a new client is created for every reconcile.
+	ClientPool *clientpool.Pool
+	// RateLimiters limits the requests to stores which have a rateLimit.
+	// It is shared with the ExternalSecret reconciler. If nil, requests are not limited.
+	RateLimiters *ratelimit.Limiters
+	recorder     record.EventRecorder
+}
+
+// Reconcile pushes the data of the selected Secret to all referenced stores
+// and records the result per store and key in the PushSecret status.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("PushSecret", req.NamespacedName)
+
+	var ps esv1alpha1.PushSecret
+	err := r.Get(ctx, req.NamespacedName, &ps)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		log.Error(err, errGetPushSecret)
+		return ctrl.Result{}, nil
+	}
+
+	if !ps.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, log, &ps)
+	}
+
+	// the finalizer is only needed to clean up the provider secrets.
+	shouldHaveFinalizer := ps.Spec.DeletionPolicy == esv1alpha1.PushSecretDeletionPolicyDelete
+	if shouldHaveFinalizer != controllerutil.ContainsFinalizer(&ps, pushSecretFinalizer) {
+		if shouldHaveFinalizer {
+			controllerutil.AddFinalizer(&ps, pushSecretFinalizer)
+		} else {
+			controllerutil.RemoveFinalizer(&ps, pushSecretFinalizer)
+		}
+		if err := r.Update(ctx, &ps); err != nil {
+			log.Error(err, errUpdateFinalizer)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// patch status when done processing
+	p := client.MergeFrom(ps.DeepCopy())
+	defer func() {
+		err = r.Status().Patch(ctx, &ps, p)
+		if err != nil {
+			log.Error(err, errPatchStatus)
+		}
+	}()
+
+	refreshInt := r.RequeueInterval
+	if ps.Spec.RefreshInterval != nil {
+		refreshInt = ps.Spec.RefreshInterval.Duration
+	}
+
+	var secret v1.Secret
+	err = r.Get(ctx, types.NamespacedName{Name: ps.Spec.Selector.Secret.Name, Namespace: ps.Namespace}, &secret)
+	if err != nil {
+		err = fmt.Errorf(errGetSecret, ps.Spec.Selector.Secret.Name, err)
+		log.Error(err, "unable to push secret")
+		r.recorder.Event(&ps, v1.EventTypeWarning, esv1alpha1.ReasonErrored, err.Error())
+		SetPushSecretCondition(&ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionFalse, esv1alpha1.ReasonErrored, err.Error()))
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+
+	// refresh should be skipped if neither the PushSecret
+	// nor the source Secret changed within the refresh interval.
+	if !shouldRefresh(ps, secret) {
+		log.V(1).Info("skipping refresh", "rv", getResourceVersion(ps, secret))
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+
+	clients := newClientCache(r, ps.Namespace)
+	defer clients.close(ctx, log)
+
+	synced := r.pushData(ctx, clients, &ps, &secret)
+	synced = append(synced, r.deleteStale(ctx, clients, &ps, synced)...)
+	ps.Status.SyncedPushSecrets = synced
+
+	failed := 0
+	for _, s := range synced {
+		if s.Status == esv1alpha1.PushSecretSyncStatusError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		msg := fmt.Sprintf(errPushFailed, failed, len(synced))
+		reason := esv1alpha1.ReasonErrored
+		if clients.notAllowed() {
+			reason = esv1alpha1.ReasonStoreNotAllowed
+		}
+		r.recorder.Event(&ps, v1.EventTypeWarning, reason, msg)
+		SetPushSecretCondition(&ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionFalse, reason, msg))
+		// failed keys are retried with the backoff of the controller's
+		// rate limiter, independent of the refresh interval.
+		return ctrl.Result{}, errors.New(msg)
+	}
+
+	currCond := GetPushSecretCondition(ps.Status, esv1alpha1.PushSecretReady)
+	SetPushSecretCondition(&ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionTrue, esv1alpha1.ReasonSynced, "PushSecret synced successfully"))
+	ps.Status.RefreshTime = metav1.NewTime(time.Now())
+	ps.Status.SyncedResourceVersion = getResourceVersion(ps, secret)
+	if currCond == nil || currCond.Status != v1.ConditionTrue {
+		r.recorder.Event(&ps, v1.EventTypeNormal, esv1alpha1.ReasonSynced, "PushSecret synced successfully")
+		log.Info("pushed secret")
+	} else {
+		log.V(1).Info("pushed secret")
+	}
+	if refreshInt == 0 {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: refreshInt}, nil
+}
+
+// pushData writes every key of spec.data to every store and returns the per key result.
+func (r *Reconciler) pushData(ctx context.Context, clients *clientCache, ps *esv1alpha1.PushSecret, secret *v1.Secret) []esv1alpha1.PushSecretSyncedData {
+	synced := make([]esv1alpha1.PushSecretSyncedData, 0, len(ps.Spec.SecretStoreRefs)*len(ps.Spec.Data))
+	pushed := make(map[string]bool, len(ps.Status.SyncedPushSecrets))
+	for _, old := range ps.Status.SyncedPushSecrets {
+		pushed[syncedKey(old)] = wasPushed(old)
+	}
+	for _, storeRef := range ps.Spec.SecretStoreRefs {
+		storeRef = normalizeStoreRef(storeRef)
+		secretClient, err := clients.get(ctx, storeRef)
+		for _, data := range ps.Spec.Data {
+			entry := esv1alpha1.PushSecretSyncedData{
+				StoreRef:  storeRef,
+				SecretKey: data.Match.SecretKey,
+				RemoteRef: data.Match.RemoteRef,
+				Status:    esv1alpha1.PushSecretSyncStatusSynced,
+			}
+			entry.Pushed = pushed[syncedKey(entry)]
+			if err != nil {
+				entry.Status = esv1alpha1.PushSecretSyncStatusError
+				entry.Message = err.Error()
+				synced = append(synced, entry)
+				continue
+			}
+			value, ok := secret.Data[data.Match.SecretKey]
+			if !ok {
+				entry.Status = esv1alpha1.PushSecretSyncStatusError
+				entry.Message = fmt.Sprintf(errMissingSecretKey, data.Match.SecretKey, secret.Name)
+				synced = append(synced, entry)
+				continue
+			}
+			if setErr := secretClient.SetSecret(ctx, value, data.Match.RemoteRef); setErr != nil {
+				entry.Status = esv1alpha1.PushSecretSyncStatusError
+				entry.Message = setErr.Error()
+			} else {
+				entry.Pushed = true
+			}
+			synced = append(synced, entry)
+		}
+	}
+	return synced
+}
+
+// deleteStale deletes provider secrets that were pushed before but are no longer
+// part of the PushSecret, whatever the result of their last push.
+// This is a no-op unless deletionPolicy=Delete.
+// Entries that could not be deleted are returned so that the deletion is retried.
+func (r *Reconciler) deleteStale(ctx context.Context, clients *clientCache, ps *esv1alpha1.PushSecret, current []esv1alpha1.PushSecretSyncedData) []esv1alpha1.PushSecretSyncedData {
+	if ps.Spec.DeletionPolicy != esv1alpha1.PushSecretDeletionPolicyDelete {
+		return nil
+	}
+	desired := make(map[string]struct{}, len(current))
+	for _, s := range current {
+		desired[syncedKey(s)] = struct{}{}
+	}
+	var pending []esv1alpha1.PushSecretSyncedData
+	for _, old := range ps.Status.SyncedPushSecrets {
+		if !wasPushed(old) {
+			continue
+		}
+		if _, ok := desired[syncedKey(old)]; ok {
+			continue
+		}
+		if err := deleteSynced(ctx, clients, old); err != nil {
+			old.Message = fmt.Errorf(errDeleteStale, err).Error()
+			old.Pushed = true
+			pending = append(pending, old)
+		}
+	}
+	return pending
+}
+
+// reconcileDelete removes all secrets which have ever been pushed
+// before the finalizer is removed.
+func (r *Reconciler) reconcileDelete(ctx context.Context, log logr.Logger, ps *esv1alpha1.PushSecret) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(ps, pushSecretFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if ps.Spec.DeletionPolicy == esv1alpha1.PushSecretDeletionPolicyDelete {
+		clients := newClientCache(r, ps.Namespace)
+		defer clients.close(ctx, log)
+
+		p := client.MergeFrom(ps.DeepCopy())
+		var pending []esv1alpha1.PushSecretSyncedData
+		for _, old := range ps.Status.SyncedPushSecrets {
+			if !wasPushed(old) {
+				continue
+			}
+			if err := deleteSynced(ctx, clients, old); err != nil {
+				old.Message = fmt.Errorf(errDeleteStale, err).Error()
+				old.Pushed = true
+				pending = append(pending, old)
+			}
+		}
+		if len(pending) > 0 {
+			ps.Status.SyncedPushSecrets = pending
+			SetPushSecretCondition(ps, *NewPushSecretCondition(esv1alpha1.PushSecretReady, v1.ConditionFalse, esv1alpha1.ReasonErrored, errDeleteSynced))
+			r.recorder.Event(ps, v1.EventTypeWarning, esv1alpha1.ReasonErrored, errDeleteSynced)
+			if err := r.Status().Patch(ctx, ps, p); err != nil {
+				log.Error(err, errPatchStatus)
+			}
+			return ctrl.Result{}, fmt.Errorf("%s: %d secrets pending", errDeleteSynced, len(pending))
+		}
+	}
+	controllerutil.RemoveFinalizer(ps, pushSecretFinalizer)
+	if err := r.Update(ctx, ps); err != nil {
+		log.Error(err, errUpdateFinalizer)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func deleteSynced(ctx context.Context, clients *clientCache, s esv1alpha1.PushSecretSyncedData) error {
+	secretClient, err := clients.get(ctx, s.StoreRef)
+	if err != nil {
+		return err
+	}
+	return secretClient.DeleteSecret(ctx, s.RemoteRef)
+}
+
+// wasPushed returns true if the key has been written to the store at some point.
+// Entries recorded before pushed was tracked are pushed if their last push succeeded.
+func wasPushed(s esv1alpha1.PushSecretSyncedData) bool {
+	return s.Pushed || s.Status == esv1alpha1.PushSecretSyncStatusSynced
+}
+
+func syncedKey(s esv1alpha1.PushSecretSyncedData) string {
+	return fmt.Sprintf("%s/%s/%s/%s", s.StoreRef.Kind, s.StoreRef.Name, s.RemoteRef.RemoteKey, s.RemoteRef.Property)
+}
+
+func normalizeStoreRef(ref esv1alpha1.PushSecretStoreRef) esv1alpha1.PushSecretStoreRef {
+	if ref.Kind == "" {
+		ref.Kind = esv1beta1.SecretStoreKind
+	}
+	return ref
+}
+
+// clientCache creates at most one provider client per store during a reconcile.
+type clientCache struct {
+	r         *Reconciler
+	namespace string
+	clients   map[esv1alpha1.PushSecretStoreRef]esv1beta1.SecretsClient
+	errors    map[esv1alpha1.PushSecretStoreRef]error
+}
+
+func newClientCache(r *Reconciler, namespace string) *clientCache {
+	return &clientCache{
+		r:         r,
+		namespace: namespace,
+		clients:   make(map[esv1alpha1.PushSecretStoreRef]esv1beta1.SecretsClient),
+		errors:    make(map[esv1alpha1.PushSecretStoreRef]error),
+	}
+}
+
+func (c *clientCache) get(ctx context.Context, ref esv1alpha1.PushSecretStoreRef) (esv1beta1.SecretsClient, error) {
+	ref = normalizeStoreRef(ref)
+	if cl, ok := c.clients[ref]; ok {
+		return cl, nil
+	}
+	if err, ok := c.errors[ref]; ok {
+		return nil, err
+	}
+	cl, err := c.r.newClient(ctx, ref, c.namespace)
+	if err != nil {
+		c.errors[ref] = err
+		return nil, err
+	}
+	c.clients[ref] = cl
+	return cl, nil
+}
+
+// notAllowed returns true if the conditions of a ClusterSecretStore
+// do not allow the namespace to use the store.
+func (c *clientCache) notAllowed() bool {
+	for _, err := range c.errors {
+		if errors.As(err, &esv1beta1.NamespaceNotAllowedError{}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *clientCache) close(ctx context.Context, log logr.Logger) {
+	for ref, cl := range c.clients {
+		if err := cl.Close(ctx); err != nil {
+			log.Error(err, errCloseStoreClient, "store", ref.Name)
+		}
+	}
+}
+
+func (r *Reconciler) newClient(ctx context.Context, ref esv1alpha1.PushSecretStoreRef, namespace string) (esv1beta1.SecretsClient, error) {
+	store, err := r.getStore(ctx, ref, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !secretstore.ShouldProcessStore(store, r.ControllerClass) {
+		return nil, fmt.Errorf(errUnmanagedStore, ref.Name)
+	}
+	if r.EnableFloodGate && !isStoreReady(store) {
+		return nil, fmt.Errorf(errStoreNotReady, ref.Name)
+	}
+	storeProvider, err := esv1beta1.GetProvider(store)
+	if err != nil {
+		return nil, fmt.Errorf(errStoreProvider, err)
+	}
+	policy, prefix, err := r.getStoreScope(ctx, store, namespace)
+	if err != nil {
+		return nil, err
+	}
+	// the client is opened like the clients of ExternalSecrets, see storeClients.open
+	provider := tracing.WrapProvider(providermetrics.WrapProvider(storeProvider))
+	secretClient, err := r.ClientPool.Get(ctx, provider, store, r.Client, namespace)
+	if err != nil {
+		return nil, fmt.Errorf(errStoreClient, err)
+	}
+	instrumented := tracing.Wrap(store, providermetrics.Wrap(store, secretClient))
+	retryClient, err := retry.Wrap(store, r.RateLimiters.Wrap(store, instrumented))
+	if err != nil {
+		_ = secretClient.Close(ctx)
+		return nil, fmt.Errorf(errStoreClient, err)
+	}
+	return prefix.Wrap(policy.Wrap(retryClient)), nil
+}
+
+// isStoreReady returns true if the Ready condition of the store is true.
+func isStoreReady(store esv1beta1.GenericStore) bool {
+	condition := secretstore.GetSecretStoreCondition(store.GetStatus(), esv1beta1.SecretStoreReady)
+	return condition != nil && condition.Status == v1.ConditionTrue
+}
+
+// getStoreScope returns the remote keys which PushSecrets in the
+// namespace may write to the store and the prefix of their keys.
+func (r *Reconciler) getStoreScope(ctx context.Context, store esv1beta1.GenericStore, namespace string) (*accesspolicy.Policy, *keyprefix.Prefix, error) {
+	spec := store.GetSpec()
+	if len(spec.AccessRules) == 0 && spec.KeyPrefix == "" {
+		return nil, nil, nil
+	}
+	var ns v1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return nil, nil, fmt.Errorf(errGetNamespace, namespace, err)
+	}
+	policy, err := accesspolicy.ForNamespace(store, &ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix, err := keyprefix.ForNamespace(store, &ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, prefix, nil
+}
+
+func (r *Reconciler) getStore(ctx context.Context, ref esv1alpha1.PushSecretStoreRef, namespace string) (esv1beta1.GenericStore, error) {
+	if ref.Kind == esv1beta1.ClusterSecretStoreKind {
+		if !r.ClusterSecretStoreEnabled {
+			return nil, fmt.Errorf(errClusterStoreDisabled, ref.Name)
+		}
+		var store esv1beta1.ClusterSecretStore
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, &store)
+		if err != nil {
+			return nil, fmt.Errorf(errGetClusterSecretStore, ref.Name, err)
+		}
+		if len(store.Spec.Conditions) > 0 {
+			var ns v1.Namespace
+			if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+				return nil, fmt.Errorf(errGetNamespace, namespace, err)
+			}
+			if err := esv1beta1.ValidateNamespace(&store, &ns); err != nil {
+				return nil, err
+			}
+		}
+		return &store, nil
+	}
+	var store esv1beta1.SecretStore
+	err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &store)
+	if err != nil {
+		return nil, fmt.Errorf(errGetSecretStore, ref.Name, err)
+	}
+	return &store, nil
+}
+
+// getResourceVersion changes whenever the PushSecret spec, its metadata
+// or the source Secret changes.
+func getResourceVersion(ps esv1alpha1.PushSecret, secret v1.Secret) string {
+	return fmt.Sprintf("%d-%s-%s", ps.ObjectMeta.GetGeneration(), hashMeta(ps.ObjectMeta), secret.ResourceVersion)
+}
+
+func hashMeta(m metav1.ObjectMeta) string {
+	type meta struct {
+		annotations map[string]string
+		labels      map[string]string
+	}
+	return utils.ObjectHash(meta{
+		annotations: m.Annotations,
+		labels:      m.Labels,
+	})
+}
+
+func shouldRefresh(ps esv1alpha1.PushSecret, secret v1.Secret) bool {
+	if ps.Status.SyncedResourceVersion != getResourceVersion(ps, secret) {
+		return true
+	}
+	if ps.Spec.RefreshInterval == nil || ps.Spec.RefreshInterval.Duration == 0 {
+		return false
+	}
+	if ps.Status.RefreshTime.IsZero() {
+		return true
+	}
+	return !ps.Status.RefreshTime.Add(ps.Spec.RefreshInterval.Duration).After(time.Now())
+}
+
+// findPushSecretsForSecret enqueues all PushSecrets that select the given Secret.
+func (r *Reconciler) findPushSecretsForSecret(obj client.Object) []reconcile.Request {
+	var list esv1alpha1.PushSecretList
+	err := r.List(context.Background(), &list, client.InNamespace(obj.GetNamespace()))
+	if err != nil {
+		r.Log.Error(err, "unable to list PushSecrets")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, ps := range list.Items {
+		if ps.Spec.Selector.Secret.Name != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: ps.Name, Namespace: ps.Namespace},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager returns a new controller builder that will be started by the provided Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+	r.recorder = mgr.GetEventRecorderFor("pushsecret")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&esv1alpha1.PushSecret{}).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findPushSecretsForSecret),
+			builder.OnlyMetadata,
+		).
+		Complete(r)
+}
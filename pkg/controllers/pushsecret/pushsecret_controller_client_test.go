@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+func TestNewClientFloodGate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := esv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	makeStore := func(ready v1.ConditionStatus) *esv1beta1.SecretStore {
+		store := &esv1beta1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default"},
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{
+					AWS: &esv1beta1.AWSProvider{Service: esv1beta1.AWSServiceSecretsManager},
+				},
+			},
+		}
+		if ready != "" {
+			store.Status.Conditions = []esv1beta1.SecretStoreStatusCondition{
+				{Type: esv1beta1.SecretStoreReady, Status: ready},
+			}
+		}
+		return store
+	}
+	tbl := []struct {
+		test      string
+		store     *esv1beta1.SecretStore
+		floodGate bool
+		expErr    string
+	}{
+		{
+			test:      "should open a client for a ready store",
+			store:     makeStore(v1.ConditionTrue),
+			floodGate: true,
+		},
+		{
+			test:      "should not open a client for a store which is not ready",
+			store:     makeStore(v1.ConditionFalse),
+			floodGate: true,
+			expErr:    fmt.Sprintf(errStoreNotReady, "store"),
+		},
+		{
+			test:      "should not open a client for a store without status",
+			store:     makeStore(""),
+			floodGate: true,
+			expErr:    fmt.Sprintf(errStoreNotReady, "store"),
+		},
+		{
+			test:  "should open a client for a store which is not ready without flood gate",
+			store: makeStore(v1.ConditionFalse),
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.test, func(t *testing.T) {
+			r := &Reconciler{
+				Client:          clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(row.store).Build(),
+				EnableFloodGate: row.floodGate,
+			}
+			cl, err := r.newClient(context.Background(), esv1alpha1.PushSecretStoreRef{
+				Name: "store",
+				Kind: esv1beta1.SecretStoreKind,
+			}, "default")
+			if row.expErr != "" {
+				if err == nil || err.Error() != row.expErr {
+					t.Fatalf("want error %q, got %v", row.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = cl.Close(context.Background())
+		})
+	}
+}
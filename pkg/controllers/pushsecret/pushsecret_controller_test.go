@@ -0,0 +1,396 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	ctest "github.com/external-secrets/external-secrets/pkg/controllers/commontest"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+var (
+	fakeProvider *fake.Client
+	timeout      = time.Second * 10
+	interval     = time.Millisecond * 250
+)
+
+// remoteStore records the values written through the fake provider.
+type remoteStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (s *remoteStore) set(ctx context.Context, value []byte, ref esv1beta1.PushRemoteRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[ref.GetRemoteKey()] = value
+	return nil
+}
+
+func (s *remoteStore) delete(ctx context.Context, ref esv1beta1.PushRemoteRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, ref.GetRemoteKey())
+	return nil
+}
+
+func (s *remoteStore) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return string(s.data[key])
+}
+
+func (s *remoteStore) has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data[key]
+	return ok
+}
+
+var _ = Describe("PushSecret controller", func() {
+	const (
+		PushSecretName  = "test-ps"
+		PushSecretStore = "test-store"
+		SecretName      = "test-secret"
+		SecretKey       = "key"
+		RemoteKey       = "remote-key"
+	)
+
+	var (
+		PushSecretNamespace string
+		remote              *remoteStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		PushSecretNamespace, err = ctest.CreateNamespace("test-ns", k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		fakeProvider.Reset()
+		remote = &remoteStore{data: make(map[string][]byte)}
+		fakeProvider.SetSecretFn = remote.set
+		fakeProvider.DeleteSecretFn = remote.delete
+
+		Expect(k8sClient.Create(context.Background(), &esv1beta1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PushSecretStore,
+				Namespace: PushSecretNamespace,
+			},
+			Spec: esv1beta1.SecretStoreSpec{
+				Provider: &esv1beta1.SecretStoreProvider{
+					AWS: &esv1beta1.AWSProvider{
+						Service: esv1beta1.AWSServiceSecretsManager,
+					},
+				},
+			},
+		})).To(Succeed())
+		Expect(k8sClient.Create(context.Background(), &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      SecretName,
+				Namespace: PushSecretNamespace,
+			},
+			Data: map[string][]byte{
+				SecretKey: []byte("value"),
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(context.Background(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: PushSecretNamespace,
+			},
+		})).To(Succeed())
+	})
+
+	makePushSecret := func(secretKey string) *esv1alpha1.PushSecret {
+		return &esv1alpha1.PushSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PushSecretName,
+				Namespace: PushSecretNamespace,
+			},
+			Spec: esv1alpha1.PushSecretSpec{
+				SecretStoreRefs: []esv1alpha1.PushSecretStoreRef{
+					{
+						Name: PushSecretStore,
+						Kind: esv1beta1.SecretStoreKind,
+					},
+				},
+				Selector: esv1alpha1.PushSecretSelector{
+					Secret: esv1alpha1.PushSecretSecret{
+						Name: SecretName,
+					},
+				},
+				Data: []esv1alpha1.PushSecretData{
+					{
+						Match: esv1alpha1.PushSecretMatch{
+							SecretKey: secretKey,
+							RemoteRef: esv1alpha1.PushSecretRemoteRef{
+								RemoteKey: RemoteKey,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	getPushSecret := func() *esv1alpha1.PushSecret {
+		var ps esv1alpha1.PushSecret
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{
+			Name:      PushSecretName,
+			Namespace: PushSecretNamespace,
+		}, &ps)).To(Succeed())
+		return &ps
+	}
+
+	readyStatus := func() v1.ConditionStatus {
+		cond := GetPushSecretCondition(getPushSecret().Status, esv1alpha1.PushSecretReady)
+		if cond == nil {
+			return v1.ConditionUnknown
+		}
+		return cond.Status
+	}
+
+	It("should push the secret value to the store", func() {
+		Expect(k8sClient.Create(context.Background(), makePushSecret(SecretKey))).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+		Expect(remote.get(RemoteKey)).To(Equal("value"))
+
+		ps := getPushSecret()
+		Expect(ps.Status.SyncedPushSecrets).To(HaveLen(1))
+		Expect(ps.Status.SyncedPushSecrets[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusSynced))
+		Expect(ps.Status.SyncedPushSecrets[0].StoreRef.Name).To(Equal(PushSecretStore))
+		Expect(ps.Finalizers).To(BeEmpty())
+	})
+
+	It("should push again when the source secret changes", func() {
+		Expect(k8sClient.Create(context.Background(), makePushSecret(SecretKey))).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+
+		var secret v1.Secret
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{
+			Name:      SecretName,
+			Namespace: PushSecretNamespace,
+		}, &secret)).To(Succeed())
+		secret.Data[SecretKey] = []byte("new-value")
+		Expect(k8sClient.Update(context.Background(), &secret)).To(Succeed())
+
+		Eventually(func() string {
+			return remote.get(RemoteKey)
+		}, timeout, interval).Should(Equal("new-value"))
+	})
+
+	It("should report a missing secret key", func() {
+		Expect(k8sClient.Create(context.Background(), makePushSecret("does-not-exist"))).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+
+		ps := getPushSecret()
+		Expect(ps.Status.SyncedPushSecrets).To(HaveLen(1))
+		Expect(ps.Status.SyncedPushSecrets[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusError))
+		Expect(remote.has(RemoteKey)).To(BeFalse())
+	})
+
+	It("should report provider errors", func() {
+		fakeProvider.WithSetSecret(errors.New("boom"))
+		Expect(k8sClient.Create(context.Background(), makePushSecret(SecretKey))).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+
+		ps := getPushSecret()
+		Expect(ps.Status.SyncedPushSecrets).To(HaveLen(1))
+		Expect(ps.Status.SyncedPushSecrets[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusError))
+		Expect(ps.Status.SyncedPushSecrets[0].Message).To(Equal("boom"))
+	})
+
+	It("should retry a failed push without a refresh interval", func() {
+		fakeProvider.WithSetSecret(errors.New("boom"))
+		ps := makePushSecret(SecretKey)
+		ps.Spec.RefreshInterval = &metav1.Duration{Duration: 0}
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+
+		fakeProvider.SetSecretFn = remote.set
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+		Expect(remote.get(RemoteKey)).To(Equal("value"))
+	})
+
+	It("should report a missing store", func() {
+		ps := makePushSecret(SecretKey)
+		ps.Spec.SecretStoreRefs[0].Name = "does-not-exist"
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+		Expect(getPushSecret().Status.SyncedPushSecrets[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusError))
+	})
+
+	It("should not push to a ClusterSecretStore restricted to other namespaces", func() {
+		store := &esv1beta1.ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "restricted-store-",
+			},
+			Spec: esv1beta1.SecretStoreSpec{
+				Conditions: []esv1beta1.ClusterSecretStoreCondition{
+					{Namespaces: []string{"other-namespace"}},
+				},
+				Provider: &esv1beta1.SecretStoreProvider{
+					AWS: &esv1beta1.AWSProvider{
+						Service: esv1beta1.AWSServiceSecretsManager,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), store)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), store)).To(Succeed())
+		}()
+
+		ps := makePushSecret(SecretKey)
+		ps.Spec.SecretStoreRefs[0] = esv1alpha1.PushSecretStoreRef{
+			Name: store.Name,
+			Kind: esv1beta1.ClusterSecretStoreKind,
+		}
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+
+		cond := GetPushSecretCondition(getPushSecret().Status, esv1alpha1.PushSecretReady)
+		Expect(cond.Reason).To(Equal(esv1alpha1.ReasonStoreNotAllowed))
+		Expect(remote.has(RemoteKey)).To(BeFalse())
+	})
+
+	It("should not push keys which the access rules of a ClusterSecretStore deny", func() {
+		store := &esv1beta1.ClusterSecretStore{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "shared-store-",
+			},
+			Spec: esv1beta1.SecretStoreSpec{
+				AccessRules: []esv1beta1.ClusterSecretStoreAccessRule{
+					{
+						ClusterSecretStoreCondition: esv1beta1.ClusterSecretStoreCondition{
+							Namespaces: []string{PushSecretNamespace},
+						},
+						Prefixes: []string{PushSecretNamespace + "/"},
+					},
+				},
+				Provider: &esv1beta1.SecretStoreProvider{
+					AWS: &esv1beta1.AWSProvider{
+						Service: esv1beta1.AWSServiceSecretsManager,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), store)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), store)).To(Succeed())
+		}()
+
+		ps := makePushSecret(SecretKey)
+		ps.Spec.SecretStoreRefs[0] = esv1alpha1.PushSecretStoreRef{
+			Name: store.Name,
+			Kind: esv1beta1.ClusterSecretStoreKind,
+		}
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+
+		synced := getPushSecret().Status.SyncedPushSecrets
+		Expect(synced).To(HaveLen(1))
+		Expect(synced[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusError))
+		Expect(synced[0].Message).To(ContainSubstring("does not allow namespace"))
+		Expect(remote.has(RemoteKey)).To(BeFalse())
+	})
+
+	It("should delete pushed secrets with deletionPolicy=Delete", func() {
+		ps := makePushSecret(SecretKey)
+		ps.Spec.DeletionPolicy = esv1alpha1.PushSecretDeletionPolicyDelete
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+		Expect(getPushSecret().Finalizers).To(ContainElement(pushSecretFinalizer))
+		Expect(remote.get(RemoteKey)).To(Equal("value"))
+
+		Expect(k8sClient.Delete(context.Background(), getPushSecret())).To(Succeed())
+		Eventually(func() bool {
+			var ps esv1alpha1.PushSecret
+			err := k8sClient.Get(context.Background(), types.NamespacedName{
+				Name:      PushSecretName,
+				Namespace: PushSecretNamespace,
+			}, &ps)
+			return apierrors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+		Expect(remote.has(RemoteKey)).To(BeFalse())
+	})
+
+	It("should delete stale remote secrets with deletionPolicy=Delete", func() {
+		ps := makePushSecret(SecretKey)
+		ps.Spec.DeletionPolicy = esv1alpha1.PushSecretDeletionPolicyDelete
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+
+		ps = getPushSecret()
+		ps.Spec.Data[0].Match.RemoteRef.RemoteKey = "other-key"
+		Expect(k8sClient.Update(context.Background(), ps)).To(Succeed())
+
+		Eventually(func() bool {
+			return remote.has(RemoteKey)
+		}, timeout, interval).Should(BeFalse())
+		Expect(remote.get("other-key")).To(Equal("value"))
+	})
+
+	It("should delete a pushed secret whose last push failed with deletionPolicy=Delete", func() {
+		ps := makePushSecret(SecretKey)
+		ps.Spec.DeletionPolicy = esv1alpha1.PushSecretDeletionPolicyDelete
+		Expect(k8sClient.Create(context.Background(), ps)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionTrue))
+		Expect(remote.get(RemoteKey)).To(Equal("value"))
+
+		// the next push fails, the key stays pushed
+		fakeProvider.WithSetSecret(errors.New("boom"))
+		var secret v1.Secret
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{
+			Name:      SecretName,
+			Namespace: PushSecretNamespace,
+		}, &secret)).To(Succeed())
+		secret.Data[SecretKey] = []byte("new-value")
+		Expect(k8sClient.Update(context.Background(), &secret)).To(Succeed())
+		Eventually(readyStatus, timeout, interval).Should(Equal(v1.ConditionFalse))
+		synced := getPushSecret().Status.SyncedPushSecrets
+		Expect(synced).To(HaveLen(1))
+		Expect(synced[0].Status).To(Equal(esv1alpha1.PushSecretSyncStatusError))
+		Expect(synced[0].Pushed).To(BeTrue())
+
+		Expect(k8sClient.Delete(context.Background(), getPushSecret())).To(Succeed())
+		Eventually(func() bool {
+			return remote.has(RemoteKey)
+		}, timeout, interval).Should(BeFalse())
+	})
+})
+
+func init() {
+	fakeProvider = fake.New()
+	esv1beta1.ForceRegister(fakeProvider, &esv1beta1.SecretStoreProvider{
+		AWS: &esv1beta1.AWSProvider{
+			Service: esv1beta1.AWSServiceSecretsManager,
+		},
+	})
+}
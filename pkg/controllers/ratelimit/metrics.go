@@ -0,0 +1,45 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	RateLimitSubsystem = "ratelimit"
+	WaitSecondsKey     = "wait_seconds"
+	ThrottledKey       = "throttled_total"
+)
+
+var (
+	waitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: RateLimitSubsystem,
+		Name:      WaitSecondsKey,
+		Help:      "Time a provider request waited for the rate limit of its store",
+		Buckets:   []float64{0, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"kind", "name", "namespace"})
+
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: RateLimitSubsystem,
+		Name:      ThrottledKey,
+		Help:      "Total number of provider requests which were not sent because the rate limit of their store was exceeded",
+	}, []string{"kind", "name", "namespace"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(waitSeconds, throttledTotal)
+}
@@ -0,0 +1,215 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits the requests sent to a provider
+// according to the rateLimit of a store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// ThrottledError is returned instead of waiting for the rate limit
+// if a request would have to wait longer than the maximum wait time.
+type ThrottledError struct {
+	// RetryAfter is the time after which the request can be sent.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("rate limit of store exceeded, retry after %s", e.RetryAfter)
+}
+
+// limiter is the token bucket of a store.
+type limiter struct {
+	*rate.Limiter
+	qps   int32
+	burst int32
+
+	kind, name, namespace string
+}
+
+// Limiters holds one token bucket per store, which is shared
+// by all clients of the store. A nil Limiters does not limit.
+type Limiters struct {
+	maxWait time.Duration
+
+	mu       sync.Mutex
+	limiters map[types.UID]*limiter
+}
+
+// New returns Limiters which wait at most maxWait for a request.
+func New(maxWait time.Duration) *Limiters {
+	return &Limiters{
+		maxWait:  maxWait,
+		limiters: make(map[types.UID]*limiter),
+	}
+}
+
+// Wrap limits the requests of the client according to the rateLimit of the store.
+// The client is returned as is if the store has no rateLimit.
+func (l *Limiters) Wrap(store esv1beta1.GenericStore, secretClient esv1beta1.SecretsClient) esv1beta1.SecretsClient {
+	if l == nil {
+		return secretClient
+	}
+	spec := store.GetSpec().RateLimit
+	if spec == nil {
+		l.remove(store.GetObjectMeta().UID)
+		return secretClient
+	}
+	return &client{
+		SecretsClient: secretClient,
+		limiter:       l.get(store, spec),
+		maxWait:       l.maxWait,
+		kind:          storeKind(store),
+		name:          store.GetName(),
+		namespace:     store.GetNamespace(),
+	}
+}
+
+func storeKind(store esv1beta1.GenericStore) string {
+	if _, ok := store.(*esv1beta1.ClusterSecretStore); ok {
+		return esv1beta1.ClusterSecretStoreKind
+	}
+	return esv1beta1.SecretStoreKind
+}
+
+// get returns the token bucket of the store and
+// updates it if the rateLimit has been changed.
+func (l *Limiters) get(store esv1beta1.GenericStore, spec *esv1beta1.SecretStoreRateLimit) *rate.Limiter {
+	burst := spec.Burst
+	if burst == 0 {
+		burst = spec.QPS
+	}
+	uid := store.GetObjectMeta().UID
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lim, ok := l.limiters[uid]
+	if !ok {
+		lim = &limiter{
+			Limiter:   rate.NewLimiter(rate.Limit(spec.QPS), int(burst)),
+			kind:      storeKind(store),
+			name:      store.GetName(),
+			namespace: store.GetNamespace(),
+		}
+		l.limiters[uid] = lim
+	} else if lim.qps != spec.QPS || lim.burst != burst {
+		lim.SetLimit(rate.Limit(spec.QPS))
+		lim.SetBurst(int(burst))
+	}
+	lim.qps, lim.burst = spec.QPS, burst
+	return lim.Limiter
+}
+
+// remove drops the token bucket of a store whose rateLimit has been removed.
+func (l *Limiters) remove(uid types.UID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.limiters, uid)
+}
+
+// Forget drops the token bucket of a deleted store. It is called by the
+// store reconcilers, which only know the name of a deleted store.
+func (l *Limiters) Forget(kind, namespace, name string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for uid, lim := range l.limiters {
+		if lim.kind == kind && lim.namespace == namespace && lim.name == name {
+			delete(l.limiters, uid)
+		}
+	}
+}
+
+// client waits for the token bucket of its store before every request.
+type client struct {
+	esv1beta1.SecretsClient
+	limiter *rate.Limiter
+	maxWait time.Duration
+
+	kind, name, namespace string
+}
+
+func (c *client) wait(ctx context.Context) error {
+	r := c.limiter.Reserve()
+	delay := r.Delay()
+	if delay > c.maxWait {
+		r.Cancel()
+		throttledTotal.WithLabelValues(c.kind, c.name, c.namespace).Inc()
+		return &ThrottledError{RetryAfter: delay}
+	}
+	waitSeconds.WithLabelValues(c.kind, c.name, c.namespace).Observe(delay.Seconds())
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+func (c *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetSecret(ctx, ref)
+}
+
+func (c *client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetSecretMap(ctx, ref)
+}
+
+// GetSecretVersion does not call the provider and is not rate limited.
+func (c *client) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+func (c *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetAllSecrets(ctx, ref)
+}
+
+func (c *client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.SecretsClient.SetSecret(ctx, value, remoteRef)
+}
+
+func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
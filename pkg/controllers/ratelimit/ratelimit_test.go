@@ -0,0 +1,144 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// countingClient counts the requests it has received.
+type countingClient struct {
+	esv1beta1.SecretsClient
+	calls int
+}
+
+func (c *countingClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls++
+	return []byte("value"), nil
+}
+
+func makeStore(rateLimit *esv1beta1.SecretStoreRateLimit) *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "store",
+			Namespace: "default",
+			UID:       "uid",
+		},
+		Spec: esv1beta1.SecretStoreSpec{
+			RateLimit: rateLimit,
+		},
+	}
+}
+
+func TestWrapWithoutRateLimit(t *testing.T) {
+	inner := &countingClient{}
+	if c := New(0).Wrap(makeStore(nil), inner); c != inner {
+		t.Errorf("client of a store without rateLimit must not be wrapped")
+	}
+	var l *Limiters
+	store := makeStore(&esv1beta1.SecretStoreRateLimit{QPS: 1})
+	if c := l.Wrap(store, inner); c != inner {
+		t.Errorf("nil Limiters must not wrap the client")
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	l := New(0)
+	store := makeStore(&esv1beta1.SecretStoreRateLimit{QPS: 1, Burst: 2})
+	inner := &countingClient{}
+
+	// the token bucket is shared by all clients of the store
+	for i := 0; i < 2; i++ {
+		if _, err := l.Wrap(store, inner).GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err := l.Wrap(store, inner).GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{})
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("want ThrottledError, got %v", err)
+	}
+	if throttled.RetryAfter <= 0 {
+		t.Errorf("want positive RetryAfter, got %s", throttled.RetryAfter)
+	}
+	if inner.calls != 2 {
+		t.Errorf("want 2 requests, got %d", inner.calls)
+	}
+
+	// a changed rateLimit is applied to the existing token bucket
+	store.Spec.RateLimit = &esv1beta1.SecretStoreRateLimit{QPS: 5}
+	l.Wrap(store, inner)
+	if lim := l.limiters["uid"]; lim.Limit() != 5 || lim.Burst() != 5 {
+		t.Errorf("want limit 5 and burst 5, got %v and %d", lim.Limit(), lim.Burst())
+	}
+}
+
+func TestForget(t *testing.T) {
+	l := New(0)
+	store := makeStore(&esv1beta1.SecretStoreRateLimit{QPS: 1})
+	inner := &countingClient{}
+	l.Wrap(store, inner)
+
+	// a bucket is only dropped for the deleted store
+	l.Forget(esv1beta1.ClusterSecretStoreKind, "", "store")
+	l.Forget(esv1beta1.SecretStoreKind, "other", "store")
+	if len(l.limiters) != 1 {
+		t.Fatalf("want 1 token bucket, got %d", len(l.limiters))
+	}
+	l.Forget(esv1beta1.SecretStoreKind, "default", "store")
+	if len(l.limiters) != 0 {
+		t.Errorf("want no token bucket, got %d", len(l.limiters))
+	}
+
+	// the bucket is dropped once the rateLimit is removed
+	l.Wrap(store, inner)
+	store.Spec.RateLimit = nil
+	l.Wrap(store, inner)
+	if len(l.limiters) != 0 {
+		t.Errorf("want no token bucket, got %d", len(l.limiters))
+	}
+
+	var nilLimiters *Limiters
+	nilLimiters.Forget(esv1beta1.SecretStoreKind, "default", "store")
+}
+
+func TestWaitForToken(t *testing.T) {
+	l := New(time.Second)
+	store := makeStore(&esv1beta1.SecretStoreRateLimit{QPS: 100, Burst: 1})
+	inner := &countingClient{}
+	c := l.Wrap(store, inner)
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// a cancelled request gives its token back
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("want 3 requests, got %d", inner.calls)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry retries provider requests which failed with a
+// transient error according to the retrySettings of a store.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	// defaultMaxRetries is used if retrySettings.maxRetries is not set.
+	defaultMaxRetries = 3
+	// defaultRetryInterval is used if retrySettings.retryInterval is not set.
+	defaultRetryInterval = time.Second
+	// maxRetryInterval caps the exponential backoff.
+	maxRetryInterval = time.Minute
+
+	errRetryInterval = "invalid retryInterval: %w"
+)
+
+// Wrap retries the requests of the client according to the retrySettings of the store.
+// The client is returned as is if the store has no retrySettings or if the
+// provider applies them itself.
+func Wrap(store esv1beta1.GenericStore, secretClient esv1beta1.SecretsClient) (esv1beta1.SecretsClient, error) {
+	spec := store.GetSpec()
+	if spec.RetrySettings == nil || retriesItself(spec.Provider) {
+		return secretClient, nil
+	}
+	maxRetries := defaultMaxRetries
+	if spec.RetrySettings.MaxRetries != nil {
+		maxRetries = int(*spec.RetrySettings.MaxRetries)
+	}
+	interval := defaultRetryInterval
+	if spec.RetrySettings.RetryInterval != nil {
+		var err error
+		interval, err = time.ParseDuration(*spec.RetrySettings.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf(errRetryInterval, err)
+		}
+	}
+	if maxRetries <= 0 {
+		return secretClient, nil
+	}
+	return &client{
+		SecretsClient: secretClient,
+		maxRetries:    maxRetries,
+		interval:      interval,
+	}, nil
+}
+
+// retriesItself returns true for providers whose SDK applies the
+// retrySettings, these SDKs also retry on provider specific throttling errors.
+func retriesItself(provider *esv1beta1.SecretStoreProvider) bool {
+	return provider != nil && (provider.AWS != nil || provider.IBM != nil)
+}
+
+// isTransient returns true if a request which failed with the error may succeed
+// when it is sent again. Missing secrets, authentication and authorization
+// failures are never transient. Errors which have been classified by the
+// provider are retried if they are throttled or transient, unless the provider
+// tells when to retry: then the controller tries again after that delay.
+func isTransient(err error) bool {
+	var throttled esv1beta1.ThrottledError
+	switch {
+	case err == nil,
+		errors.Is(err, esv1beta1.NoSecretErr),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &esv1beta1.AuthError{}),
+		errors.As(err, &esv1beta1.PermissionDeniedError{}),
+		errors.As(err, &esv1beta1.InvalidRefError{}):
+		return false
+	case errors.As(err, &throttled):
+		return throttled.RetryAfter == 0
+	case errors.As(err, &esv1beta1.TransientError{}):
+		return true
+	case errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED):
+		return true
+	case apierrors.IsUnauthorized(err), apierrors.IsForbidden(err):
+		return false
+	case apierrors.IsTooManyRequests(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsInternalError(err):
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// client retries requests which failed with a transient error
+// with an exponential backoff.
+type client struct {
+	esv1beta1.SecretsClient
+	maxRetries int
+	interval   time.Duration
+}
+
+// backoff returns the time to wait before the given retry, starting at 0.
+// The interval is doubled on every retry and jittered between half and
+// the full interval, so that clients which failed together do not retry together.
+func (c *client) backoff(retry int) time.Duration {
+	d := c.interval
+	for i := 0; i < retry && d < maxRetryInterval; i++ {
+		d *= 2
+	}
+	if d > maxRetryInterval {
+		d = maxRetryInterval
+	}
+	return wait.Jitter(d/2, 1)
+}
+
+func (c *client) do(ctx context.Context, req func() error) error {
+	err := req()
+	for retry := 0; retry < c.maxRetries && isTransient(err); retry++ {
+		timer := time.NewTimer(c.backoff(retry))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		err = req()
+	}
+	return err
+}
+
+func (c *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	var secret []byte
+	err := c.do(ctx, func() error {
+		var err error
+		secret, err = c.SecretsClient.GetSecret(ctx, ref)
+		return err
+	})
+	return secret, err
+}
+
+func (c *client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	var secretMap map[string][]byte
+	err := c.do(ctx, func() error {
+		var err error
+		secretMap, err = c.SecretsClient.GetSecretMap(ctx, ref)
+		return err
+	})
+	return secretMap, err
+}
+
+// GetSecretVersion does not call the provider and is not retried.
+func (c *client) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+func (c *client) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	var secretMap map[string][]byte
+	err := c.do(ctx, func() error {
+		var err error
+		secretMap, err = c.SecretsClient.GetAllSecrets(ctx, ref)
+		return err
+	})
+	return secretMap, err
+}
+
+func (c *client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return c.do(ctx, func() error {
+		return c.SecretsClient.SetSecret(ctx, value, remoteRef)
+	})
+}
+
+func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return c.do(ctx, func() error {
+		return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+	})
+}
@@ -0,0 +1,194 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// failingClient fails with err until it has been called failures times.
+type failingClient struct {
+	esv1beta1.SecretsClient
+	err      error
+	failures int
+	calls    int
+}
+
+func (c *failingClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return []byte("value"), nil
+}
+
+func makeStore(maxRetries int32, provider *esv1beta1.SecretStoreProvider) *esv1beta1.SecretStore {
+	interval := "1ms"
+	return &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: provider,
+			RetrySettings: &esv1beta1.SecretStoreRetrySettings{
+				MaxRetries:    &maxRetries,
+				RetryInterval: &interval,
+			},
+		},
+	}
+}
+
+func TestRetry(t *testing.T) {
+	transient := fmt.Errorf("could not get secret: %w", syscall.ECONNRESET)
+	tbl := []struct {
+		name      string
+		err       error
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "retry transient error",
+			err:       transient,
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:      "give up after maxRetries",
+			err:       transient,
+			failures:  10,
+			wantCalls: 4,
+			wantErr:   true,
+		},
+		{
+			name:      "do not retry missing secret",
+			err:       esv1beta1.NoSecretError{},
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "do not retry auth failure",
+			err:       apierrors.NewUnauthorized("invalid token"),
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retry throttled request",
+			err:       apierrors.NewTooManyRequests("slow down", 1),
+			failures:  1,
+			wantCalls: 2,
+		},
+		{
+			name:      "retry error classified as transient",
+			err:       esv1beta1.TransientError{Err: errors.New("unavailable")},
+			failures:  1,
+			wantCalls: 2,
+		},
+		{
+			name:      "do not retry request throttled with retry after",
+			err:       esv1beta1.ThrottledError{Err: errors.New("slow down"), RetryAfter: time.Minute},
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "do not retry error classified as permission denied",
+			err:       esv1beta1.PermissionDeniedError{Err: errors.New("access denied")},
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "do not retry unknown error",
+			err:       errors.New("boom"),
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tbl {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &failingClient{err: tt.err, failures: tt.failures}
+			c, err := Wrap(makeStore(3, nil), inner)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, err = c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+			if inner.calls != tt.wantCalls {
+				t.Errorf("want %d calls, got %d", tt.wantCalls, inner.calls)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	inner := &failingClient{}
+	if c, _ := Wrap(&esv1beta1.SecretStore{}, inner); c != inner {
+		t.Errorf("client of a store without retrySettings must not be wrapped")
+	}
+	if c, _ := Wrap(makeStore(3, &esv1beta1.SecretStoreProvider{AWS: &esv1beta1.AWSProvider{}}), inner); c != inner {
+		t.Errorf("client of a provider which retries itself must not be wrapped")
+	}
+	if c, _ := Wrap(makeStore(0, nil), inner); c != inner {
+		t.Errorf("client must not be wrapped if maxRetries is 0")
+	}
+	store := makeStore(3, nil)
+	interval := "soon"
+	store.Spec.RetrySettings.RetryInterval = &interval
+	if _, err := Wrap(store, inner); err == nil {
+		t.Errorf("want error for invalid retryInterval")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &client{interval: time.Second}
+	for retry, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if d := c.backoff(retry); d < want/2 || d >= want {
+			t.Errorf("retry %d: want backoff in [%s, %s), got %s", retry, want/2, want, d)
+		}
+	}
+	if d := c.backoff(100); d >= maxRetryInterval {
+		t.Errorf("want backoff below %s, got %s", maxRetryInterval, d)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	store := makeStore(3, nil)
+	interval := "1h"
+	store.Spec.RetrySettings.RetryInterval = &interval
+	inner := &failingClient{err: syscall.ECONNREFUSED, failures: 10}
+	c, err := Wrap(store, inner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{})
+	if !errors.Is(err, syscall.ECONNREFUSED) || inner.calls != 1 {
+		t.Errorf("want the first error without retries, got %v after %d calls", err, inner.calls)
+	}
+}
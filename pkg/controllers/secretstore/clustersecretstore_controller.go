@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
 
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
@@ -38,6 +39,7 @@ type ClusterStoreReconciler struct {
 	Scheme          *runtime.Scheme
 	ControllerClass string
 	RequeueInterval time.Duration
+	RateLimiters    *ratelimit.Limiters
 	recorder        record.EventRecorder
 }
 
@@ -46,6 +48,8 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	var css esapi.ClusterSecretStore
 	err := r.Get(ctx, req.NamespacedName, &css)
 	if apierrors.IsNotFound(err) {
+		deleteStoreCondition(esapi.ClusterSecretStoreKind, req.Name, req.Namespace)
+		r.RateLimiters.Forget(esapi.ClusterSecretStoreKind, req.Namespace, req.Name)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get ClusterSecretStore")
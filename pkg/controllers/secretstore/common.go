@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/providermetrics"
 )
 
 const (
@@ -57,6 +58,7 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 		if err != nil {
 			log.Error(err, errPatchStatus)
 		}
+		updateStoreCondition(ss)
 	}()
 
 	// validateStore modifies the store conditions
@@ -89,7 +91,7 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 		return fmt.Errorf(errStoreProvider, err)
 	}
 
-	cl, err := storeProvider.NewClient(ctx, store, client, namespace)
+	cl, err := providermetrics.WrapProvider(storeProvider).NewClient(ctx, store, client, namespace)
 	if err != nil {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonInvalidProviderConfig, errUnableCreateClient)
 		SetExternalSecretCondition(store, *cond)
@@ -98,7 +100,7 @@ func validateStore(ctx context.Context, namespace string, store esapi.GenericSto
 	}
 	defer cl.Close(ctx)
 
-	validationResult, err := cl.Validate()
+	validationResult, err := providermetrics.Wrap(store, cl).Validate()
 	if err != nil && validationResult != esapi.ValidationResultUnknown {
 		cond := NewSecretStoreCondition(esapi.SecretStoreReady, v1.ConditionFalse, esapi.ReasonValidationFailed, errUnableValidateStore)
 		SetExternalSecretCondition(store, *cond)
@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -121,6 +122,14 @@ var _ = Describe("SecretStore reconcile", func() {
 				WithTimeout(time.Second * 10).
 				WithPolling(time.Second).
 				Should(BeTrue())
+
+			Eventually(func() float64 {
+				return readyGauge(tc.store.GetTypeMeta().Kind, tc.store.GetNamespace(), corev1.ConditionTrue)
+			}).
+				WithTimeout(time.Second * 10).
+				WithPolling(time.Second).
+				Should(Equal(1.0))
+			Expect(readyGauge(tc.store.GetTypeMeta().Kind, tc.store.GetNamespace(), corev1.ConditionFalse)).To(Equal(0.0))
 		}
 
 	}
@@ -195,6 +204,13 @@ func useClusterStore(tc *testCase) {
 	}
 }
 
+// readyGauge returns the value of the Ready condition metric of the default store.
+func readyGauge(kind, namespace string, status corev1.ConditionStatus) float64 {
+	var metric dto.Metric
+	Expect(secretStoreCondition.WithLabelValues(kind, defaultStoreName, namespace, string(esapi.SecretStoreReady), string(status)).Write(&metric)).To(Succeed())
+	return metric.GetGauge().GetValue()
+}
+
 func hasEvent(involvedKind, name, reason string) bool {
 	el := &corev1.EventList{}
 	err := k8sClient.List(context.Background(), el)
@@ -0,0 +1,82 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const (
+	SecretStoreSubsystem          = "secretstore"
+	secretStoreStatusConditionKey = "status_condition"
+)
+
+var secretStoreCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Subsystem: SecretStoreSubsystem,
+	Name:      secretStoreStatusConditionKey,
+	Help:      "The status condition of a specific SecretStore or ClusterSecretStore",
+}, []string{"kind", "name", "namespace", "condition", "status"})
+
+// updateStoreCondition exports the Ready condition of the store.
+// The series of the opposite status is set to 0.
+func updateStoreCondition(store esapi.GenericStore) {
+	cond := GetSecretStoreCondition(store.GetStatus(), esapi.SecretStoreReady)
+	if cond == nil {
+		return
+	}
+	for _, status := range []v1.ConditionStatus{v1.ConditionTrue, v1.ConditionFalse} {
+		value := 0.0
+		if cond.Status == status {
+			value = 1
+		}
+		secretStoreCondition.With(prometheus.Labels{
+			"kind":      storeKind(store),
+			"name":      store.GetName(),
+			"namespace": store.GetNamespace(),
+			"condition": string(esapi.SecretStoreReady),
+			"status":    string(status),
+		}).Set(value)
+	}
+}
+
+// deleteStoreCondition removes the condition metrics of a deleted store.
+func deleteStoreCondition(kind, name, namespace string) {
+	for _, status := range []v1.ConditionStatus{v1.ConditionTrue, v1.ConditionFalse} {
+		secretStoreCondition.Delete(prometheus.Labels{
+			"kind":      kind,
+			"name":      name,
+			"namespace": namespace,
+			"condition": string(esapi.SecretStoreReady),
+			"status":    string(status),
+		})
+	}
+}
+
+// storeKind returns the kind of the store, the TypeMeta
+// of a store read by the client is not always set.
+func storeKind(store esapi.GenericStore) string {
+	if _, ok := store.(*esapi.ClusterSecretStore); ok {
+		return esapi.ClusterSecretStoreKind
+	}
+	return esapi.SecretStoreKind
+}
+
+func init() {
+	metrics.Registry.MustRegister(secretStoreCondition)
+}
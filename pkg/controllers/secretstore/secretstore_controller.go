@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/ratelimit"
 
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
@@ -38,6 +39,7 @@ type StoreReconciler struct {
 	Scheme          *runtime.Scheme
 	recorder        record.EventRecorder
 	RequeueInterval time.Duration
+	RateLimiters    *ratelimit.Limiters
 	ControllerClass string
 }
 
@@ -46,6 +48,8 @@ func (r *StoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	var ss esapi.SecretStore
 	err := r.Get(ctx, req.NamespacedName, &ss)
 	if apierrors.IsNotFound(err) {
+		deleteStoreCondition(esapi.SecretStoreKind, req.Name, req.Namespace)
+		r.RateLimiters.Forget(esapi.SecretStoreKind, req.Namespace, req.Name)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get SecretStore")
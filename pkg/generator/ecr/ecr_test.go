@@ -0,0 +1,135 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+type fakeECR struct {
+	ecriface.ECRAPI
+	out *ecr.GetAuthorizationTokenOutput
+	err error
+}
+
+func (f *fakeECR) GetAuthorizationTokenWithContext(aws.Context, *ecr.GetAuthorizationTokenInput, ...request.Option) (*ecr.GetAuthorizationTokenOutput, error) {
+	return f.out, f.err
+}
+
+func TestGenerate(t *testing.T) {
+	expiry := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	spec := &apiextensions.JSON{Raw: []byte(`{"spec":{"region":"eu-west-1"}}`)}
+	tbl := []struct {
+		name    string
+		spec    *apiextensions.JSON
+		fake    *fakeECR
+		want    map[string][]byte
+		wantErr string
+	}{
+		{
+			name:    "no spec",
+			spec:    nil,
+			fake:    &fakeECR{},
+			wantErr: errNoSpec,
+		},
+		{
+			name: "api error",
+			spec: spec,
+			fake: &fakeECR{
+				err: errors.New("boom"),
+			},
+			wantErr: "unable to get authorization token: boom",
+		},
+		{
+			name: "no token",
+			spec: spec,
+			fake: &fakeECR{
+				out: &ecr.GetAuthorizationTokenOutput{},
+			},
+			wantErr: errNoToken,
+		},
+		{
+			name: "invalid token",
+			spec: spec,
+			fake: &fakeECR{
+				out: &ecr.GetAuthorizationTokenOutput{
+					AuthorizationData: []*ecr.AuthorizationData{{
+						AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("nocolon"))),
+					}},
+				},
+			},
+			wantErr: errInvalidTok,
+		},
+		{
+			name: "token",
+			spec: spec,
+			fake: &fakeECR{
+				out: &ecr.GetAuthorizationTokenOutput{
+					AuthorizationData: []*ecr.AuthorizationData{{
+						AuthorizationToken: aws.String(base64.StdEncoding.EncodeToString([]byte("AWS:secret"))),
+						ProxyEndpoint:      aws.String("https://123456789012.dkr.ecr.eu-west-1.amazonaws.com"),
+						ExpiresAt:          aws.Time(expiry),
+					}},
+				},
+			},
+			want: map[string][]byte{
+				keyRegistry: []byte("123456789012.dkr.ecr.eu-west-1.amazonaws.com"),
+				keyUsername: []byte("AWS"),
+				keyPassword: []byte("secret"),
+				keyExpiry:   []byte("2022-08-01T12:00:00Z"),
+			},
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.name, func(t *testing.T) {
+			g := &Generator{}
+			got, err := g.generate(context.Background(), row.spec, nil, "default", func(*session.Session) ecriface.ECRAPI {
+				return row.fake
+			})
+			if row.wantErr != "" {
+				if err == nil || err.Error() != row.wantErr {
+					t.Fatalf("unexpected error: got %v, want %s", err, row.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, row.want) {
+				t.Errorf("unexpected result: got %v, want %v", got, row.want)
+			}
+			exp, err := g.ExpiresAt(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !exp.Equal(expiry) {
+				t.Errorf("unexpected expiry: got %v, want %v", exp, expiry)
+			}
+		})
+	}
+}
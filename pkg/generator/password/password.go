@@ -0,0 +1,166 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// Generator creates random passwords.
+type Generator struct{}
+
+const (
+	defaultLength      = 24
+	defaultSymbolChars = "~!@#$%^&*()_+`-={}|[]\\:\"<>?,./"
+	digitChars         = "0123456789"
+	lowerChars         = "abcdefghijklmnopqrstuvwxyz"
+	upperChars         = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	errNoSpec           = "no config spec provided"
+	errParseSpec        = "unable to parse spec: %w"
+	errInvalidLength    = "invalid length %d: must be greater than 0"
+	errInvalidCount     = "invalid number of %s %d: must not be negative"
+	errTooManyChars     = "digits (%d) and symbols (%d) exceed the length %d"
+	errEmptySymbolSet   = "symbolCharacters must not be empty if symbols are requested"
+	errGeneratePassword = "unable to generate password: %w"
+)
+
+func (g *Generator) Generate(ctx context.Context, jsonSpec *apiextensions.JSON, kube client.Client, namespace string) (map[string][]byte, error) {
+	if jsonSpec == nil {
+		return nil, errors.New(errNoSpec)
+	}
+	res, err := parseSpec(jsonSpec.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(errParseSpec, err)
+	}
+	pass, err := generate(res.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"password": pass,
+	}, nil
+}
+
+// generate creates a password which consists of exactly the requested
+// number of digits and symbols, the remaining characters are letters.
+func generate(spec genv1alpha1.PasswordSpec) ([]byte, error) {
+	length := spec.Length
+	if length == 0 {
+		length = defaultLength
+	}
+	if length < 0 {
+		return nil, fmt.Errorf(errInvalidLength, length)
+	}
+	digits := length / 4
+	if spec.Digits != nil {
+		digits = *spec.Digits
+	}
+	symbols := length / 4
+	if spec.Symbols != nil {
+		symbols = *spec.Symbols
+	}
+	if digits < 0 {
+		return nil, fmt.Errorf(errInvalidCount, "digits", digits)
+	}
+	if symbols < 0 {
+		return nil, fmt.Errorf(errInvalidCount, "symbols", symbols)
+	}
+	if digits+symbols > length {
+		return nil, fmt.Errorf(errTooManyChars, digits, symbols, length)
+	}
+	symbolChars := defaultSymbolChars
+	if spec.SymbolCharacters != nil {
+		symbolChars = *spec.SymbolCharacters
+	}
+	if symbols > 0 && symbolChars == "" {
+		return nil, errors.New(errEmptySymbolSet)
+	}
+	letterChars := lowerChars
+	if !spec.NoUpper {
+		letterChars += upperChars
+	}
+
+	pass := make([]byte, 0, length)
+	var err error
+	pass, err = appendRandom(pass, digitChars, digits)
+	if err != nil {
+		return nil, fmt.Errorf(errGeneratePassword, err)
+	}
+	pass, err = appendRandom(pass, symbolChars, symbols)
+	if err != nil {
+		return nil, fmt.Errorf(errGeneratePassword, err)
+	}
+	pass, err = appendRandom(pass, letterChars, length-digits-symbols)
+	if err != nil {
+		return nil, fmt.Errorf(errGeneratePassword, err)
+	}
+	if err := shuffle(pass); err != nil {
+		return nil, fmt.Errorf(errGeneratePassword, err)
+	}
+	return pass, nil
+}
+
+// appendRandom appends n characters picked randomly from charset to dst.
+func appendRandom(dst []byte, charset string, n int) ([]byte, error) {
+	for i := 0; i < n; i++ {
+		idx, err := randInt(len(charset))
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, charset[idx])
+	}
+	return dst, nil
+}
+
+// shuffle randomly permutes b in place using the Fisher-Yates algorithm.
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return nil
+}
+
+func randInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+func parseSpec(data []byte) (*genv1alpha1.Password, error) {
+	var spec genv1alpha1.Password
+	err := json.Unmarshal(data, &spec)
+	return &spec, err
+}
+
+func init() {
+	genv1alpha1.Register(genv1alpha1.PasswordKind, &Generator{})
+}
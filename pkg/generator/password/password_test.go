@@ -0,0 +1,172 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package password
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func count(pass []byte, charset string) int {
+	var n int
+	for _, c := range pass {
+		if strings.ContainsRune(charset, rune(c)) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGenerate(t *testing.T) {
+	tbl := []struct {
+		name       string
+		spec       genv1alpha1.PasswordSpec
+		expLength  int
+		expDigits  int
+		expSymbols int
+		symbols    string
+		expErr     string
+	}{
+		{
+			name:       "defaults",
+			spec:       genv1alpha1.PasswordSpec{},
+			expLength:  24,
+			expDigits:  6,
+			expSymbols: 6,
+			symbols:    defaultSymbolChars,
+		},
+		{
+			name: "custom counts",
+			spec: genv1alpha1.PasswordSpec{
+				Length:  10,
+				Digits:  intPtr(3),
+				Symbols: intPtr(0),
+			},
+			expLength:  10,
+			expDigits:  3,
+			expSymbols: 0,
+			symbols:    defaultSymbolChars,
+		},
+		{
+			name: "custom symbol characters",
+			spec: genv1alpha1.PasswordSpec{
+				Length:           16,
+				Symbols:          intPtr(8),
+				SymbolCharacters: strPtr("-_"),
+			},
+			expLength:  16,
+			expDigits:  4,
+			expSymbols: 8,
+			symbols:    "-_",
+		},
+		{
+			name: "no upper case letters",
+			spec: genv1alpha1.PasswordSpec{
+				Length:  32,
+				Digits:  intPtr(0),
+				Symbols: intPtr(0),
+				NoUpper: true,
+			},
+			expLength:  32,
+			expDigits:  0,
+			expSymbols: 0,
+			symbols:    defaultSymbolChars,
+		},
+		{
+			name: "too many digits and symbols",
+			spec: genv1alpha1.PasswordSpec{
+				Length:  4,
+				Digits:  intPtr(3),
+				Symbols: intPtr(2),
+			},
+			expErr: "digits (3) and symbols (2) exceed the length 4",
+		},
+		{
+			name: "negative digits",
+			spec: genv1alpha1.PasswordSpec{
+				Length: 4,
+				Digits: intPtr(-1),
+			},
+			expErr: "invalid number of digits -1: must not be negative",
+		},
+		{
+			name: "empty symbol characters",
+			spec: genv1alpha1.PasswordSpec{
+				Length:           4,
+				Symbols:          intPtr(1),
+				SymbolCharacters: strPtr(""),
+			},
+			expErr: errEmptySymbolSet,
+		},
+	}
+	for i := range tbl {
+		row := tbl[i]
+		t.Run(row.name, func(t *testing.T) {
+			pass, err := generate(row.spec)
+			if row.expErr != "" {
+				if err == nil || err.Error() != row.expErr {
+					t.Fatalf("unexpected error: got %v, want %s", err, row.expErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(pass) != row.expLength {
+				t.Errorf("unexpected length: got %d, want %d", len(pass), row.expLength)
+			}
+			if n := count(pass, digitChars); n != row.expDigits {
+				t.Errorf("unexpected number of digits: got %d, want %d", n, row.expDigits)
+			}
+			if n := count(pass, row.symbols); n != row.expSymbols {
+				t.Errorf("unexpected number of symbols: got %d, want %d", n, row.expSymbols)
+			}
+			if row.spec.NoUpper && count(pass, upperChars) != 0 {
+				t.Errorf("unexpected upper case letters in %q", pass)
+			}
+		})
+	}
+}
+
+func TestGenerateFromJSON(t *testing.T) {
+	gen := &Generator{}
+	res, err := gen.Generate(context.Background(), &apiextensions.JSON{
+		Raw: []byte(`{"spec":{"length":12,"digits":2,"symbols":2}}`),
+	}, nil, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res["password"]) != 12 {
+		t.Errorf("unexpected password length: %d", len(res["password"]))
+	}
+
+	_, err = gen.Generate(context.Background(), nil, nil, "default")
+	if err == nil || err.Error() != errNoSpec {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 
@@ -28,12 +29,23 @@ import (
 	azure_cloud_id "github.com/akeylesslabs/akeyless-go-cloud-id/cloudprovider/azure"
 	gcp_cloud_id "github.com/akeylesslabs/akeyless-go-cloud-id/cloudprovider/gcp"
 	"github.com/akeylesslabs/akeyless-go/v2"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 var apiErr akeyless.GenericOpenAPIError
 
 const DefServiceAccountFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 
+// classifyErr classifies the error of an Akeyless API request
+// by the HTTP status code of its response.
+func classifyErr(resp *http.Response, err error) error {
+	if resp == nil {
+		return err
+	}
+	return esv1beta1.FromHTTPStatus(resp.StatusCode, err)
+}
+
 func (a *akeylessBase) GetToken(accessID, accType, accTypeParam string) (string, error) {
 	ctx := context.Background()
 	authBody := akeyless.NewAuthWithDefaults()
@@ -58,12 +70,12 @@ func (a *akeylessBase) GetToken(accessID, accType, accTypeParam string) (string,
 		authBody.CloudId = akeyless.PtrString(cloudID)
 	}
 
-	authOut, _, err := a.RestAPI.Auth(ctx).Body(*authBody).Execute()
+	authOut, resp, err := a.RestAPI.Auth(ctx).Body(*authBody).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
-			return "", fmt.Errorf("authentication failed: %v", string(apiErr.Body()))
+			return "", classifyErr(resp, fmt.Errorf("authentication failed: %v", string(apiErr.Body())))
 		}
-		return "", fmt.Errorf("authentication failed: %w", err)
+		return "", classifyErr(resp, fmt.Errorf("authentication failed: %w", err))
 	}
 
 	token := authOut.GetToken()
@@ -100,12 +112,12 @@ func (a *akeylessBase) DescribeItem(itemName, token string) (*akeyless.Item, err
 	} else {
 		body.Token = &token
 	}
-	gsvOut, _, err := a.RestAPI.DescribeItem(ctx).Body(body).Execute()
+	gsvOut, resp, err := a.RestAPI.DescribeItem(ctx).Body(body).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
-			return nil, fmt.Errorf("can't describe item: %v", string(apiErr.Body()))
+			return nil, classifyErr(resp, fmt.Errorf("can't describe item: %v", string(apiErr.Body())))
 		}
-		return nil, fmt.Errorf("can't describe item: %w", err)
+		return nil, classifyErr(resp, fmt.Errorf("can't describe item: %w", err))
 	}
 
 	return &gsvOut, nil
@@ -124,12 +136,12 @@ func (a *akeylessBase) GetRotatedSecrets(secretName, token string, version int32
 		body.Token = &token
 	}
 
-	gsvOut, _, err := a.RestAPI.GetRotatedSecretValue(ctx).Body(body).Execute()
+	gsvOut, resp, err := a.RestAPI.GetRotatedSecretValue(ctx).Body(body).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
-			return "", fmt.Errorf("can't get rotated secret value: %v", string(apiErr.Body()))
+			return "", classifyErr(resp, fmt.Errorf("can't get rotated secret value: %v", string(apiErr.Body())))
 		}
-		return "", fmt.Errorf("can't get rotated secret value: %w", err)
+		return "", classifyErr(resp, fmt.Errorf("can't get rotated secret value: %w", err))
 	}
 
 	val, ok := gsvOut["value"]
@@ -169,12 +181,12 @@ func (a *akeylessBase) GetDynamicSecrets(secretName, token string) (string, erro
 		body.Token = &token
 	}
 
-	gsvOut, _, err := a.RestAPI.GetDynamicSecretValue(ctx).Body(body).Execute()
+	gsvOut, resp, err := a.RestAPI.GetDynamicSecretValue(ctx).Body(body).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
-			return "", fmt.Errorf("can't get dynamic secret value: %v", string(apiErr.Body()))
+			return "", classifyErr(resp, fmt.Errorf("can't get dynamic secret value: %v", string(apiErr.Body())))
 		}
-		return "", fmt.Errorf("can't get dynamic secret value: %w", err)
+		return "", classifyErr(resp, fmt.Errorf("can't get dynamic secret value: %w", err))
 	}
 
 	out, err := json.Marshal(gsvOut)
@@ -199,12 +211,12 @@ func (a *akeylessBase) GetStaticSecret(secretName, token string, version int32)
 		gsvBody.Token = &token
 	}
 
-	gsvOut, _, err := a.RestAPI.GetSecretValue(ctx).Body(gsvBody).Execute()
+	gsvOut, resp, err := a.RestAPI.GetSecretValue(ctx).Body(gsvBody).Execute()
 	if err != nil {
 		if errors.As(err, &apiErr) {
-			return "", fmt.Errorf("can't get secret value: %v", string(apiErr.Body()))
+			return "", classifyErr(resp, fmt.Errorf("can't get secret value: %v", string(apiErr.Body())))
 		}
-		return "", fmt.Errorf("can't get secret value: %w", err)
+		return "", classifyErr(resp, fmt.Errorf("can't get secret value: %w", err))
 	}
 	val, ok := gsvOut[secretName]
 	if !ok {
@@ -20,6 +20,7 @@ import (
 
 type AlibabaMockClient struct {
 	getSecretValue func(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	describeSecret func(request *kmssdk.DescribeSecretRequest) (response *kmssdk.DescribeSecretResponse, err error)
 }
 
 func (mc *AlibabaMockClient) GetSecretValue(*kmssdk.GetSecretValueRequest) (result *kmssdk.GetSecretValueResponse, err error) {
@@ -33,3 +34,19 @@ func (mc *AlibabaMockClient) WithValue(in *kmssdk.GetSecretValueRequest, val *km
 		}
 	}
 }
+
+func (mc *AlibabaMockClient) DescribeSecret(in *kmssdk.DescribeSecretRequest) (result *kmssdk.DescribeSecretResponse, err error) {
+	return mc.describeSecret(in)
+}
+
+func (mc *AlibabaMockClient) WithTags(tags map[string]string, err error) {
+	if mc != nil {
+		mc.describeSecret = func(paramIn *kmssdk.DescribeSecretRequest) (*kmssdk.DescribeSecretResponse, error) {
+			out := &kmssdk.DescribeSecretResponse{SecretName: paramIn.SecretName}
+			for k, v := range tags {
+				out.Tags.Tag = append(out.Tags.Tag, kmssdk.Tag{TagKey: k, TagValue: v})
+			}
+			return out, err
+		}
+	}
+}
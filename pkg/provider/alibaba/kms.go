@@ -17,9 +17,13 @@ package alibaba
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	alierrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
 	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
@@ -55,6 +59,7 @@ type Client struct {
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &KeyManagementService{}
 var _ esv1beta1.Provider = &KeyManagementService{}
+var _ esv1beta1.MetadataFetcher = &KeyManagementService{}
 
 type KeyManagementService struct {
 	Client SMInterface
@@ -63,6 +68,7 @@ type KeyManagementService struct {
 
 type SMInterface interface {
 	GetSecretValue(request *kmssdk.GetSecretValueRequest) (response *kmssdk.GetSecretValueResponse, err error)
+	DescribeSecret(request *kmssdk.DescribeSecretRequest) (response *kmssdk.DescribeSecretResponse, err error)
 }
 
 // setAuth creates a new Alibaba session based on a store.
@@ -120,18 +126,31 @@ func (kms *KeyManagementService) GetAllSecrets(ctx context.Context, ref esv1beta
 	return nil, fmt.Errorf("GetAllSecrets not implemented")
 }
 
+// SetSecret is not implemented for this provider.
+func (kms *KeyManagementService) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (kms *KeyManagementService) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 // GetSecret returns a single secret from the provider.
 func (kms *KeyManagementService) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if utils.IsNil(kms.Client) {
 		return nil, fmt.Errorf(errUninitalizedAlibabaProvider)
 	}
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return kms.getTags(ref)
+	}
 	kmsRequest := kmssdk.CreateGetSecretValueRequest()
 	kmsRequest.VersionId = ref.Version
 	kmsRequest.SecretName = ref.Key
 	kmsRequest.SetScheme("https")
 	secretOut, err := kms.Client.GetSecretValue(kmsRequest)
 	if err != nil {
-		return nil, util.SanitizeErr(err)
+		return nil, classifyErr(err)
 	}
 	if ref.Property == "" {
 		if secretOut.SecretData != "" {
@@ -150,6 +169,23 @@ func (kms *KeyManagementService) GetSecret(ctx context.Context, ref esv1beta1.Ex
 	return []byte(val.String()), nil
 }
 
+// getTags returns the tags of the secret instead of its value.
+func (kms *KeyManagementService) getTags(ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	kmsRequest := kmssdk.CreateDescribeSecretRequest()
+	kmsRequest.SecretName = ref.Key
+	kmsRequest.FetchTags = "true"
+	kmsRequest.SetScheme("https")
+	secretOut, err := kms.Client.DescribeSecret(kmsRequest)
+	if err != nil {
+		return nil, classifyErr(err)
+	}
+	tags := make(map[string]string, len(secretOut.Tags.Tag))
+	for _, tag := range secretOut.Tags.Tag {
+		tags[tag.TagKey] = tag.TagValue
+	}
+	return utils.GetMetadataValue(tags, ref.Property)
+}
+
 // GetSecretMap returns multiple k/v pairs from the provider.
 func (kms *KeyManagementService) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	data, err := kms.GetSecret(ctx, ref)
@@ -207,6 +243,11 @@ func (kms *KeyManagementService) Validate() (esv1beta1.ValidationResult, error)
 	return esv1beta1.ValidationResultReady, nil
 }
 
+// SupportsMetadataFetch returns true as the tags of a secret can be fetched.
+func (kms *KeyManagementService) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	return true
+}
+
 func (kms *KeyManagementService) ValidateStore(store esv1beta1.GenericStore) error {
 	storeSpec := store.GetSpec()
 	alibabaSpec := storeSpec.Provider.Alibaba
@@ -253,3 +294,17 @@ func init() {
 		Alibaba: &esv1beta1.AlibabaProvider{},
 	})
 }
+
+// classifyErr classifies an error of the KMS API by its error code and HTTP status code.
+// Alibaba Cloud answers throttled requests with 400 and a Throttling error code.
+func classifyErr(err error) error {
+	sanitized := util.SanitizeErr(err)
+	var srvErr *alierrors.ServerError
+	if !errors.As(err, &srvErr) {
+		return sanitized
+	}
+	if strings.HasPrefix(srvErr.ErrorCode(), "Throttling") {
+		return esv1beta1.FromHTTPStatus(http.StatusTooManyRequests, sanitized)
+	}
+	return esv1beta1.FromHTTPStatus(srvErr.HttpStatus(), sanitized)
+}
@@ -16,11 +16,13 @@ package alibaba
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	alierrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	kmssdk "github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
 
@@ -153,6 +155,26 @@ func TestAlibabaKMSGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecretClassifiesErrors(t *testing.T) {
+	tbl := []struct {
+		err  error
+		want interface{}
+	}{
+		{err: alierrors.NewServerError(400, `{"Code":"Throttling.User"}`, ""), want: &esv1beta1.ThrottledError{}},
+		{err: alierrors.NewServerError(403, `{"Code":"Forbidden.NoPermission"}`, ""), want: &esv1beta1.PermissionDeniedError{}},
+		{err: alierrors.NewServerError(503, `{"Code":"ServiceUnavailable"}`, ""), want: &esv1beta1.TransientError{}},
+	}
+	for _, row := range tbl {
+		kmstc := makeValidKMSTestCaseCustom(func(kmstc *keyManagementServiceTestCase) {
+			kmstc.apiErr = row.err
+		})
+		sm := KeyManagementService{Client: kmstc.mockClient}
+		if _, err := sm.GetSecret(context.Background(), *kmstc.ref); !errors.As(err, row.want) {
+			t.Errorf("unexpected error type %T: %v", err, err)
+		}
+	}
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(kmstc *keyManagementServiceTestCase) {
@@ -187,6 +209,40 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestGetSecretMetadata(t *testing.T) {
+	mc := &fakesm.AlibabaMockClient{}
+	mc.WithTags(map[string]string{"env": "dev"}, nil)
+	sm := KeyManagementService{Client: mc}
+
+	ref := esv1beta1.ExternalSecretDataRemoteRef{
+		Key:            secretName,
+		MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+	}
+	out, err := sm.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != `{"env":"dev"}` {
+		t.Errorf("unexpected tags: %s, %v", out, err)
+	}
+
+	ref.Property = "env"
+	out, err = sm.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != "dev" {
+		t.Errorf("unexpected tag value: %s, %v", out, err)
+	}
+
+	ref.Property = "missing"
+	_, err = sm.GetSecret(context.Background(), ref)
+	if !ErrorContains(err, "does not exist") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	mc.WithTags(nil, fmt.Errorf("oh no"))
+	ref.Property = ""
+	_, err = sm.GetSecret(context.Background(), ref)
+	if !ErrorContains(err, "oh no") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestValidateStore(t *testing.T) {
 	kms := KeyManagementService{}
 
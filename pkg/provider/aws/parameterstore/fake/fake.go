@@ -23,6 +23,10 @@ import (
 // Client implements the aws parameterstore interface.
 type Client struct {
 	valFn func(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+
+	PutParameterFn        func(*ssm.PutParameterInput) (*ssm.PutParameterOutput, error)
+	DeleteParameterFn     func(*ssm.DeleteParameterInput) (*ssm.DeleteParameterOutput, error)
+	ListTagsForResourceFn func(*ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error)
 }
 
 func (sm *Client) GetParameter(in *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
@@ -33,6 +37,27 @@ func (sm *Client) DescribeParameters(*ssm.DescribeParametersInput) (*ssm.Describ
 	return nil, nil
 }
 
+func (sm *Client) PutParameter(in *ssm.PutParameterInput) (*ssm.PutParameterOutput, error) {
+	if sm.PutParameterFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.PutParameterFn(in)
+}
+
+func (sm *Client) DeleteParameter(in *ssm.DeleteParameterInput) (*ssm.DeleteParameterOutput, error) {
+	if sm.DeleteParameterFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.DeleteParameterFn(in)
+}
+
+func (sm *Client) ListTagsForResource(in *ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+	if sm.ListTagsForResourceFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.ListTagsForResourceFn(in)
+}
+
 func (sm *Client) WithValue(in *ssm.GetParameterInput, val *ssm.GetParameterOutput, err error) {
 	sm.valFn = func(paramIn *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
 		if !cmp.Equal(paramIn, in) {
@@ -46,10 +46,14 @@ type ParameterStore struct {
 type PMInterface interface {
 	GetParameter(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
 	DescribeParameters(*ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error)
+	PutParameter(*ssm.PutParameterInput) (*ssm.PutParameterOutput, error)
+	DeleteParameter(*ssm.DeleteParameterInput) (*ssm.DeleteParameterOutput, error)
+	ListTagsForResource(*ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error)
 }
 
 const (
 	errUnexpectedFindOperator = "unexpected find operator"
+	errNotManaged             = "parameter %q is not managed by external-secrets"
 )
 
 // New constructs a ParameterStore Provider that is specific to a store.
@@ -170,6 +174,9 @@ func (pm *ParameterStore) fetchAndSet(data map[string][]byte, name string) error
 
 // GetSecret returns a single secret from the provider.
 func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return pm.fetchTags(ref)
+	}
 	out, err := pm.client.GetParameter(&ssm.GetParameterInput{
 		Name:           &ref.Key,
 		WithDecryption: aws.Bool(true),
@@ -203,6 +210,26 @@ func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1beta1.ExternalS
 	return []byte(val.String()), nil
 }
 
+// fetchTags returns the tags of the parameter instead of its value.
+func (pm *ParameterStore) fetchTags(ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	out, err := pm.client.ListTagsForResource(&ssm.ListTagsForResourceInput{
+		ResourceId:   &ref.Key,
+		ResourceType: utilpointer.StringPtr(ssm.ResourceTypeForTaggingParameter),
+	})
+	var nf *ssm.InvalidResourceId
+	if errors.As(err, &nf) {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	tags := make(map[string]string, len(out.TagList))
+	for _, tag := range out.TagList {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return utils.GetMetadataValue(tags, ref.Property)
+}
+
 // GetSecretMap returns multiple k/v pairs from the provider.
 func (pm *ParameterStore) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	data, err := pm.GetSecret(ctx, ref)
@@ -221,6 +248,117 @@ func (pm *ParameterStore) GetSecretMap(ctx context.Context, ref esv1beta1.Extern
 	return secretData, nil
 }
 
+// SetSecret creates or updates a SecureString parameter. If a property is given,
+// the value is merged into the JSON document of the parameter. Parameters are
+// tagged on creation and existing parameters without that tag are never overwritten.
+func (pm *ParameterStore) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	key := remoteRef.GetRemoteKey()
+	current, err := pm.currentManagedValue(key)
+	if err != nil {
+		return err
+	}
+	payload := value
+	if remoteRef.GetProperty() != "" {
+		var doc []byte
+		if current != nil {
+			doc = []byte(*current)
+		}
+		payload, err = utils.SetJSONProperty(doc, remoteRef.GetProperty(), value)
+		if err != nil {
+			return err
+		}
+	}
+	if current == nil {
+		_, err = pm.client.PutParameter(&ssm.PutParameterInput{
+			Name:  &key,
+			Value: utilpointer.StringPtr(string(payload)),
+			Type:  utilpointer.StringPtr(ssm.ParameterTypeSecureString),
+			Tags: []*ssm.Tag{
+				{
+					Key:   utilpointer.StringPtr(utils.ManagedByKey),
+					Value: utilpointer.StringPtr(utils.ManagedByValue),
+				},
+			},
+		})
+		return util.SanitizeErr(err)
+	}
+	if *current == string(payload) {
+		return nil
+	}
+	_, err = pm.client.PutParameter(&ssm.PutParameterInput{
+		Name:      &key,
+		Value:     utilpointer.StringPtr(string(payload)),
+		Overwrite: aws.Bool(true),
+	})
+	return util.SanitizeErr(err)
+}
+
+// DeleteSecret removes a property from a parameter created by SetSecret.
+// The parameter is deleted once no property is left or if no property is given.
+func (pm *ParameterStore) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	key := remoteRef.GetRemoteKey()
+	current, err := pm.currentManagedValue(key)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	if remoteRef.GetProperty() != "" {
+		payload, empty, err := utils.DeleteJSONProperty([]byte(*current), remoteRef.GetProperty())
+		if err != nil {
+			return err
+		}
+		if !empty {
+			if *current == string(payload) {
+				return nil
+			}
+			_, err = pm.client.PutParameter(&ssm.PutParameterInput{
+				Name:      &key,
+				Value:     utilpointer.StringPtr(string(payload)),
+				Overwrite: aws.Bool(true),
+			})
+			return util.SanitizeErr(err)
+		}
+	}
+	_, err = pm.client.DeleteParameter(&ssm.DeleteParameterInput{Name: &key})
+	var nf *ssm.ParameterNotFound
+	if errors.As(err, &nf) {
+		return nil
+	}
+	return util.SanitizeErr(err)
+}
+
+// currentManagedValue returns the value of a parameter or nil if it does not exist.
+// It fails if the parameter exists but has not been created by external-secrets.
+func (pm *ParameterStore) currentManagedValue(key string) (*string, error) {
+	out, err := pm.client.GetParameter(&ssm.GetParameterInput{
+		Name:           &key,
+		WithDecryption: aws.Bool(true),
+	})
+	var nf *ssm.ParameterNotFound
+	if errors.As(err, &nf) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	tags, err := pm.client.ListTagsForResource(&ssm.ListTagsForResourceInput{
+		ResourceId:   &key,
+		ResourceType: utilpointer.StringPtr(ssm.ResourceTypeForTaggingParameter),
+	})
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	for _, tag := range tags.TagList {
+		if aws.StringValue(tag.Key) == utils.ManagedByKey && aws.StringValue(tag.Value) == utils.ManagedByValue {
+			value := aws.StringValue(out.Parameter.Value)
+			return &value, nil
+		}
+	}
+	return nil, fmt.Errorf(errNotManaged, key)
+}
+
 func (pm *ParameterStore) Close(ctx context.Context) error {
 	return nil
 }
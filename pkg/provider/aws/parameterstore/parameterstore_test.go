@@ -15,6 +15,7 @@ package parameterstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -23,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/google/go-cmp/cmp"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	fake "github.com/external-secrets/external-secrets/pkg/provider/aws/parameterstore/fake"
 )
@@ -200,3 +202,122 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func TestGetSecretMetadata(t *testing.T) {
+	fakeClient := &fake.Client{}
+	fakeClient.ListTagsForResourceFn = func(in *ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+		if aws.StringValue(in.ResourceId) != "/baz" {
+			return nil, &ssm.InvalidResourceId{}
+		}
+		return &ssm.ListTagsForResourceOutput{
+			TagList: []*ssm.Tag{
+				{Key: aws.String("env"), Value: aws.String("dev")},
+			},
+		}, nil
+	}
+	ps := ParameterStore{client: fakeClient}
+
+	ref := esv1beta1.ExternalSecretDataRemoteRef{
+		Key:            "/baz",
+		MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+	}
+	out, err := ps.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != `{"env":"dev"}` {
+		t.Errorf("unexpected tags: %s, %v", out, err)
+	}
+
+	ref.Property = "env"
+	out, err = ps.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != "dev" {
+		t.Errorf("unexpected tag value: %s, %v", out, err)
+	}
+
+	ref.Property = "missing"
+	_, err = ps.GetSecret(context.Background(), ref)
+	if !ErrorContains(err, `metadata key "missing" does not exist`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ref.Key = "/missing"
+	ref.Property = ""
+	_, err = ps.GetSecret(context.Background(), ref)
+	if !errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSetSecret(t *testing.T) {
+	managedTags := func(*ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+		return &ssm.ListTagsForResourceOutput{
+			TagList: []*ssm.Tag{{Key: aws.String("managed-by"), Value: aws.String("external-secrets")}},
+		}, nil
+	}
+	var put *ssm.PutParameterInput
+	putFn := func(in *ssm.PutParameterInput) (*ssm.PutParameterOutput, error) {
+		put = in
+		return &ssm.PutParameterOutput{}, nil
+	}
+
+	// create a new tagged parameter
+	fakeClient := &fake.Client{PutParameterFn: putFn, ListTagsForResourceFn: managedTags}
+	fakeClient.WithValue(makeValidAPIInput(), nil, &ssm.ParameterNotFound{})
+	ps := ParameterStore{client: fakeClient}
+	err := ps.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if put == nil || aws.StringValue(put.Value) != "bar" || len(put.Tags) != 1 || aws.BoolValue(put.Overwrite) {
+		t.Errorf("unexpected put input: %v", put)
+	}
+
+	// merge a property into a managed parameter
+	put = nil
+	fakeClient.WithValue(makeValidAPIInput(), &ssm.GetParameterOutput{
+		Parameter: &ssm.Parameter{Value: aws.String(`{"other":"x"}`)},
+	}, nil)
+	err = ps.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz", Property: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if put == nil || aws.StringValue(put.Value) != `{"foo":"bar","other":"x"}` || !aws.BoolValue(put.Overwrite) {
+		t.Errorf("unexpected put input: %v", put)
+	}
+
+	// refuse to overwrite unmanaged parameters
+	fakeClient.ListTagsForResourceFn = func(*ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+		return &ssm.ListTagsForResourceOutput{}, nil
+	}
+	err = ps.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err == nil || !strings.Contains(err.Error(), "not managed by external-secrets") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	deleted := false
+	fakeClient := &fake.Client{
+		ListTagsForResourceFn: func(*ssm.ListTagsForResourceInput) (*ssm.ListTagsForResourceOutput, error) {
+			return &ssm.ListTagsForResourceOutput{
+				TagList: []*ssm.Tag{{Key: aws.String("managed-by"), Value: aws.String("external-secrets")}},
+			}, nil
+		},
+		DeleteParameterFn: func(*ssm.DeleteParameterInput) (*ssm.DeleteParameterOutput, error) {
+			deleted = true
+			return &ssm.DeleteParameterOutput{}, nil
+		},
+	}
+	fakeClient.WithValue(makeValidAPIInput(), makeValidAPIOutput(), nil)
+	ps := ParameterStore{client: fakeClient}
+	err := ps.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err != nil || !deleted {
+		t.Errorf("parameter was not deleted: %v", err)
+	}
+
+	// parameters that do not exist are ignored
+	deleted = false
+	fakeClient.WithValue(makeValidAPIInput(), nil, &ssm.ParameterNotFound{})
+	err = ps.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err != nil || deleted {
+		t.Errorf("unexpected delete: %v", err)
+	}
+}
@@ -35,6 +35,7 @@ import (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.Provider = &Provider{}
+var _ esv1beta1.MetadataFetcher = &Provider{}
 
 // Provider satisfies the provider interface.
 type Provider struct{}
@@ -51,6 +52,12 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	return newClient(ctx, store, kube, namespace, awsauth.DefaultSTSProvider)
 }
 
+// SupportsMetadataFetch returns true as both SecretsManager
+// and ParameterStore are able to fetch the tags of a secret.
+func (p *Provider) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	return true
+}
+
 func (p *Provider) ValidateStore(store esv1beta1.GenericStore) error {
 	prov, err := util.GetAWSProvider(store)
 	if err != nil {
@@ -25,6 +25,12 @@ import (
 type Client struct {
 	ExecutionCounter int
 	valFn            map[string]func(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
+
+	DescribeSecretFn func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error)
+	CreateSecretFn   func(*awssm.CreateSecretInput) (*awssm.CreateSecretOutput, error)
+	PutSecretValueFn func(*awssm.PutSecretValueInput) (*awssm.PutSecretValueOutput, error)
+	RestoreSecretFn  func(*awssm.RestoreSecretInput) (*awssm.RestoreSecretOutput, error)
+	DeleteSecretFn   func(*awssm.DeleteSecretInput) (*awssm.DeleteSecretOutput, error)
 }
 
 // NewClient init a new fake client.
@@ -46,6 +52,41 @@ func (sm *Client) ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput
 	return nil, nil
 }
 
+func (sm *Client) DescribeSecret(in *awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+	if sm.DescribeSecretFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.DescribeSecretFn(in)
+}
+
+func (sm *Client) CreateSecret(in *awssm.CreateSecretInput) (*awssm.CreateSecretOutput, error) {
+	if sm.CreateSecretFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.CreateSecretFn(in)
+}
+
+func (sm *Client) PutSecretValue(in *awssm.PutSecretValueInput) (*awssm.PutSecretValueOutput, error) {
+	if sm.PutSecretValueFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.PutSecretValueFn(in)
+}
+
+func (sm *Client) RestoreSecret(in *awssm.RestoreSecretInput) (*awssm.RestoreSecretOutput, error) {
+	if sm.RestoreSecretFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.RestoreSecretFn(in)
+}
+
+func (sm *Client) DeleteSecret(in *awssm.DeleteSecretInput) (*awssm.DeleteSecretOutput, error) {
+	if sm.DeleteSecretFn == nil {
+		return nil, fmt.Errorf("test case not found")
+	}
+	return sm.DeleteSecretFn(in)
+}
+
 func (sm *Client) cacheKeyForInput(in *awssm.GetSecretValueInput) string {
 	var secretID, versionID string
 	if in.SecretId != nil {
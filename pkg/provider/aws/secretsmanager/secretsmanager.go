@@ -15,11 +15,14 @@ limitations under the License.
 package secretsmanager
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -36,12 +39,15 @@ import (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &SecretsManager{}
+var _ esv1beta1.SecretVersionGetter = &SecretsManager{}
 
 // SecretsManager is a provider for AWS SecretsManager.
 type SecretsManager struct {
 	sess   *session.Session
 	client SMInterface
-	cache  map[string]*awssm.GetSecretValueOutput
+	// cacheMu guards cache, a client may be used by concurrent fetches.
+	cacheMu sync.Mutex
+	cache   map[string]*awssm.GetSecretValueOutput
 }
 
 // SMInterface is a subset of the smiface api.
@@ -49,10 +55,16 @@ type SecretsManager struct {
 type SMInterface interface {
 	GetSecretValue(*awssm.GetSecretValueInput) (*awssm.GetSecretValueOutput, error)
 	ListSecrets(*awssm.ListSecretsInput) (*awssm.ListSecretsOutput, error)
+	DescribeSecret(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error)
+	CreateSecret(*awssm.CreateSecretInput) (*awssm.CreateSecretOutput, error)
+	PutSecretValue(*awssm.PutSecretValueInput) (*awssm.PutSecretValueOutput, error)
+	RestoreSecret(*awssm.RestoreSecretInput) (*awssm.RestoreSecretOutput, error)
+	DeleteSecret(*awssm.DeleteSecretInput) (*awssm.DeleteSecretOutput, error)
 }
 
 const (
 	errUnexpectedFindOperator = "unexpected find operator"
+	errNotManaged             = "secret %q is not managed by external-secrets"
 )
 
 var log = ctrl.Log.WithName("provider").WithName("aws").WithName("secretsmanager")
@@ -66,15 +78,23 @@ func New(sess *session.Session, cfg *aws.Config) (*SecretsManager, error) {
 	}, nil
 }
 
-func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (*awssm.GetSecretValueOutput, error) {
+// valueCacheKey returns the key of the cached value of a secret and its requested version.
+func valueCacheKey(ref esv1beta1.ExternalSecretDataRemoteRef) (string, string) {
 	ver := "AWSCURRENT"
 	if ref.Version != "" {
 		ver = ref.Version
 	}
+	return fmt.Sprintf("%s#%s", ref.Key, ver), ver
+}
+
+func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (*awssm.GetSecretValueOutput, error) {
+	cacheKey, ver := valueCacheKey(ref)
 	log.Info("fetching secret value", "key", ref.Key, "version", ver)
 
-	cacheKey := fmt.Sprintf("%s#%s", ref.Key, ver)
-	if secretOut, found := sm.cache[cacheKey]; found {
+	sm.cacheMu.Lock()
+	secretOut, found := sm.cache[cacheKey]
+	sm.cacheMu.Unlock()
+	if found {
 		log.Info("found secret in cache", "key", ref.Key, "version", ver)
 		return secretOut, nil
 	}
@@ -100,7 +120,9 @@ func (sm *SecretsManager) fetch(_ context.Context, ref esv1beta1.ExternalSecretD
 	if err != nil {
 		return nil, err
 	}
+	sm.cacheMu.Lock()
 	sm.cache[cacheKey] = secretOut
+	sm.cacheMu.Unlock()
 
 	return secretOut, nil
 }
@@ -231,6 +253,9 @@ func (sm *SecretsManager) fetchAndSet(ctx context.Context, data map[string][]byt
 
 // GetSecret returns a single secret from the provider.
 func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return sm.fetchTags(ref)
+	}
 	secretOut, err := sm.fetch(ctx, ref)
 	if errors.Is(err, esv1beta1.NoSecretErr) {
 		return nil, err
@@ -270,6 +295,38 @@ func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1beta1.ExternalS
 	return []byte(val.String()), nil
 }
 
+// fetchTags returns the tags of the secret instead of its value.
+func (sm *SecretsManager) fetchTags(ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	out, err := sm.client.DescribeSecret(&awssm.DescribeSecretInput{SecretId: &ref.Key})
+	var nf *awssm.ResourceNotFoundException
+	if errors.As(err, &nf) {
+		return nil, esv1beta1.NoSecretErr
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	tags := make(map[string]string, len(out.Tags))
+	for _, tag := range out.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return utils.GetMetadataValue(tags, ref.Property)
+}
+
+// GetSecretVersion returns the VersionId of the cached value of the secret.
+func (sm *SecretsManager) GetSecretVersion(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return "", nil
+	}
+	key, _ := valueCacheKey(ref)
+	sm.cacheMu.Lock()
+	defer sm.cacheMu.Unlock()
+	secretOut, ok := sm.cache[key]
+	if !ok {
+		return "", nil
+	}
+	return aws.StringValue(secretOut.VersionId), nil
+}
+
 // GetSecretMap returns multiple k/v pairs from the provider.
 func (sm *SecretsManager) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	log.Info("fetching secret map", "key", ref.Key)
@@ -295,6 +352,163 @@ func (sm *SecretsManager) GetSecretMap(ctx context.Context, ref esv1beta1.Extern
 	return secretData, nil
 }
 
+// SetSecret creates or updates a secret. If a property is given, the value
+// is merged into the JSON document of the secret. Secrets are tagged on creation
+// and existing secrets without that tag are never overwritten.
+func (sm *SecretsManager) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	key := remoteRef.GetRemoteKey()
+	describe, err := sm.describeManagedSecret(key)
+	if err != nil {
+		return err
+	}
+	payload := value
+	if describe == nil {
+		if remoteRef.GetProperty() != "" {
+			payload, err = utils.SetJSONProperty(nil, remoteRef.GetProperty(), value)
+			if err != nil {
+				return err
+			}
+		}
+		input := &awssm.CreateSecretInput{
+			Name: &key,
+			Tags: []*awssm.Tag{
+				{
+					Key:   utilpointer.StringPtr(utils.ManagedByKey),
+					Value: utilpointer.StringPtr(utils.ManagedByValue),
+				},
+			},
+		}
+		setPayload(payload, &input.SecretString, &input.SecretBinary)
+		_, err = sm.client.CreateSecret(input)
+		return util.SanitizeErr(err)
+	}
+	if describe.DeletedDate != nil {
+		_, err = sm.client.RestoreSecret(&awssm.RestoreSecretInput{SecretId: &key})
+		if err != nil {
+			return util.SanitizeErr(err)
+		}
+	}
+	current, err := sm.currentValue(key)
+	if err != nil {
+		return err
+	}
+	if remoteRef.GetProperty() != "" {
+		payload, err = utils.SetJSONProperty(current, remoteRef.GetProperty(), value)
+		if err != nil {
+			return err
+		}
+	}
+	if bytes.Equal(current, payload) {
+		return nil
+	}
+	return sm.putValue(key, payload)
+}
+
+// DeleteSecret removes a property from a secret created by SetSecret.
+// The secret is deleted once no property is left or if no property is given.
+func (sm *SecretsManager) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	key := remoteRef.GetRemoteKey()
+	describe, err := sm.describeManagedSecret(key)
+	if err != nil {
+		return err
+	}
+	if describe == nil || describe.DeletedDate != nil {
+		return nil
+	}
+	if remoteRef.GetProperty() != "" {
+		current, err := sm.currentValue(key)
+		if err != nil {
+			return err
+		}
+		payload, empty, err := utils.DeleteJSONProperty(current, remoteRef.GetProperty())
+		if err != nil {
+			return err
+		}
+		if !empty {
+			if bytes.Equal(current, payload) {
+				return nil
+			}
+			return sm.putValue(key, payload)
+		}
+	}
+	sm.invalidate(key)
+	_, err = sm.client.DeleteSecret(&awssm.DeleteSecretInput{SecretId: &key})
+	return util.SanitizeErr(err)
+}
+
+// describeManagedSecret returns nil if the secret does not exist and fails
+// if it exists but has not been created by external-secrets.
+func (sm *SecretsManager) describeManagedSecret(key string) (*awssm.DescribeSecretOutput, error) {
+	out, err := sm.client.DescribeSecret(&awssm.DescribeSecretInput{SecretId: &key})
+	var nf *awssm.ResourceNotFoundException
+	if errors.As(err, &nf) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	for _, tag := range out.Tags {
+		if aws.StringValue(tag.Key) == utils.ManagedByKey && aws.StringValue(tag.Value) == utils.ManagedByValue {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf(errNotManaged, key)
+}
+
+func (sm *SecretsManager) currentValue(key string) ([]byte, error) {
+	out, err := sm.client.GetSecretValue(&awssm.GetSecretValueInput{
+		SecretId:     &key,
+		VersionStage: utilpointer.StringPtr("AWSCURRENT"),
+	})
+	var nf *awssm.ResourceNotFoundException
+	if errors.As(err, &nf) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, util.SanitizeErr(err)
+	}
+	if out.SecretString != nil {
+		return []byte(*out.SecretString), nil
+	}
+	return out.SecretBinary, nil
+}
+
+func (sm *SecretsManager) putValue(key string, payload []byte) error {
+	sm.invalidate(key)
+	input := &awssm.PutSecretValueInput{SecretId: &key}
+	setPayload(payload, &input.SecretString, &input.SecretBinary)
+	_, err := sm.client.PutSecretValue(input)
+	return util.SanitizeErr(err)
+}
+
+// Reset drops all cached secret values. The client pool calls it before
+// a pooled client is used again, so that every refresh reads current values.
+func (sm *SecretsManager) Reset() {
+	sm.cacheMu.Lock()
+	defer sm.cacheMu.Unlock()
+	sm.cache = make(map[string]*awssm.GetSecretValueOutput)
+}
+
+// invalidate drops all cached versions of a secret.
+func (sm *SecretsManager) invalidate(key string) {
+	sm.cacheMu.Lock()
+	defer sm.cacheMu.Unlock()
+	for cacheKey := range sm.cache {
+		if strings.HasPrefix(cacheKey, key+"#") {
+			delete(sm.cache, cacheKey)
+		}
+	}
+}
+
+// setPayload stores text as SecretString and everything else as SecretBinary.
+func setPayload(payload []byte, secretString **string, secretBinary *[]byte) {
+	if utf8.Valid(payload) {
+		*secretString = utilpointer.StringPtr(string(payload))
+		return
+	}
+	*secretBinary = payload
+}
+
 func (sm *SecretsManager) Close(ctx context.Context) error {
 	return nil
 }
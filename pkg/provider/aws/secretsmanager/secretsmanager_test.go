@@ -16,7 +16,9 @@ package secretsmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -24,6 +26,7 @@ import (
 	awssm "github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/google/go-cmp/cmp"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	fakesm "github.com/external-secrets/external-secrets/pkg/provider/aws/secretsmanager/fake"
 )
@@ -254,6 +257,32 @@ func TestCaching(t *testing.T) {
 	}
 }
 
+func TestGetSecretVersion(t *testing.T) {
+	sm := SecretsManager{
+		cache: map[string]*awssm.GetSecretValueOutput{
+			"foo#AWSCURRENT": {VersionId: aws.String("1234-5678")},
+		},
+	}
+	tbl := []struct {
+		ref  esv1beta1.ExternalSecretDataRemoteRef
+		want string
+	}{
+		{ref: esv1beta1.ExternalSecretDataRemoteRef{Key: "foo"}, want: "1234-5678"},
+		{ref: esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", Version: "AWSPREVIOUS"}},
+		{ref: esv1beta1.ExternalSecretDataRemoteRef{Key: "bar"}},
+		{ref: esv1beta1.ExternalSecretDataRemoteRef{Key: "foo", MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch}},
+	}
+	for i, row := range tbl {
+		got, err := sm.GetSecretVersion(context.Background(), row.ref)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %v", i, err)
+		}
+		if got != row.want {
+			t.Errorf("[%d] unexpected version: expected %q, got %q", i, row.want, got)
+		}
+	}
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(smtc *secretsManagerTestCase) {
@@ -316,3 +345,171 @@ func ErrorContains(out error, want string) bool {
 	}
 	return strings.Contains(out.Error(), want)
 }
+
+func makeManagedDescribe() func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+	return func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		return &awssm.DescribeSecretOutput{
+			Tags: []*awssm.Tag{{Key: aws.String("managed-by"), Value: aws.String("external-secrets")}},
+		}, nil
+	}
+}
+
+func TestGetSecretMetadata(t *testing.T) {
+	fakeClient := fakesm.NewClient()
+	fakeClient.DescribeSecretFn = func(in *awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		if aws.StringValue(in.SecretId) != "/baz" {
+			return nil, &awssm.ResourceNotFoundException{}
+		}
+		return &awssm.DescribeSecretOutput{
+			Tags: []*awssm.Tag{
+				{Key: aws.String("env"), Value: aws.String("dev")},
+				{Key: aws.String("team"), Value: aws.String("platform")},
+			},
+		}, nil
+	}
+	sm := SecretsManager{client: fakeClient, cache: make(map[string]*awssm.GetSecretValueOutput)}
+
+	ref := esv1beta1.ExternalSecretDataRemoteRef{
+		Key:            "/baz",
+		MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+	}
+	out, err := sm.GetSecret(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(out) != `{"env":"dev","team":"platform"}` {
+		t.Errorf("unexpected tags: %s", out)
+	}
+
+	ref.Property = "team"
+	out, err = sm.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != "platform" {
+		t.Errorf("unexpected tag value: %s, %v", out, err)
+	}
+
+	ref.Property = ""
+	data, err := sm.GetSecretMap(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(data, map[string][]byte{"env": []byte("dev"), "team": []byte("platform")}) {
+		t.Errorf("unexpected tag map: %v", data)
+	}
+
+	ref.Key = "/missing"
+	_, err = sm.GetSecret(context.Background(), ref)
+	if !errors.Is(err, esv1beta1.NoSecretErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSetSecret(t *testing.T) {
+	notFound := func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		return nil, &awssm.ResourceNotFoundException{}
+	}
+	currentValue := &awssm.GetSecretValueInput{
+		SecretId:     aws.String("/baz"),
+		VersionStage: aws.String("AWSCURRENT"),
+	}
+
+	// create a new tagged secret
+	fakeClient := fakesm.NewClient()
+	fakeClient.DescribeSecretFn = notFound
+	var created *awssm.CreateSecretInput
+	fakeClient.CreateSecretFn = func(in *awssm.CreateSecretInput) (*awssm.CreateSecretOutput, error) {
+		created = in
+		return &awssm.CreateSecretOutput{}, nil
+	}
+	sm := SecretsManager{client: fakeClient, cache: make(map[string]*awssm.GetSecretValueOutput)}
+	err := sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz", Property: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created == nil || aws.StringValue(created.SecretString) != `{"foo":"bar"}` {
+		t.Errorf("unexpected create input: %v", created)
+	}
+	if len(created.Tags) != 1 || aws.StringValue(created.Tags[0].Key) != "managed-by" {
+		t.Errorf("secret is not tagged: %v", created.Tags)
+	}
+
+	// merge a property into a managed secret
+	fakeClient = fakesm.NewClient()
+	fakeClient.DescribeSecretFn = makeManagedDescribe()
+	fakeClient.WithValue(currentValue, &awssm.GetSecretValueOutput{SecretString: aws.String(`{"other":"x"}`)}, nil)
+	var put *awssm.PutSecretValueInput
+	fakeClient.PutSecretValueFn = func(in *awssm.PutSecretValueInput) (*awssm.PutSecretValueOutput, error) {
+		put = in
+		return &awssm.PutSecretValueOutput{}, nil
+	}
+	sm.client = fakeClient
+	err = sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz", Property: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if put == nil || aws.StringValue(put.SecretString) != `{"foo":"bar","other":"x"}` {
+		t.Errorf("unexpected put input: %v", put)
+	}
+
+	// do not write unchanged values
+	put = nil
+	fakeClient.WithValue(currentValue, &awssm.GetSecretValueOutput{SecretString: aws.String("bar")}, nil)
+	err = sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err != nil || put != nil {
+		t.Errorf("unexpected write of unchanged value: %v, %v", err, put)
+	}
+
+	// refuse to overwrite unmanaged secrets
+	fakeClient.DescribeSecretFn = func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		return &awssm.DescribeSecretOutput{}, nil
+	}
+	err = sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if !ErrorContains(err, "not managed by external-secrets") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	currentValue := &awssm.GetSecretValueInput{
+		SecretId:     aws.String("/baz"),
+		VersionStage: aws.String("AWSCURRENT"),
+	}
+	fakeClient := fakesm.NewClient()
+	fakeClient.DescribeSecretFn = makeManagedDescribe()
+	fakeClient.WithValue(currentValue, &awssm.GetSecretValueOutput{SecretString: aws.String(`{"foo":"bar","other":"x"}`)}, nil)
+	var put *awssm.PutSecretValueInput
+	fakeClient.PutSecretValueFn = func(in *awssm.PutSecretValueInput) (*awssm.PutSecretValueOutput, error) {
+		put = in
+		return &awssm.PutSecretValueOutput{}, nil
+	}
+	deleted := false
+	fakeClient.DeleteSecretFn = func(in *awssm.DeleteSecretInput) (*awssm.DeleteSecretOutput, error) {
+		deleted = true
+		return &awssm.DeleteSecretOutput{}, nil
+	}
+	sm := SecretsManager{client: fakeClient, cache: make(map[string]*awssm.GetSecretValueOutput)}
+
+	// remove a single property
+	err := sm.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz", Property: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deleted || put == nil || aws.StringValue(put.SecretString) != `{"other":"x"}` {
+		t.Errorf("unexpected put input: %v", put)
+	}
+
+	// remove the last property
+	fakeClient.WithValue(currentValue, &awssm.GetSecretValueOutput{SecretString: aws.String(`{"other":"x"}`)}, nil)
+	err = sm.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz", Property: "other"})
+	if err != nil || !deleted {
+		t.Errorf("secret was not deleted: %v", err)
+	}
+
+	// secrets that do not exist are ignored
+	fakeClient.DescribeSecretFn = func(*awssm.DescribeSecretInput) (*awssm.DescribeSecretOutput, error) {
+		return nil, &awssm.ResourceNotFoundException{}
+	}
+	err = sm.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "/baz"})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -16,14 +16,72 @@ package util
 
 import (
 	"errors"
+	"net/http"
 	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 var regexReqID = regexp.MustCompile(`request id: (\S+)`)
 
+// RetryAfterHandler keeps the Retry-After header of a throttled request in its error,
+// so that the controller knows when to try again. It runs once all retries of the
+// SDK have failed.
+var RetryAfterHandler = request.NamedHandler{
+	Name: "externalsecrets.RetryAfterHandler",
+	Fn: func(r *request.Request) {
+		if r.Error == nil || r.HTTPResponse == nil {
+			return
+		}
+		if r.HTTPResponse.StatusCode != http.StatusTooManyRequests && !request.IsErrorThrottle(r.Error) {
+			return
+		}
+		if retryAfter, ok := utils.ParseRetryAfter(r.HTTPResponse.Header.Get("Retry-After")); ok {
+			r.Error = esv1beta1.ThrottledError{Err: r.Error, RetryAfter: retryAfter}
+		}
+	},
+}
+
 // SanitizeErr sanitizes the error string
 // because the requestID must not be included in the error.
 // otherwise the secrets keeps syncing.
+// The sanitized error is classified by the code of the AWS error.
 func SanitizeErr(err error) error {
-	return errors.New(string(regexReqID.ReplaceAll([]byte(err.Error()), nil)))
+	if err == nil {
+		return nil
+	}
+	return classify(err, errors.New(string(regexReqID.ReplaceAll([]byte(err.Error()), nil))))
+}
+
+// classify wraps the sanitized error in the type matching the code of the AWS error.
+func classify(err, sanitized error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return sanitized
+	}
+	switch aerr.Code() {
+	case "UnrecognizedClientException", "InvalidClientTokenId", "ExpiredToken", "ExpiredTokenException",
+		"InvalidSignatureException", "SignatureDoesNotMatch", "IncompleteSignature", "MissingAuthenticationToken":
+		return esv1beta1.AuthError{Err: sanitized}
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation":
+		return esv1beta1.PermissionDeniedError{Err: sanitized}
+	case "Throttling", "ThrottlingException", "TooManyRequestsException", "RequestLimitExceeded":
+		var throttled esv1beta1.ThrottledError
+		errors.As(err, &throttled)
+		return esv1beta1.ThrottledError{Err: sanitized, RetryAfter: throttled.RetryAfter}
+	case "InternalFailure", "InternalServiceError", "InternalServerError", "ServiceUnavailable",
+		"RequestTimeout", "RequestTimeoutException", request.ErrCodeRequestError:
+		return esv1beta1.TransientError{Err: sanitized}
+	case "InvalidParameterException", "InvalidRequestException", "ValidationException", "ParameterVersionNotFound":
+		return esv1beta1.InvalidRefError{Err: sanitized}
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return esv1beta1.FromHTTPStatus(reqErr.StatusCode(), sanitized)
+	}
+	return sanitized
 }
@@ -15,9 +15,15 @@ package util
 
 import (
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/stretchr/testify/assert"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 func TestSanitize(t *testing.T) {
@@ -40,3 +46,73 @@ func TestSanitize(t *testing.T) {
 		assert.Equal(t, c.expected, out.Error())
 	}
 }
+
+func TestSanitizeNil(t *testing.T) {
+	assert.Nil(t, SanitizeErr(nil))
+}
+
+func TestSanitizeClassifies(t *testing.T) {
+	tbl := []struct {
+		err  error
+		want interface{}
+	}{
+		{
+			err:  awserr.New("ExpiredTokenException", "token expired", nil),
+			want: &esv1beta1.AuthError{},
+		},
+		{
+			err:  awserr.New("AccessDeniedException", "not authorized", nil),
+			want: &esv1beta1.PermissionDeniedError{},
+		},
+		{
+			err:  awserr.New("ThrottlingException", "rate exceeded", nil),
+			want: &esv1beta1.ThrottledError{},
+		},
+		{
+			err:  awserr.New("InternalServiceError", "try again", nil),
+			want: &esv1beta1.TransientError{},
+		},
+		{
+			err:  awserr.New("ValidationException", "invalid name", nil),
+			want: &esv1beta1.InvalidRefError{},
+		},
+		{
+			err:  awserr.NewRequestFailure(awserr.New("Unknown", "unavailable", nil), 503, "df34"),
+			want: &esv1beta1.TransientError{},
+		},
+	}
+	for _, c := range tbl {
+		out := SanitizeErr(c.err)
+		assert.True(t, errors.As(out, c.want), "%s: unexpected error type %T", c.err, out)
+		assert.NotContains(t, out.Error(), "df34")
+	}
+}
+
+func TestRetryAfterHandler(t *testing.T) {
+	makeReq := func(err error, retryAfter string) *request.Request {
+		return &request.Request{
+			Error: err,
+			HTTPResponse: &http.Response{
+				StatusCode: 400,
+				Header:     http.Header{"Retry-After": []string{retryAfter}},
+			},
+		}
+	}
+	throttled := awserr.New("ThrottlingException", "rate exceeded", nil)
+
+	r := makeReq(throttled, "5")
+	RetryAfterHandler.Fn(r)
+	var out esv1beta1.ThrottledError
+	assert.ErrorAs(t, SanitizeErr(r.Error), &out)
+	assert.Equal(t, 5*time.Second, out.RetryAfter)
+
+	// other errors are left as is
+	denied := awserr.New("AccessDeniedException", "not authorized", nil)
+	r = makeReq(denied, "5")
+	RetryAfterHandler.Fn(r)
+	assert.Equal(t, denied, r.Error)
+
+	r = makeReq(throttled, "")
+	RetryAfterHandler.Fn(r)
+	assert.Equal(t, throttled, r.Error)
+}
@@ -24,6 +24,8 @@ type AzureMockClient struct {
 	getSecret          func(ctx context.Context, vaultBaseURL string, secretName string, secretVersion string) (result keyvault.SecretBundle, err error)
 	getSecretsComplete func(ctx context.Context, vaultBaseURL string, maxresults *int32) (result keyvault.SecretListResultIterator, err error)
 	getCertificate     func(ctx context.Context, vaultBaseURL string, certificateName string, certificateVersion string) (result keyvault.CertificateBundle, err error)
+	setSecret          func(ctx context.Context, vaultBaseURL string, secretName string, parameters keyvault.SecretSetParameters) (result keyvault.SecretBundle, err error)
+	deleteSecret       func(ctx context.Context, vaultBaseURL string, secretName string) (result keyvault.DeletedSecretBundle, err error)
 }
 
 func (mc *AzureMockClient) GetSecret(ctx context.Context, vaultBaseURL, secretName, secretVersion string) (result keyvault.SecretBundle, err error) {
@@ -42,6 +44,14 @@ func (mc *AzureMockClient) GetSecretsComplete(ctx context.Context, vaultBaseURL
 	return mc.getSecretsComplete(ctx, vaultBaseURL, maxresults)
 }
 
+func (mc *AzureMockClient) SetSecret(ctx context.Context, vaultBaseURL, secretName string, parameters keyvault.SecretSetParameters) (result keyvault.SecretBundle, err error) {
+	return mc.setSecret(ctx, vaultBaseURL, secretName, parameters)
+}
+
+func (mc *AzureMockClient) DeleteSecret(ctx context.Context, vaultBaseURL, secretName string) (result keyvault.DeletedSecretBundle, err error) {
+	return mc.deleteSecret(ctx, vaultBaseURL, secretName)
+}
+
 func (mc *AzureMockClient) WithValue(serviceURL, secretName, secretVersion string, apiOutput keyvault.SecretBundle, err error) {
 	if mc != nil {
 		mc.getSecret = func(ctx context.Context, serviceURL, secretName, secretVersion string) (result keyvault.SecretBundle, retErr error) {
@@ -73,3 +83,19 @@ func (mc *AzureMockClient) WithList(serviceURL string, apiOutput keyvault.Secret
 		}
 	}
 }
+
+func (mc *AzureMockClient) WithSetSecret(fn func(secretName string, parameters keyvault.SecretSetParameters) error) {
+	if mc != nil {
+		mc.setSecret = func(ctx context.Context, vaultBaseURL, secretName string, parameters keyvault.SecretSetParameters) (result keyvault.SecretBundle, err error) {
+			return keyvault.SecretBundle{}, fn(secretName, parameters)
+		}
+	}
+}
+
+func (mc *AzureMockClient) WithDeleteSecret(fn func(secretName string) error) {
+	if mc != nil {
+		mc.deleteSecret = func(ctx context.Context, vaultBaseURL, secretName string) (result keyvault.DeletedSecretBundle, err error) {
+			return keyvault.DeletedSecretBundle{}, fn(secretName)
+		}
+	}
+}
@@ -15,10 +15,12 @@ limitations under the License.
 package keyvault
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"regexp"
@@ -36,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	kcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/client/config"
 
@@ -78,11 +81,15 @@ const (
 	errMissingWorkloadEnvVars = "missing environment variables. AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE must be set"
 	errReadTokenFile          = "unable to read token file %s: %w"
 	errMissingSAAnnotation    = "missing service account annotation: %s"
+
+	errPushObjectType = "cannot push to Azure Keyvault object type %s, only secrets are supported"
+	errNotManaged     = "secret %q is not managed by external-secrets"
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &Azure{}
 var _ esv1beta1.Provider = &Azure{}
+var _ esv1beta1.MetadataFetcher = &Azure{}
 
 // interface to keyvault.BaseClient.
 type SecretClient interface {
@@ -90,6 +97,8 @@ type SecretClient interface {
 	GetSecret(ctx context.Context, vaultBaseURL string, secretName string, secretVersion string) (result keyvault.SecretBundle, err error)
 	GetSecretsComplete(ctx context.Context, vaultBaseURL string, maxresults *int32) (result keyvault.SecretListResultIterator, err error)
 	GetCertificate(ctx context.Context, vaultBaseURL string, certificateName string, certificateVersion string) (result keyvault.CertificateBundle, err error)
+	SetSecret(ctx context.Context, vaultBaseURL string, secretName string, parameters keyvault.SecretSetParameters) (result keyvault.SecretBundle, err error)
+	DeleteSecret(ctx context.Context, vaultBaseURL string, secretName string) (result keyvault.DeletedSecretBundle, err error)
 }
 
 type Azure struct {
@@ -161,6 +170,11 @@ func getProvider(store esv1beta1.GenericStore) (*esv1beta1.AzureKVProvider, erro
 	return spc.Provider.AzureKV, nil
 }
 
+// SupportsMetadataFetch returns true as the tags of a secret can be fetched.
+func (a *Azure) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	return true
+}
+
 func (a *Azure) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -296,7 +310,7 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 		// https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#SecretBundle
 		secretResp, err := a.baseClient.GetSecret(context.Background(), *a.provider.VaultURL, secretName, ref.Version)
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(err)
 		}
 		if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
 			return getSecretTag(secretResp.Tags, ref.Property)
@@ -307,7 +321,7 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#CertificateBundle
 		certResp, err := a.baseClient.GetCertificate(context.Background(), *a.provider.VaultURL, secretName, ref.Version)
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(err)
 		}
 		if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
 			return getSecretTag(certResp.Tags, ref.Property)
@@ -319,7 +333,7 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#KeyBundle
 		keyResp, err := a.baseClient.GetKey(context.Background(), *a.provider.VaultURL, secretName, ref.Version)
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(err)
 		}
 		if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
 			return getSecretTag(keyResp.Tags, ref.Property)
@@ -330,6 +344,117 @@ func (a *Azure) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataR
 	return nil, fmt.Errorf(errUnknownObjectType, secretName)
 }
 
+// SetSecret writes the value to a Key Vault secret. If a property is given,
+// the value is merged into the JSON document of the secret. Secrets are tagged
+// on creation and existing secrets without that tag are never overwritten.
+func (a *Azure) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	secretName, err := pushSecretName(remoteRef)
+	if err != nil {
+		return err
+	}
+	current, err := a.currentManagedSecret(ctx, secretName)
+	if err != nil {
+		return err
+	}
+	payload := value
+	if remoteRef.GetProperty() != "" {
+		payload, err = utils.SetJSONProperty(current, remoteRef.GetProperty(), value)
+		if err != nil {
+			return err
+		}
+	}
+	if current != nil && bytes.Equal(current, payload) {
+		return nil
+	}
+	_, err = a.baseClient.SetSecret(ctx, *a.provider.VaultURL, secretName, keyvault.SecretSetParameters{
+		Value: pointer.StringPtr(string(payload)),
+		Tags: map[string]*string{
+			utils.ManagedByKey: pointer.StringPtr(utils.ManagedByValue),
+		},
+	})
+	return err
+}
+
+// DeleteSecret removes a property from a secret created by SetSecret.
+// The secret is deleted once no property is left or if no property is given.
+func (a *Azure) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	secretName, err := pushSecretName(remoteRef)
+	if err != nil {
+		return err
+	}
+	current, err := a.currentManagedSecret(ctx, secretName)
+	if err != nil || current == nil {
+		return err
+	}
+	if remoteRef.GetProperty() != "" {
+		payload, empty, err := utils.DeleteJSONProperty(current, remoteRef.GetProperty())
+		if err != nil {
+			return err
+		}
+		if !empty {
+			if bytes.Equal(current, payload) {
+				return nil
+			}
+			_, err = a.baseClient.SetSecret(ctx, *a.provider.VaultURL, secretName, keyvault.SecretSetParameters{
+				Value: pointer.StringPtr(string(payload)),
+				Tags: map[string]*string{
+					utils.ManagedByKey: pointer.StringPtr(utils.ManagedByValue),
+				},
+			})
+			return err
+		}
+	}
+	_, err = a.baseClient.DeleteSecret(ctx, *a.provider.VaultURL, secretName)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func pushSecretName(remoteRef esv1beta1.PushRemoteRef) (string, error) {
+	objectType, secretName := getObjType(esv1beta1.ExternalSecretDataRemoteRef{Key: remoteRef.GetRemoteKey()})
+	if objectType != defaultObjType {
+		return "", fmt.Errorf(errPushObjectType, objectType)
+	}
+	return secretName, nil
+}
+
+// currentManagedSecret returns the value of a secret or nil if it does not exist.
+// It fails if the secret exists but has not been created by external-secrets.
+func (a *Azure) currentManagedSecret(ctx context.Context, secretName string) ([]byte, error) {
+	secretResp, err := a.baseClient.GetSecret(ctx, *a.provider.VaultURL, secretName, "")
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	managedBy, ok := secretResp.Tags[utils.ManagedByKey]
+	if !ok || managedBy == nil || *managedBy != utils.ManagedByValue {
+		return nil, fmt.Errorf(errNotManaged, secretName)
+	}
+	if secretResp.Value == nil {
+		return []byte{}, nil
+	}
+	return []byte(*secretResp.Value), nil
+}
+
+func isNotFound(err error) bool {
+	var de autorest.DetailedError
+	return errors.As(err, &de) && de.StatusCode == http.StatusNotFound
+}
+
+// classifyErr classifies an error of the Key Vault API by its HTTP status code.
+func classifyErr(err error) error {
+	var de autorest.DetailedError
+	if errors.As(err, &de) {
+		if code, ok := de.StatusCode.(int); ok {
+			return esv1beta1.FromHTTPStatus(code, err)
+		}
+	}
+	return err
+}
+
 // returns a SecretBundle with the tags values.
 func (a *Azure) getSecretTags(ref esv1beta1.ExternalSecretDataRemoteRef) (map[string]*string, error) {
 	_, secretName := getObjType(ref)
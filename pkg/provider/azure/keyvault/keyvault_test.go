@@ -23,8 +23,10 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/keyvault/2016-10-01/keyvault"
+	"github.com/Azure/go-autorest/autorest"
 	"k8s.io/utils/pointer"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fake "github.com/external-secrets/external-secrets/pkg/provider/azure/keyvault/fake"
@@ -933,3 +935,92 @@ func TestValidateStore(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureKeyVaultSetSecret(t *testing.T) {
+	managed := map[string]*string{"managed-by": pointer.StringPtr("external-secrets")}
+	notFound := autorest.DetailedError{StatusCode: 404}
+	var written *keyvault.SecretSetParameters
+	setFn := func(name string, params keyvault.SecretSetParameters) error {
+		written = &params
+		return nil
+	}
+
+	// create a new tagged secret
+	mc := &fake.AzureMockClient{}
+	mc.WithValue("", "", "", keyvault.SecretBundle{}, notFound)
+	mc.WithSetSecret(setFn)
+	az := Azure{baseClient: mc, provider: &esv1beta1.AzureKVProvider{VaultURL: pointer.StringPtr(fakeURL)}}
+	err := az.SetSecret(context.Background(), []byte(bar), esv1alpha1.PushSecretRemoteRef{RemoteKey: foo, Property: foo})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if written == nil || *written.Value != `{"foo":"bar"}` || *written.Tags["managed-by"] != "external-secrets" {
+		t.Errorf("unexpected secret parameters: %v", written)
+	}
+
+	// unchanged values are not written
+	written = nil
+	mc.WithValue("", "", "", keyvault.SecretBundle{Value: pointer.StringPtr(bar), Tags: managed}, nil)
+	err = az.SetSecret(context.Background(), []byte(bar), esv1alpha1.PushSecretRemoteRef{RemoteKey: foo})
+	if err != nil || written != nil {
+		t.Errorf("unexpected write: %v, %v", err, written)
+	}
+
+	// refuse to overwrite unmanaged secrets
+	mc.WithValue("", "", "", keyvault.SecretBundle{Value: pointer.StringPtr(bar)}, nil)
+	err = az.SetSecret(context.Background(), []byte(foo), esv1alpha1.PushSecretRemoteRef{RemoteKey: foo})
+	if !utils.ErrorContains(err, "not managed by external-secrets") || written != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// only secrets are supported
+	err = az.SetSecret(context.Background(), []byte(foo), esv1alpha1.PushSecretRemoteRef{RemoteKey: keyName})
+	if !utils.ErrorContains(err, "only secrets are supported") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAzureKeyVaultDeleteSecret(t *testing.T) {
+	managed := map[string]*string{"managed-by": pointer.StringPtr("external-secrets")}
+	deleted := ""
+	mc := &fake.AzureMockClient{}
+	mc.WithValue("", "", "", keyvault.SecretBundle{Value: pointer.StringPtr(bar), Tags: managed}, nil)
+	mc.WithDeleteSecret(func(name string) error {
+		deleted = name
+		return nil
+	})
+	az := Azure{baseClient: mc, provider: &esv1beta1.AzureKVProvider{VaultURL: pointer.StringPtr(fakeURL)}}
+	err := az.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: foo})
+	if err != nil || deleted != foo {
+		t.Errorf("secret was not deleted: %v", err)
+	}
+
+	// secrets that do not exist are ignored
+	deleted = ""
+	mc.WithValue("", "", "", keyvault.SecretBundle{}, autorest.DetailedError{StatusCode: 404})
+	err = az.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: foo})
+	if err != nil || deleted != "" {
+		t.Errorf("unexpected delete: %v", err)
+	}
+}
+
+func TestClassifyErr(t *testing.T) {
+	tbl := []struct {
+		code int
+		want interface{}
+	}{
+		{code: 401, want: &esv1beta1.AuthError{}},
+		{code: 403, want: &esv1beta1.PermissionDeniedError{}},
+		{code: 429, want: &esv1beta1.ThrottledError{}},
+		{code: 503, want: &esv1beta1.TransientError{}},
+	}
+	for _, tc := range tbl {
+		if err := classifyErr(autorest.DetailedError{StatusCode: tc.code}); !errors.As(err, tc.want) {
+			t.Errorf("%d: unexpected error type %T", tc.code, err)
+		}
+	}
+	notFound := autorest.DetailedError{StatusCode: 404}
+	if err := classifyErr(notFound); !reflect.DeepEqual(err, notFound) {
+		t.Errorf("not found: error must be returned as is, got %T", err)
+	}
+}
@@ -17,10 +17,12 @@ package fake
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 )
 
 var (
@@ -30,8 +32,16 @@ var (
 	errMissingValueField   = "at least one of value or valueMap must be set in data %v"
 )
 
+// pushed holds the values written by SetSecret, keyed by store and remote key.
+// It is shared between clients so that values survive client re-creation.
+var (
+	pushedMu sync.RWMutex
+	pushed   = make(map[string][]byte)
+)
+
 type Provider struct {
 	config *esv1beta1.FakeProvider
+	store  string
 }
 
 func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
@@ -41,6 +51,7 @@ func (p *Provider) NewClient(ctx context.Context, store esv1beta1.GenericStore,
 	}
 	return &Provider{
 		config: cfg,
+		store:  store.GetNamespacedName(),
 	}, nil
 }
 
@@ -68,9 +79,63 @@ func (p *Provider) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDa
 			return []byte(data.Value), nil
 		}
 	}
+	if ref.Version == "" {
+		pushedMu.RLock()
+		defer pushedMu.RUnlock()
+		if val, ok := pushed[p.pushedKey(ref.Key)]; ok {
+			return val, nil
+		}
+	}
 	return nil, esv1beta1.NoSecretErr
 }
 
+// SetSecret stores the value in memory. It is only visible to
+// GetSecret calls of clients created from the same store.
+func (p *Provider) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	pushedMu.Lock()
+	defer pushedMu.Unlock()
+	key := p.pushedKey(remoteRef.GetRemoteKey())
+	if remoteRef.GetProperty() == "" {
+		pushed[key] = value
+		return nil
+	}
+	doc, err := utils.SetJSONProperty(pushed[key], remoteRef.GetProperty(), value)
+	if err != nil {
+		return err
+	}
+	pushed[key] = doc
+	return nil
+}
+
+// DeleteSecret removes a value previously stored by SetSecret.
+func (p *Provider) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	pushedMu.Lock()
+	defer pushedMu.Unlock()
+	key := p.pushedKey(remoteRef.GetRemoteKey())
+	doc, ok := pushed[key]
+	if !ok {
+		return nil
+	}
+	if remoteRef.GetProperty() == "" {
+		delete(pushed, key)
+		return nil
+	}
+	doc, empty, err := utils.DeleteJSONProperty(doc, remoteRef.GetProperty())
+	if err != nil {
+		return err
+	}
+	if empty {
+		delete(pushed, key)
+		return nil
+	}
+	pushed[key] = doc
+	return nil
+}
+
+func (p *Provider) pushedKey(remoteKey string) string {
+	return p.store + "#" + remoteKey
+}
+
 // GetSecretMap returns multiple k/v pairs from the provider.
 func (p *Provider) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	for _, data := range p.config.Data {
@@ -226,3 +226,59 @@ func TestGetSecretMap(t *testing.T) {
 		})
 	}
 }
+
+type pushRef struct {
+	key      string
+	property string
+}
+
+func (r pushRef) GetRemoteKey() string {
+	return r.key
+}
+
+func (r pushRef) GetProperty() string {
+	return r.property
+}
+
+func TestSetAndDeleteSecret(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	store := &esv1beta1.SecretStore{
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Fake: &esv1beta1.FakeProvider{},
+			},
+		},
+	}
+	store.Name = "push"
+	store.Namespace = "default"
+	p := &Provider{}
+	cl, err := p.NewClient(context.Background(), store, nil, "")
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	ctx := context.Background()
+
+	// whole value
+	err = cl.SetSecret(ctx, []byte("bar"), pushRef{key: "/foo"})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	val, err := cl.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "/foo"})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(string(val)).To(gomega.Equal("bar"))
+
+	// properties are merged and survive client re-creation
+	gomega.Expect(cl.SetSecret(ctx, []byte("1"), pushRef{key: "/props", property: "a"})).To(gomega.Succeed())
+	cl, err = p.NewClient(context.Background(), store, nil, "")
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(cl.SetSecret(ctx, []byte("2"), pushRef{key: "/props", property: "b"})).To(gomega.Succeed())
+	val, err = cl.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "/props"})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(string(val)).To(gomega.Equal(`{"a":"1","b":"2"}`))
+
+	// deleting the last property removes the secret
+	gomega.Expect(cl.DeleteSecret(ctx, pushRef{key: "/props", property: "a"})).To(gomega.Succeed())
+	gomega.Expect(cl.DeleteSecret(ctx, pushRef{key: "/props", property: "b"})).To(gomega.Succeed())
+	_, err = cl.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "/props"})
+	gomega.Expect(err).To(gomega.Equal(esv1beta1.NoSecretErr))
+
+	gomega.Expect(cl.DeleteSecret(ctx, pushRef{key: "/foo"})).To(gomega.Succeed())
+	_, err = cl.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "/foo"})
+	gomega.Expect(err).To(gomega.Equal(esv1beta1.NoSecretErr))
+}
@@ -14,6 +14,7 @@ limitations under the License.
 package secretmanager
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -30,6 +31,8 @@ import (
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -64,6 +67,9 @@ const (
 	errInvalidAuthSecretRef   = "invalid auth secret ref: %w"
 	errInvalidWISARef         = "invalid workload identity service account reference: %w"
 	errUnexpectedFindOperator = "unexpected find operator"
+	errNotManaged             = "secret %q is not managed by external-secrets"
+	errClientSetSecret        = "unable to write Secret to SecretManager Client: %w"
+	errClientDeleteSecret     = "unable to delete Secret from SecretManager Client: %w"
 )
 
 var log = ctrl.Log.WithName("provider").WithName("gcp").WithName("secretsmanager")
@@ -71,6 +77,10 @@ var log = ctrl.Log.WithName("provider").WithName("gcp").WithName("secretsmanager
 type GoogleSecretManagerClient interface {
 	AccessSecretVersion(ctx context.Context, req *secretmanagerpb.AccessSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)
 	ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, opts ...gax.CallOption) *secretmanager.SecretIterator
+	GetSecret(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error)
+	CreateSecret(ctx context.Context, req *secretmanagerpb.CreateSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error)
+	AddSecretVersion(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.SecretVersion, error)
+	DeleteSecret(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest, opts ...gax.CallOption) error
 	Close() error
 }
 
@@ -86,12 +96,19 @@ var useMu = sync.Mutex{}
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &ProviderGCP{}
 var _ esv1beta1.Provider = &ProviderGCP{}
+var _ esv1beta1.MetadataFetcher = &ProviderGCP{}
+var _ esv1beta1.SecretVersionGetter = &ProviderGCP{}
 
 // ProviderGCP is a provider for GCP Secret Manager.
 type ProviderGCP struct {
 	projectID           string
 	SecretManagerClient GoogleSecretManagerClient
 	gClient             *gClient
+
+	// versions holds the version of the last value read
+	// per secret and requested version.
+	versionsMu sync.Mutex
+	versions   map[string]string
 }
 
 type gClient struct {
@@ -246,7 +263,7 @@ func (sm *ProviderGCP) findByName(ctx context.Context, ref esv1beta1.ExternalSec
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %w", err)
+			return nil, classifyErr(fmt.Errorf("failed to list secrets: %w", err))
 		}
 		log.V(1).Info("gcp sm findByName found", "secrets", strconv.Itoa(it.PageInfo().Remaining()))
 		key := sm.trimName(resp.Name)
@@ -303,7 +320,7 @@ func (sm *ProviderGCP) findByTags(ctx context.Context, ref esv1beta1.ExternalSec
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %w", err)
+			return nil, classifyErr(fmt.Errorf("failed to list secrets: %w", err))
 		}
 		key := sm.trimName(resp.Name)
 		if ref.Path != nil && !strings.HasPrefix(key, *ref.Path) {
@@ -340,6 +357,10 @@ func (sm *ProviderGCP) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecr
 		return nil, fmt.Errorf(errUninitalizedGCPProvider)
 	}
 
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return sm.getLabels(ctx, ref)
+	}
+
 	version := ref.Version
 	if version == "" {
 		version = defaultVersion
@@ -350,8 +371,9 @@ func (sm *ProviderGCP) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecr
 	}
 	result, err := sm.SecretManagerClient.AccessSecretVersion(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf(errClientGetSecretAccess, err)
+		return nil, classifyErr(fmt.Errorf(errClientGetSecretAccess, err))
 	}
+	sm.setVersion(ref, result.Name)
 
 	if ref.Property == "" {
 		if result.Payload.Data != nil {
@@ -380,6 +402,47 @@ func (sm *ProviderGCP) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecr
 	return []byte(val.String()), nil
 }
 
+func versionKey(ref esv1beta1.ExternalSecretDataRemoteRef) string {
+	version := ref.Version
+	if version == "" {
+		version = defaultVersion
+	}
+	return fmt.Sprintf("%s#%s", ref.Key, version)
+}
+
+// setVersion records the version of the value read for the ref,
+// name is the resource name of the secret version which has been accessed.
+func (sm *ProviderGCP) setVersion(ref esv1beta1.ExternalSecretDataRemoteRef, name string) {
+	sm.versionsMu.Lock()
+	defer sm.versionsMu.Unlock()
+	if sm.versions == nil {
+		sm.versions = make(map[string]string)
+	}
+	sm.versions[versionKey(ref)] = name[strings.LastIndex(name, "/")+1:]
+}
+
+// GetSecretVersion returns the version of the value which has been read last
+// for the ref, e.g. "3" for "projects/p/secrets/s/versions/3".
+func (sm *ProviderGCP) GetSecretVersion(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return "", nil
+	}
+	sm.versionsMu.Lock()
+	defer sm.versionsMu.Unlock()
+	return sm.versions[versionKey(ref)], nil
+}
+
+// getLabels returns the labels of the secret instead of its value.
+func (sm *ProviderGCP) getLabels(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, err := sm.SecretManagerClient.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, ref.Key),
+	})
+	if err != nil {
+		return nil, classifyErr(fmt.Errorf(errClientGetSecretAccess, err))
+	}
+	return utils.GetMetadataValue(secret.Labels, ref.Property)
+}
+
 // GetSecretMap returns multiple k/v pairs from the provider.
 func (sm *ProviderGCP) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	if sm.SecretManagerClient == nil || sm.projectID == "" {
@@ -411,6 +474,137 @@ func (sm *ProviderGCP) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalS
 	return secretData, nil
 }
 
+// SetSecret adds a new secret version. If a property is given, the value is
+// merged into the JSON document of the latest version. Secrets are labeled on
+// creation and existing secrets without that label are never written to.
+func (sm *ProviderGCP) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	if utils.IsNil(sm.SecretManagerClient) || sm.projectID == "" {
+		return fmt.Errorf(errUninitalizedGCPProvider)
+	}
+	key := remoteRef.GetRemoteKey()
+	exists, err := sm.getManagedSecret(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err = sm.SecretManagerClient.CreateSecret(ctx, &secretmanagerpb.CreateSecretRequest{
+			Parent:   fmt.Sprintf("projects/%s", sm.projectID),
+			SecretId: key,
+			Secret: &secretmanagerpb.Secret{
+				Replication: &secretmanagerpb.Replication{
+					Replication: &secretmanagerpb.Replication_Automatic_{
+						Automatic: &secretmanagerpb.Replication_Automatic{},
+					},
+				},
+				Labels: map[string]string{
+					utils.ManagedByKey: utils.ManagedByValue,
+				},
+			},
+		})
+		if err != nil {
+			return classifyErr(fmt.Errorf(errClientSetSecret, err))
+		}
+	}
+	current, err := sm.latestData(ctx, key)
+	if err != nil {
+		return err
+	}
+	payload := value
+	if remoteRef.GetProperty() != "" {
+		payload, err = utils.SetJSONProperty(current, remoteRef.GetProperty(), value)
+		if err != nil {
+			return err
+		}
+	}
+	if current != nil && bytes.Equal(current, payload) {
+		return nil
+	}
+	return sm.addVersion(ctx, key, payload)
+}
+
+// DeleteSecret removes a property from a secret created by SetSecret.
+// The secret is deleted once no property is left or if no property is given.
+func (sm *ProviderGCP) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	if utils.IsNil(sm.SecretManagerClient) || sm.projectID == "" {
+		return fmt.Errorf(errUninitalizedGCPProvider)
+	}
+	key := remoteRef.GetRemoteKey()
+	exists, err := sm.getManagedSecret(ctx, key)
+	if err != nil || !exists {
+		return err
+	}
+	if remoteRef.GetProperty() != "" {
+		current, err := sm.latestData(ctx, key)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			payload, empty, err := utils.DeleteJSONProperty(current, remoteRef.GetProperty())
+			if err != nil {
+				return err
+			}
+			if !empty {
+				if bytes.Equal(current, payload) {
+					return nil
+				}
+				return sm.addVersion(ctx, key, payload)
+			}
+		}
+	}
+	err = sm.SecretManagerClient.DeleteSecret(ctx, &secretmanagerpb.DeleteSecretRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, key),
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return classifyErr(fmt.Errorf(errClientDeleteSecret, err))
+	}
+	return nil
+}
+
+// getManagedSecret reports whether the secret exists. It fails if the
+// secret exists but has not been created by external-secrets.
+func (sm *ProviderGCP) getManagedSecret(ctx context.Context, key string) (bool, error) {
+	secret, err := sm.SecretManagerClient.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, key),
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, classifyErr(fmt.Errorf(errClientGetSecretAccess, err))
+	}
+	if secret.Labels[utils.ManagedByKey] != utils.ManagedByValue {
+		return false, fmt.Errorf(errNotManaged, key)
+	}
+	return true, nil
+}
+
+// latestData returns the payload of the latest version or nil if there is none.
+func (sm *ProviderGCP) latestData(ctx context.Context, key string) ([]byte, error) {
+	result, err := sm.SecretManagerClient.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/%s", sm.projectID, key, defaultVersion),
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, classifyErr(fmt.Errorf(errClientGetSecretAccess, err))
+	}
+	return result.Payload.Data, nil
+}
+
+func (sm *ProviderGCP) addVersion(ctx context.Context, key string, payload []byte) error {
+	_, err := sm.SecretManagerClient.AddSecretVersion(ctx, &secretmanagerpb.AddSecretVersionRequest{
+		Parent: fmt.Sprintf("projects/%s/secrets/%s", sm.projectID, key),
+		Payload: &secretmanagerpb.SecretPayload{
+			Data: payload,
+		},
+	})
+	if err != nil {
+		return classifyErr(fmt.Errorf(errClientSetSecret, err))
+	}
+	return nil
+}
+
 func (sm *ProviderGCP) Close(ctx context.Context) error {
 	err := sm.SecretManagerClient.Close()
 	if sm.gClient != nil {
@@ -427,6 +621,11 @@ func (sm *ProviderGCP) Validate() (esv1beta1.ValidationResult, error) {
 	return esv1beta1.ValidationResultReady, nil
 }
 
+// SupportsMetadataFetch returns true as the labels of a secret can be fetched.
+func (sm *ProviderGCP) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	return true
+}
+
 func (sm *ProviderGCP) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -460,3 +659,24 @@ func init() {
 		GCPSM: &esv1beta1.GCPSMProvider{},
 	})
 }
+
+// classifyErr classifies an error of the SecretManager API by its gRPC status code.
+func classifyErr(err error) error {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return err
+	}
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.Unauthenticated:
+		return esv1beta1.AuthError{Err: err}
+	case codes.PermissionDenied:
+		return esv1beta1.PermissionDeniedError{Err: err}
+	case codes.ResourceExhausted:
+		return esv1beta1.ThrottledError{Err: err}
+	case codes.InvalidArgument:
+		return esv1beta1.InvalidRefError{Err: err}
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Aborted:
+		return esv1beta1.TransientError{Err: err}
+	}
+	return err
+}
@@ -15,14 +15,19 @@ package secretmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/googleapis/gax-go/v2"
 	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"k8s.io/utils/pointer"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fakesm "github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager/fake"
@@ -294,3 +299,170 @@ func TestValidateStore(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSecretMetadata(t *testing.T) {
+	var requested string
+	mc := &fakesm.MockSMClient{
+		GetSecretFn: func(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+			requested = req.Name
+			return &secretmanagerpb.Secret{Labels: map[string]string{"env": "dev", "team": "platform"}}, nil
+		},
+	}
+	sm := ProviderGCP{projectID: "default", SecretManagerClient: mc}
+
+	ref := esv1beta1.ExternalSecretDataRemoteRef{
+		Key:            "foo",
+		MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+	}
+	out, err := sm.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != `{"env":"dev","team":"platform"}` {
+		t.Errorf("unexpected labels: %s, %v", out, err)
+	}
+	if requested != "projects/default/secrets/foo" {
+		t.Errorf("unexpected request: %s", requested)
+	}
+
+	ref.Property = "team"
+	out, err = sm.GetSecret(context.Background(), ref)
+	if err != nil || string(out) != "platform" {
+		t.Errorf("unexpected label value: %s, %v", out, err)
+	}
+
+	ref.Property = ""
+	data, err := sm.GetSecretMap(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(data, map[string][]byte{"env": []byte("dev"), "team": []byte("platform")}) {
+		t.Errorf("unexpected label map: %v", data)
+	}
+}
+
+func TestGetSecretVersion(t *testing.T) {
+	smtc := makeValidSecretManagerTestCaseCustom(func(smtc *secretManagerTestCase) {
+		smtc.apiOutput.Name = "projects/123/secrets/baz/versions/3"
+	})
+	sm := ProviderGCP{projectID: smtc.projectID, SecretManagerClient: smtc.mockClient}
+	if _, err := sm.GetSecret(context.Background(), *smtc.ref); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	version, err := sm.GetSecretVersion(context.Background(), *smtc.ref)
+	if err != nil || version != "3" {
+		t.Errorf("unexpected version: %q, %v", version, err)
+	}
+	other := *smtc.ref
+	other.Version = "latest"
+	version, err = sm.GetSecretVersion(context.Background(), other)
+	if err != nil || version != "" {
+		t.Errorf("unexpected version of a secret which has not been read: %q, %v", version, err)
+	}
+}
+
+func TestSetSecret(t *testing.T) {
+	latest := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: "projects/default/secrets/foo/versions/latest",
+	}
+	var added *secretmanagerpb.AddSecretVersionRequest
+	var created *secretmanagerpb.CreateSecretRequest
+	newClient := func() *fakesm.MockSMClient {
+		added, created = nil, nil
+		mc := &fakesm.MockSMClient{
+			GetSecretFn: func(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+				return &secretmanagerpb.Secret{Labels: map[string]string{"managed-by": "external-secrets"}}, nil
+			},
+			CreateSecretFn: func(ctx context.Context, req *secretmanagerpb.CreateSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+				created = req
+				return &secretmanagerpb.Secret{}, nil
+			},
+			AddSecretVersionFn: func(ctx context.Context, req *secretmanagerpb.AddSecretVersionRequest, opts ...gax.CallOption) (*secretmanagerpb.SecretVersion, error) {
+				added = req
+				return &secretmanagerpb.SecretVersion{}, nil
+			},
+		}
+		return mc
+	}
+
+	// create a new labeled secret
+	mc := newClient()
+	mc.GetSecretFn = func(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	mc.WithValue(context.Background(), latest, nil, status.Error(codes.NotFound, "not found"))
+	sm := ProviderGCP{SecretManagerClient: mc, projectID: "default"}
+	err := sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created == nil || created.Secret.Labels["managed-by"] != "external-secrets" {
+		t.Errorf("secret was not created with label: %v", created)
+	}
+	if added == nil || string(added.Payload.Data) != "bar" || added.Parent != "projects/default/secrets/foo" {
+		t.Errorf("unexpected version: %v", added)
+	}
+
+	// merge a property into the latest version
+	mc = newClient()
+	mc.WithValue(context.Background(), latest, &secretmanagerpb.AccessSecretVersionResponse{
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte(`{"other":"x"}`)},
+	}, nil)
+	sm.SecretManagerClient = mc
+	err = sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created != nil || added == nil || string(added.Payload.Data) != `{"foo":"bar","other":"x"}` {
+		t.Errorf("unexpected version: %v", added)
+	}
+
+	// refuse to write to unmanaged secrets
+	mc = newClient()
+	mc.GetSecretFn = func(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+		return &secretmanagerpb.Secret{}, nil
+	}
+	sm.SecretManagerClient = mc
+	err = sm.SetSecret(context.Background(), []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"})
+	if err == nil || !strings.Contains(err.Error(), "not managed by external-secrets") || added != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	var deleted *secretmanagerpb.DeleteSecretRequest
+	mc := &fakesm.MockSMClient{
+		GetSecretFn: func(ctx context.Context, req *secretmanagerpb.GetSecretRequest, opts ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+			return &secretmanagerpb.Secret{Labels: map[string]string{"managed-by": "external-secrets"}}, nil
+		},
+		DeleteSecretFn: func(ctx context.Context, req *secretmanagerpb.DeleteSecretRequest, opts ...gax.CallOption) error {
+			deleted = req
+			return nil
+		},
+	}
+	sm := ProviderGCP{SecretManagerClient: mc, projectID: "default"}
+	err := sm.DeleteSecret(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"})
+	if err != nil || deleted == nil || deleted.Name != "projects/default/secrets/foo" {
+		t.Errorf("secret was not deleted: %v, %v", err, deleted)
+	}
+}
+
+func TestClassifyErr(t *testing.T) {
+	tbl := []struct {
+		code codes.Code
+		want interface{}
+	}{
+		{code: codes.Unauthenticated, want: &esv1beta1.AuthError{}},
+		{code: codes.PermissionDenied, want: &esv1beta1.PermissionDeniedError{}},
+		{code: codes.ResourceExhausted, want: &esv1beta1.ThrottledError{}},
+		{code: codes.InvalidArgument, want: &esv1beta1.InvalidRefError{}},
+		{code: codes.Unavailable, want: &esv1beta1.TransientError{}},
+	}
+	for _, tc := range tbl {
+		err := fmt.Errorf(errClientGetSecretAccess, status.Error(tc.code, "failed"))
+		if err := classifyErr(err); !errors.As(err, tc.want) {
+			t.Errorf("%s: unexpected error type %T", tc.code, err)
+		}
+	}
+	notFound := fmt.Errorf(errClientGetSecretAccess, status.Error(codes.NotFound, "failed"))
+	if err := classifyErr(notFound); err != notFound {
+		t.Errorf("not found: error must be returned as is, got %T", err)
+	}
+}
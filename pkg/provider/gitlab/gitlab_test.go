@@ -15,6 +15,7 @@ package gitlab
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -174,6 +175,18 @@ func TestGitlabSecretManagerGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecretClassifiesErrors(t *testing.T) {
+	setThrottled := func(smtc *secretManagerTestCase) {
+		smtc.apiResponse.StatusCode = http.StatusTooManyRequests
+	}
+	smtc := makeValidSecretManagerTestCaseCustom(setAPIErr, setThrottled)
+	sm := Gitlab{client: smtc.mockClient}
+	_, err := sm.GetSecret(context.Background(), *smtc.ref)
+	if !errors.As(err, &esv1beta1.ThrottledError{}) {
+		t.Errorf("unexpected error type %T: %v", err, err)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCaseCustom(),
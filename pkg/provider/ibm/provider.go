@@ -51,6 +51,7 @@ const (
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &providerIBM{}
 var _ esv1beta1.Provider = &providerIBM{}
+var _ esv1beta1.MetadataFetcher = &providerIBM{}
 
 type SecretManagerClient interface {
 	GetSecret(getSecretOptions *sm.GetSecretOptions) (result *sm.GetSecret, response *core.DetailedResponse, err error)
@@ -107,6 +108,16 @@ func (ibm *providerIBM) GetAllSecrets(ctx context.Context, ref esv1beta1.Externa
 	return nil, fmt.Errorf("GetAllSecrets not implemented")
 }
 
+// SetSecret is not implemented for this provider.
+func (ibm *providerIBM) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (ibm *providerIBM) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 func (ibm *providerIBM) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if utils.IsNil(ibm.IBMClient) {
 		return nil, fmt.Errorf(errUninitalizedIBMProvider)
@@ -121,6 +132,10 @@ func (ibm *providerIBM) GetSecret(ctx context.Context, ref esv1beta1.ExternalSec
 		secretName = nameSplitted[1]
 	}
 
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return getSecretLabels(ibm, &secretName, secretType, ref)
+	}
+
 	switch secretType {
 	case sm.GetSecretOptionsSecretTypeArbitraryConst:
 
@@ -171,13 +186,13 @@ func (ibm *providerIBM) GetSecret(ctx context.Context, ref esv1beta1.ExternalSec
 }
 
 func getArbitrarySecret(ibm *providerIBM, secretName *string) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.GetSecretOptionsSecretTypeArbitraryConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -187,13 +202,13 @@ func getArbitrarySecret(ibm *providerIBM, secretName *string) ([]byte, error) {
 }
 
 func getImportCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeImportedCertConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -206,13 +221,13 @@ func getImportCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.Ext
 }
 
 func getPublicCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -225,13 +240,13 @@ func getPublicCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.Ext
 }
 
 func getPrivateCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePrivateCertConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -244,13 +259,13 @@ func getPrivateCertSecret(ibm *providerIBM, secretName *string, ref esv1beta1.Ex
 }
 
 func getIamCredentialsSecret(ibm *providerIBM, secretName *string) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeIamCredentialsConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -260,13 +275,13 @@ func getIamCredentialsSecret(ibm *providerIBM, secretName *string) ([]byte, erro
 }
 
 func getUsernamePasswordSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeUsernamePasswordConst),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -342,14 +357,34 @@ func getKVSecret(ibm *providerIBM, secretName *string, ref esv1beta1.ExternalSec
 	return nil, fmt.Errorf("no property provided for secret %s", ref.Key)
 }
 
+// getSecretLabels returns the labels of a secret instead of its value.
+// Labels of the form "key:value" are split into key and value, any other
+// label is returned as a key with an empty value.
+func getSecretLabels(ibm *providerIBM, secretName *string, secretType string, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, err := getSecretByType(ibm, secretName, secretType)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(secret.Labels))
+	for _, label := range secret.Labels {
+		kv := strings.SplitN(label, ":", 2)
+		if len(kv) == 2 {
+			labels[kv[0]] = kv[1]
+			continue
+		}
+		labels[label] = ""
+	}
+	return utils.GetMetadataValue(labels, ref.Property)
+}
+
 func getSecretByType(ibm *providerIBM, secretName *string, secretType string) (*sm.SecretResource, error) {
-	response, _, err := ibm.IBMClient.GetSecret(
+	response, detail, err := ibm.IBMClient.GetSecret(
 		&sm.GetSecretOptions{
 			SecretType: core.StringPtr(secretType),
 			ID:         secretName,
 		})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(detail, err)
 	}
 
 	secret := response.Resources[0].(*sm.SecretResource)
@@ -371,15 +406,27 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		secretName = nameSplitted[1]
 	}
 
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		labels, err := getSecretLabels(ibm, &secretName, secretType, ref)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{})
+		if err := json.Unmarshal(labels, &m); err != nil {
+			return nil, fmt.Errorf(errJSONSecretUnmarshal, err)
+		}
+		return byteArrayMap(m), nil
+	}
+
 	switch secretType {
 	case sm.GetSecretOptionsSecretTypeArbitraryConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.GetSecretOptionsSecretTypeArbitraryConst),
 				ID:         &ref.Key,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -397,13 +444,13 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		return secretMap, nil
 
 	case sm.CreateSecretOptionsSecretTypeUsernamePasswordConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeUsernamePasswordConst),
 				ID:         &secretName,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -414,13 +461,13 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		return secretMap, nil
 
 	case sm.CreateSecretOptionsSecretTypeIamCredentialsConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeIamCredentialsConst),
 				ID:         &secretName,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -432,13 +479,13 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		return secretMap, nil
 
 	case sm.CreateSecretOptionsSecretTypeImportedCertConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypeImportedCertConst),
 				ID:         &secretName,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -449,13 +496,13 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		return secretMap, nil
 
 	case sm.CreateSecretOptionsSecretTypePublicCertConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePublicCertConst),
 				ID:         &secretName,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -466,13 +513,13 @@ func (ibm *providerIBM) GetSecretMap(ctx context.Context, ref esv1beta1.External
 		return secretMap, nil
 
 	case sm.CreateSecretOptionsSecretTypePrivateCertConst:
-		response, _, err := ibm.IBMClient.GetSecret(
+		response, detail, err := ibm.IBMClient.GetSecret(
 			&sm.GetSecretOptions{
 				SecretType: core.StringPtr(sm.CreateSecretOptionsSecretTypePrivateCertConst),
 				ID:         &secretName,
 			})
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(detail, err)
 		}
 
 		secret := response.Resources[0].(*sm.SecretResource)
@@ -545,6 +592,11 @@ func (ibm *providerIBM) Validate() (esv1beta1.ValidationResult, error) {
 	return esv1beta1.ValidationResultReady, nil
 }
 
+// SupportsMetadataFetch returns true as the labels of a secret can be fetched.
+func (ibm *providerIBM) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	return true
+}
+
 func (ibm *providerIBM) ValidateStore(store esv1beta1.GenericStore) error {
 	storeSpec := store.GetSpec()
 	ibmSpec := storeSpec.Provider.IBM
@@ -670,3 +722,12 @@ func init() {
 		IBM: &esv1beta1.IBMProvider{},
 	})
 }
+
+// classifyErr classifies the error of a Secrets Manager request
+// by the HTTP status code of its response.
+func classifyErr(detail *core.DetailedResponse, err error) error {
+	if detail == nil {
+		return err
+	}
+	return esv1beta1.FromHTTPStatus(detail.StatusCode, err)
+}
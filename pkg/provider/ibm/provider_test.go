@@ -15,6 +15,7 @@ package ibm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -398,6 +399,35 @@ func TestIBMSecretManagerGetSecret(t *testing.T) {
 		smtc.expectedSecret = secretKVComplex
 	}
 
+	// good case: fetch labels instead of the secret value
+	setSecretLabels := func(smtc *secretManagerTestCase) {
+		resources := []sm.SecretResourceIntf{
+			&sm.SecretResource{
+				SecretType: utilpointer.StringPtr("testytype"),
+				Name:       utilpointer.StringPtr("testyname"),
+				SecretData: secretData,
+				Labels:     []string{"env:dev", "critical"},
+			}}
+		smtc.ref.MetadataPolicy = esv1beta1.ExternalSecretMetadataPolicyFetch
+		smtc.apiOutput.Resources = resources
+		smtc.expectedSecret = `{"critical":"","env":"dev"}`
+	}
+
+	// good case: fetch a single label value
+	setSecretLabelWithProperty := func(smtc *secretManagerTestCase) {
+		resources := []sm.SecretResourceIntf{
+			&sm.SecretResource{
+				SecretType: utilpointer.StringPtr("testytype"),
+				Name:       utilpointer.StringPtr("testyname"),
+				SecretData: secretData,
+				Labels:     []string{"env:dev", "critical"},
+			}}
+		smtc.ref.MetadataPolicy = esv1beta1.ExternalSecretMetadataPolicyFetch
+		smtc.ref.Property = "env"
+		smtc.apiOutput.Resources = resources
+		smtc.expectedSecret = "dev"
+	}
+
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCase(),
 		makeValidSecretManagerTestCaseCustom(setSecretString),
@@ -419,6 +449,8 @@ func TestIBMSecretManagerGetSecret(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(badSecretPublicCert),
 		makeValidSecretManagerTestCaseCustom(setSecretPrivateCert),
 		makeValidSecretManagerTestCaseCustom(badSecretPrivateCert),
+		makeValidSecretManagerTestCaseCustom(setSecretLabels),
+		makeValidSecretManagerTestCaseCustom(setSecretLabelWithProperty),
 	}
 
 	sm := providerIBM{}
@@ -628,6 +660,20 @@ func TestGetSecretMap(t *testing.T) {
 		smtc.expectError = "key unknown.property does not exist in secret kv/test-secret"
 	}
 
+	// good case: labels instead of the secret value
+	setSecretLabels := func(smtc *secretManagerTestCase) {
+		resources := []sm.SecretResourceIntf{
+			&sm.SecretResource{
+				SecretType: utilpointer.StringPtr("testytype"),
+				Name:       utilpointer.StringPtr("testyname"),
+				Labels:     []string{"env:dev", "team:platform"},
+			}}
+		smtc.ref.MetadataPolicy = esv1beta1.ExternalSecretMetadataPolicyFetch
+		smtc.apiOutput.Resources = resources
+		smtc.expectedData["env"] = []byte("dev")
+		smtc.expectedData["team"] = []byte("platform")
+	}
+
 	successCases := []*secretManagerTestCase{
 		makeValidSecretManagerTestCaseCustom(setDeserialization),
 		makeValidSecretManagerTestCaseCustom(setInvalidJSON),
@@ -642,6 +688,7 @@ func TestGetSecretMap(t *testing.T) {
 		makeValidSecretManagerTestCaseCustom(badSecretKVWithUnknownProperty),
 		makeValidSecretManagerTestCaseCustom(setSecretPublicCert),
 		makeValidSecretManagerTestCaseCustom(setSecretPrivateCert),
+		makeValidSecretManagerTestCaseCustom(setSecretLabels),
 	}
 
 	sm := providerIBM{}
@@ -657,6 +704,26 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestClassifyErr(t *testing.T) {
+	errBoom := errors.New("boom")
+	tbl := []struct {
+		detail *core.DetailedResponse
+		want   interface{}
+	}{
+		{detail: &core.DetailedResponse{StatusCode: 401}, want: &esv1beta1.AuthError{}},
+		{detail: &core.DetailedResponse{StatusCode: 429}, want: &esv1beta1.ThrottledError{}},
+		{detail: &core.DetailedResponse{StatusCode: 503}, want: &esv1beta1.TransientError{}},
+	}
+	for _, row := range tbl {
+		if err := classifyErr(row.detail, errBoom); !errors.As(err, row.want) {
+			t.Errorf("%d: unexpected error type %T", row.detail.StatusCode, err)
+		}
+	}
+	if err := classifyErr(nil, errBoom); err != errBoom {
+		t.Errorf("error without response must be returned as is, got %T", err)
+	}
+}
+
 func TestValidRetryInput(t *testing.T) {
 	sm := providerIBM{}
 
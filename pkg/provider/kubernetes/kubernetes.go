@@ -17,10 +17,13 @@ package kubernetes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
@@ -36,9 +39,17 @@ import (
 var _ esv1beta1.SecretsClient = &ProviderKubernetes{}
 var _ esv1beta1.Provider = &ProviderKubernetes{}
 
+const (
+	errNotManaged = "secret %q is not managed by external-secrets"
+	errPushFormat = "value must be a JSON object of strings when no property is set: %w"
+)
+
 type KClient interface {
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error)
 	List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error)
+	Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error)
+	Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 }
 
 type RClient interface {
@@ -173,11 +184,101 @@ func (p *ProviderKubernetes) GetSecret(ctx context.Context, ref esv1beta1.Extern
 func (p *ProviderKubernetes) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
 	secret, err := p.Client.Get(ctx, ref.Key, metav1.GetOptions{})
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(err)
 	}
 	return secret.Data, nil
 }
 
+// SetSecret writes the value into a key of the remote secret. If no property
+// is given, the value must be a JSON object which replaces the secret data.
+// Secrets are labeled on creation and existing secrets without that label
+// are never overwritten.
+func (p *ProviderKubernetes) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	data := make(map[string][]byte)
+	if remoteRef.GetProperty() == "" {
+		kv := make(map[string]string)
+		if err := json.Unmarshal(value, &kv); err != nil {
+			return fmt.Errorf(errPushFormat, err)
+		}
+		for k, v := range kv {
+			data[k] = []byte(v)
+		}
+	}
+	secret, err := p.getManagedSecret(ctx, remoteRef.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		if remoteRef.GetProperty() != "" {
+			data[remoteRef.GetProperty()] = value
+		}
+		_, err = p.Client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      remoteRef.GetRemoteKey(),
+				Namespace: p.Namespace,
+				Labels: map[string]string{
+					utils.ManagedByKey: utils.ManagedByValue,
+				},
+			},
+			Data: data,
+			Type: corev1.SecretTypeOpaque,
+		}, metav1.CreateOptions{})
+		return classifyErr(err)
+	}
+	if remoteRef.GetProperty() != "" {
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+		data[remoteRef.GetProperty()] = value
+	}
+	if reflect.DeepEqual(secret.Data, data) {
+		return nil
+	}
+	secret.Data = data
+	_, err = p.Client.Update(ctx, secret, metav1.UpdateOptions{})
+	return classifyErr(err)
+}
+
+// DeleteSecret removes a key from a secret created by SetSecret.
+// The secret is deleted once no key is left or if no property is given.
+func (p *ProviderKubernetes) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	secret, err := p.getManagedSecret(ctx, remoteRef.GetRemoteKey())
+	if err != nil || secret == nil {
+		return err
+	}
+	if remoteRef.GetProperty() != "" {
+		if _, ok := secret.Data[remoteRef.GetProperty()]; !ok {
+			return nil
+		}
+		delete(secret.Data, remoteRef.GetProperty())
+		if len(secret.Data) > 0 {
+			_, err = p.Client.Update(ctx, secret, metav1.UpdateOptions{})
+			return classifyErr(err)
+		}
+	}
+	err = p.Client.Delete(ctx, remoteRef.GetRemoteKey(), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return classifyErr(err)
+}
+
+// getManagedSecret returns nil if the secret does not exist and fails
+// if it exists but has not been created by external-secrets.
+func (p *ProviderKubernetes) getManagedSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret, err := p.Client.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, classifyErr(err)
+	}
+	if secret.Labels[utils.ManagedByKey] != utils.ManagedByValue {
+		return nil, fmt.Errorf(errNotManaged, name)
+	}
+	return secret, nil
+}
+
 func (p *ProviderKubernetes) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	if ref.Tags != nil {
 		return p.findByTags(ctx, ref)
@@ -196,7 +297,7 @@ func (p *ProviderKubernetes) findByTags(ctx context.Context, ref esv1beta1.Exter
 	}
 	secrets, err := p.Client.List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
 	if err != nil {
-		return nil, fmt.Errorf("unable to list secrets: %w", err)
+		return nil, classifyErr(fmt.Errorf("unable to list secrets: %w", err))
 	}
 	data := make(map[string][]byte)
 	for _, secret := range secrets.Items {
@@ -212,7 +313,7 @@ func (p *ProviderKubernetes) findByTags(ctx context.Context, ref esv1beta1.Exter
 func (p *ProviderKubernetes) findByName(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 	secrets, err := p.Client.List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("unable to list secrets: %w", err)
+		return nil, classifyErr(fmt.Errorf("unable to list secrets: %w", err))
 	}
 	matcher, err := find.New(*ref.Name)
 	if err != nil {
@@ -232,6 +333,15 @@ func (p *ProviderKubernetes) findByName(ctx context.Context, ref esv1beta1.Exter
 	return utils.ConvertKeys(ref.ConversionStrategy, data)
 }
 
+// classifyErr classifies an error of the Kubernetes API by its HTTP status code.
+func classifyErr(err error) error {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return esv1beta1.FromHTTPStatus(int(status.Status().Code), err)
+	}
+	return err
+}
+
 func convertMap(in map[string][]byte) map[string]string {
 	out := make(map[string]string)
 	for k, v := range in {
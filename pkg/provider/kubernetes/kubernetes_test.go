@@ -21,11 +21,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 )
@@ -63,11 +65,26 @@ func (fk fakeClient) Get(ctx context.Context, name string, opts metav1.GetOption
 	secret, ok := fk.secretMap[name]
 
 	if !ok {
-		return nil, errors.New(errSomethingWentWrong)
+		return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
 	}
 	return &secret, nil
 }
 
+func (fk fakeClient) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	fk.secretMap[secret.Name] = *secret
+	return secret, nil
+}
+
+func (fk fakeClient) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	fk.secretMap[secret.Name] = *secret
+	return secret, nil
+}
+
+func (fk fakeClient) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	delete(fk.secretMap, name)
+	return nil
+}
+
 func (fk fakeClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error) {
 	assert.Equal(fk.t, fk.expectedListOptions, opts)
 	list := &corev1.SecretList{}
@@ -188,6 +205,29 @@ func TestGetSecret(t *testing.T) {
 	}
 }
 
+func TestClassifyErr(t *testing.T) {
+	gr := corev1.Resource("secrets")
+	tests := []struct {
+		name string
+		err  error
+		want interface{}
+	}{
+		{name: "unauthorized", err: apierrors.NewUnauthorized("token expired"), want: &esv1beta1.AuthError{}},
+		{name: "forbidden", err: apierrors.NewForbidden(gr, "mysec", errors.New("denied")), want: &esv1beta1.PermissionDeniedError{}},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 1), want: &esv1beta1.ThrottledError{}},
+		{name: "unavailable", err: apierrors.NewServiceUnavailable("unavailable"), want: &esv1beta1.TransientError{}},
+	}
+	for _, tt := range tests {
+		if err := classifyErr(tt.err); !errors.As(err, tt.want) {
+			t.Errorf("%s: unexpected error type %T", tt.name, err)
+		}
+	}
+	notFound := apierrors.NewNotFound(gr, "mysec")
+	if err := classifyErr(notFound); err != notFound {
+		t.Errorf("not found: error must be returned as is, got %T", err)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	type fields struct {
 		Client       KClient
@@ -461,3 +501,72 @@ func TestGetAllSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSecret(t *testing.T) {
+	secretMap := map[string]corev1.Secret{
+		"unmanaged": {
+			ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"},
+			Data:       map[string][]byte{"token": []byte("foo")},
+		},
+	}
+	p := ProviderKubernetes{
+		Client:    fakeClient{t: t, secretMap: secretMap},
+		Namespace: "default",
+	}
+	ctx := context.Background()
+
+	// create a labeled secret
+	err := p.SetSecret(ctx, []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec", Property: "token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "external-secrets", secretMap["mysec"].Labels["managed-by"])
+	assert.Equal(t, map[string][]byte{"token": []byte("bar")}, secretMap["mysec"].Data)
+
+	// merge another key
+	err = p.SetSecret(ctx, []byte("baz"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec", Property: "other"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"token": []byte("bar"), "other": []byte("baz")}, secretMap["mysec"].Data)
+
+	// replace the whole secret
+	err = p.SetSecret(ctx, []byte(`{"a":"b"}`), esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("b")}, secretMap["mysec"].Data)
+
+	// the whole secret must be a JSON object
+	err = p.SetSecret(ctx, []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec"})
+	assert.Error(t, err)
+
+	// do not touch unmanaged secrets
+	err = p.SetSecret(ctx, []byte("bar"), esv1alpha1.PushSecretRemoteRef{RemoteKey: "unmanaged", Property: "token"})
+	assert.EqualError(t, err, `secret "unmanaged" is not managed by external-secrets`)
+	assert.Equal(t, []byte("foo"), secretMap["unmanaged"].Data["token"])
+}
+
+func TestDeleteSecret(t *testing.T) {
+	secretMap := map[string]corev1.Secret{
+		"mysec": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "mysec",
+				Labels: map[string]string{"managed-by": "external-secrets"},
+			},
+			Data: map[string][]byte{"token": []byte("foo"), "other": []byte("bar")},
+		},
+	}
+	p := ProviderKubernetes{
+		Client:    fakeClient{t: t, secretMap: secretMap},
+		Namespace: "default",
+	}
+	ctx := context.Background()
+
+	err := p.DeleteSecret(ctx, esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec", Property: "token"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"other": []byte("bar")}, secretMap["mysec"].Data)
+
+	err = p.DeleteSecret(ctx, esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec", Property: "other"})
+	assert.NoError(t, err)
+	_, ok := secretMap["mysec"]
+	assert.False(t, ok)
+
+	// deleting a missing secret is a no-op
+	err = p.DeleteSecret(ctx, esv1alpha1.PushSecretRemoteRef{RemoteKey: "mysec"})
+	assert.NoError(t, err)
+}
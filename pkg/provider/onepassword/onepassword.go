@@ -15,6 +15,7 @@ package onepassword
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"sort"
@@ -155,13 +156,14 @@ func (provider *ProviderOnePassword) GetSecret(ctx context.Context, ref esv1beta
 
 	item, err := provider.findItem(ref.Key)
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(err)
 	}
 
 	// handle files
 	if item.Category == documentCategory {
 		// default to the first file when ref.Property is empty
-		return provider.getFile(item, ref.Property)
+		data, err := provider.getFile(item, ref.Property)
+		return data, classifyErr(err)
 	}
 
 	// handle fields
@@ -189,12 +191,13 @@ func (provider *ProviderOnePassword) GetSecretMap(ctx context.Context, ref esv1b
 
 	item, err := provider.findItem(ref.Key)
 	if err != nil {
-		return nil, err
+		return nil, classifyErr(err)
 	}
 
 	// handle files
 	if item.Category == documentCategory {
-		return provider.getFiles(item, ref.Property)
+		data, err := provider.getFiles(item, ref.Property)
+		return data, classifyErr(err)
 	}
 
 	// handle fields
@@ -212,18 +215,28 @@ func (provider *ProviderOnePassword) GetAllSecrets(ctx context.Context, ref esv1
 	for _, vaultName := range sortedVaults {
 		vault, err := provider.client.GetVaultByTitle(vaultName)
 		if err != nil {
-			return nil, fmt.Errorf(errGetVault, err)
+			return nil, classifyErr(fmt.Errorf(errGetVault, err))
 		}
 
 		err = provider.getAllForVault(vault.ID, ref, secretData)
 		if err != nil {
-			return nil, err
+			return nil, classifyErr(err)
 		}
 	}
 
 	return secretData, nil
 }
 
+// SetSecret is not implemented for this provider.
+func (provider *ProviderOnePassword) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (provider *ProviderOnePassword) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 // Close closes the client connection.
 func (provider *ProviderOnePassword) Close(ctx context.Context) error {
 	return nil
@@ -405,6 +418,15 @@ func (provider *ProviderOnePassword) getAllForVault(vaultID string, ref esv1beta
 	return nil
 }
 
+// classifyErr classifies an error of the 1Password Connect API by its HTTP status code.
+func classifyErr(err error) error {
+	var opErr *onepassword.Error
+	if errors.As(err, &opErr) {
+		return esv1beta1.FromHTTPStatus(opErr.StatusCode, err)
+	}
+	return err
+}
+
 func countFieldsWithLabel(fieldLabel string, fields []*onepassword.ItemField) int {
 	count := 0
 	for _, field := range fields {
@@ -15,6 +15,7 @@ package onepassword
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -1317,6 +1318,42 @@ func TestGetAllSecrets(t *testing.T) {
 	}
 }
 
+// failingVaultClient fails to look up vaults with err.
+type failingVaultClient struct {
+	*fake.OnePasswordMockClient
+	err error
+}
+
+func (c *failingVaultClient) GetVaultByTitle(string) (*onepassword.Vault, error) {
+	return nil, c.err
+}
+
+func TestClassifiesErrors(t *testing.T) {
+	tbl := []struct {
+		err  error
+		want interface{}
+	}{
+		{err: &onepassword.Error{StatusCode: 401, Message: "invalid token"}, want: &esv1beta1.AuthError{}},
+		{err: &onepassword.Error{StatusCode: 403, Message: "forbidden"}, want: &esv1beta1.PermissionDeniedError{}},
+		{err: &onepassword.Error{StatusCode: 503, Message: "unavailable"}, want: &esv1beta1.TransientError{}},
+	}
+	for _, row := range tbl {
+		provider := &ProviderOnePassword{
+			vaults: map[string]int{myVault: 1},
+			client: &failingVaultClient{OnePasswordMockClient: fake.NewMockClient(), err: row.err},
+		}
+		if _, err := provider.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: myItem}); !errors.As(err, row.want) {
+			t.Errorf("GetSecret: unexpected error type %T: %v", err, err)
+		}
+		if _, err := provider.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: myItem}); !errors.As(err, row.want) {
+			t.Errorf("GetSecretMap: unexpected error type %T: %v", err, err)
+		}
+		if _, err := provider.GetAllSecrets(context.Background(), esv1beta1.ExternalSecretFind{}); !errors.As(err, row.want) {
+			t.Errorf("GetAllSecrets: unexpected error type %T: %v", err, err)
+		}
+	}
+}
+
 func TestSortVaults(t *testing.T) {
 	type testCase struct {
 		vaults   map[string]int
@@ -15,6 +15,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/oracle/oci-go-sdk/v56/common"
@@ -71,6 +72,16 @@ func (vms *VaultManagementService) GetAllSecrets(ctx context.Context, ref esv1be
 	return nil, fmt.Errorf("GetAllSecrets not implemented")
 }
 
+// SetSecret is not implemented for this provider.
+func (vms *VaultManagementService) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (vms *VaultManagementService) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 func (vms *VaultManagementService) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if utils.IsNil(vms.Client) {
 		return nil, fmt.Errorf(errUninitalizedOracleProvider)
@@ -82,7 +93,7 @@ func (vms *VaultManagementService) GetSecret(ctx context.Context, ref esv1beta1.
 		Stage:      secrets.GetSecretBundleByNameStageEnum(ref.Version),
 	})
 	if err != nil {
-		return nil, util.SanitizeErr(err)
+		return nil, classifyErr(err)
 	}
 
 	bt, ok := sec.SecretBundleContent.(secrets.Base64SecretBundleContentDetails)
@@ -293,3 +304,13 @@ func init() {
 		Oracle: &esv1beta1.OracleProvider{},
 	})
 }
+
+// classifyErr classifies an error of the OCI API by its HTTP status code.
+func classifyErr(err error) error {
+	sanitized := util.SanitizeErr(err)
+	var svcErr common.ServiceError
+	if errors.As(err, &svcErr) {
+		return esv1beta1.FromHTTPStatus(svcErr.GetHTTPStatusCode(), sanitized)
+	}
+	return sanitized
+}
@@ -14,6 +14,7 @@ package oracle
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -141,6 +142,37 @@ func TestOracleVaultGetSecret(t *testing.T) {
 	}
 }
 
+// serviceError is an error answered by the OCI API.
+type serviceError struct {
+	code int
+}
+
+func (e serviceError) Error() string           { return fmt.Sprintf("service error %d", e.code) }
+func (e serviceError) GetHTTPStatusCode() int  { return e.code }
+func (e serviceError) GetMessage() string      { return "" }
+func (e serviceError) GetCode() string         { return "" }
+func (e serviceError) GetOpcRequestID() string { return "" }
+
+func TestGetSecretClassifiesErrors(t *testing.T) {
+	tbl := []struct {
+		err  error
+		want interface{}
+	}{
+		{err: serviceError{code: 401}, want: &esv1beta1.AuthError{}},
+		{err: serviceError{code: 429}, want: &esv1beta1.ThrottledError{}},
+		{err: serviceError{code: 500}, want: &esv1beta1.TransientError{}},
+	}
+	for _, row := range tbl {
+		smtc := makeValidVaultTestCaseCustom(func(smtc *vaultTestCase) {
+			smtc.apiErr = row.err
+		})
+		sm := VaultManagementService{Client: smtc.mockClient}
+		if _, err := sm.GetSecret(context.Background(), *smtc.ref); !errors.As(err, row.want) {
+			t.Errorf("unexpected error type %T: %v", err, err)
+		}
+	}
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(smtc *vaultTestCase) {
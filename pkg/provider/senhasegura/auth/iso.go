@@ -128,7 +128,7 @@ func (s *SenhaseguraIsoSession) GetIsoToken(clientID, clientSecret, systemURL st
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return "", errInvalidHTTPCode
+		return "", esv1beta1.FromHTTPStatus(resp.StatusCode, errInvalidHTTPCode)
 	}
 
 	respData, err := ioutil.ReadAll(resp.Body)
@@ -157,6 +157,16 @@ func (dsm *DSM) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretF
 	return nil, fmt.Errorf("GetAllSecrets not implemented yet")
 }
 
+// SetSecret is not implemented for this provider.
+func (dsm *DSM) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (dsm *DSM) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 /*
 	fetchSecrets calls senhasegura DSM /iso/dapp/application API endpoint
 	Return an IsoDappResponse with all related information from senhasegura provider with DSM service and error
@@ -187,7 +197,7 @@ func (dsm *DSM) FetchSecrets() (respObj IsoDappResponse, err error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return respObj, errInvalidHTTPCode
+		return respObj, esv1beta1.FromHTTPStatus(resp.StatusCode, errInvalidHTTPCode)
 	}
 
 	respData, err := ioutil.ReadAll(resp.Body)
@@ -23,6 +23,7 @@ import (
 )
 
 var _ esv1beta1.Provider = &Client{}
+var _ esv1beta1.SecretVersionGetter = &Client{}
 
 // Client is a fake client for testing.
 type Client struct {
@@ -30,6 +31,10 @@ type Client struct {
 	GetSecretFn     func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error)
 	GetSecretMapFn  func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error)
 	GetAllSecretsFn func(context.Context, esv1beta1.ExternalSecretFind) (map[string][]byte, error)
+	SetSecretFn     func(context.Context, []byte, esv1beta1.PushRemoteRef) error
+	DeleteSecretFn  func(context.Context, esv1beta1.PushRemoteRef) error
+	// GetSecretVersionFn is optional, no version is reported if it is nil.
+	GetSecretVersionFn func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) (string, error)
 }
 
 // New returns a fake provider/client.
@@ -44,6 +49,12 @@ func New() *Client {
 		GetAllSecretsFn: func(context.Context, esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
 			return nil, nil
 		},
+		SetSecretFn: func(context.Context, []byte, esv1beta1.PushRemoteRef) error {
+			return nil
+		},
+		DeleteSecretFn: func(context.Context, esv1beta1.PushRemoteRef) error {
+			return nil
+		},
 	}
 
 	v.NewFn = func(context.Context, esv1beta1.GenericStore, client.Client, string) (esv1beta1.SecretsClient, error) {
@@ -81,6 +92,48 @@ func (v *Client) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretD
 	return v.GetSecretMapFn(ctx, ref)
 }
 
+// GetSecretVersion implements the provider.SecretVersionGetter interface.
+func (v *Client) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	if v.GetSecretVersionFn == nil {
+		return "", nil
+	}
+	return v.GetSecretVersionFn(ctx, ref)
+}
+
+// WithGetSecretVersion wraps the version reported by this fake provider.
+func (v *Client) WithGetSecretVersion(version string) *Client {
+	v.GetSecretVersionFn = func(context.Context, esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+		return version, nil
+	}
+	return v
+}
+
+// SetSecret implements the provider.Provider interface.
+func (v *Client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return v.SetSecretFn(ctx, value, remoteRef)
+}
+
+// WithSetSecret wraps the error returned by SetSecret.
+func (v *Client) WithSetSecret(err error) *Client {
+	v.SetSecretFn = func(context.Context, []byte, esv1beta1.PushRemoteRef) error {
+		return err
+	}
+	return v
+}
+
+// DeleteSecret implements the provider.Provider interface.
+func (v *Client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return v.DeleteSecretFn(ctx, remoteRef)
+}
+
+// WithDeleteSecret wraps the error returned by DeleteSecret.
+func (v *Client) WithDeleteSecret(err error) *Client {
+	v.DeleteSecretFn = func(context.Context, esv1beta1.PushRemoteRef) error {
+		return err
+	}
+	return v
+}
+
 func (v *Client) Close(ctx context.Context) error {
 	return nil
 }
@@ -130,4 +183,5 @@ func (v *Client) Reset() {
 		string) (esv1beta1.SecretsClient, error) {
 		return v, nil
 	})
+	v.GetSecretVersionFn = nil
 }
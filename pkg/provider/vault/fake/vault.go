@@ -32,11 +32,13 @@ func (f Auth) Login(ctx context.Context, authMethod vault.AuthMethod) (*vault.Se
 type ReadWithDataWithContextFn func(ctx context.Context, path string, data map[string][]string) (*vault.Secret, error)
 type ListWithContextFn func(ctx context.Context, path string) (*vault.Secret, error)
 type WriteWithContextFn func(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error)
+type DeleteWithContextFn func(ctx context.Context, path string) (*vault.Secret, error)
 
 type Logical struct {
 	ReadWithDataWithContextFn ReadWithDataWithContextFn
 	ListWithContextFn         ListWithContextFn
 	WriteWithContextFn        WriteWithContextFn
+	DeleteWithContextFn       DeleteWithContextFn
 }
 
 func NewReadWithContextFn(secret map[string]interface{}, err error) ReadWithDataWithContextFn {
@@ -57,13 +59,18 @@ func (f Logical) ListWithContext(ctx context.Context, path string) (*vault.Secre
 func (f Logical) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
 	return f.WriteWithContextFn(ctx, path, data)
 }
+func (f Logical) DeleteWithContext(ctx context.Context, path string) (*vault.Secret, error) {
+	return f.DeleteWithContextFn(ctx, path)
+}
 
 type RevokeSelfWithContextFn func(ctx context.Context, token string) error
 type LookupSelfWithContextFn func(ctx context.Context) (*vault.Secret, error)
+type RenewSelfWithContextFn func(ctx context.Context, increment int) (*vault.Secret, error)
 
 type Token struct {
 	RevokeSelfWithContextFn RevokeSelfWithContextFn
 	LookupSelfWithContextFn LookupSelfWithContextFn
+	RenewSelfWithContextFn  RenewSelfWithContextFn
 }
 
 func (f Token) RevokeSelfWithContext(ctx context.Context, token string) error {
@@ -72,6 +79,9 @@ func (f Token) RevokeSelfWithContext(ctx context.Context, token string) error {
 func (f Token) LookupSelfWithContext(ctx context.Context) (*vault.Secret, error) {
 	return f.LookupSelfWithContextFn(ctx)
 }
+func (f Token) RenewSelfWithContext(ctx context.Context, increment int) (*vault.Secret, error) {
+	return f.RenewSelfWithContextFn(ctx, increment)
+}
 
 type MockSetTokenFn func(v string)
 
@@ -140,6 +150,9 @@ func NewVaultLogical() Logical {
 		WriteWithContextFn: func(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
 			return nil, nil
 		},
+		DeleteWithContextFn: func(ctx context.Context, path string) (*vault.Secret, error) {
+			return nil, nil
+		},
 	}
 	return logical
 }
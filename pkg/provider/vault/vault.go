@@ -23,8 +23,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	vault "github.com/hashicorp/vault/api"
@@ -55,6 +58,9 @@ var (
 
 const (
 	serviceAccTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	// minTokenTTL is the remaining lifetime below which a token
+	// which can not be renewed is no longer used.
+	minTokenTTL = time.Minute
 
 	errVaultStore           = "received invalid Vault SecretStore resource: %w"
 	errVaultClient          = "cannot setup new vault client: %w"
@@ -73,6 +79,12 @@ const (
 	errJwtNoTokenSource     = "neither `secretRef` nor `kubernetesServiceAccountToken` was supplied as token source for jwt authentication"
 	errUnsupportedKvVersion = "cannot perform find operations with kv version v1"
 	errNotFound             = "secret not found"
+	errPushUnsupportedKv    = "cannot push secrets with kv version v1"
+	errUnsupportedMetadata  = "cannot fetch metadata with kv version v1"
+	errNotManaged           = "secret %q is not managed by external-secrets"
+	errPushFormat           = "value must be a JSON object when no property is set: %w"
+	errWriteSecret          = "cannot write secret data to Vault: %w"
+	errDeleteSecret         = "cannot delete secret from Vault: %w"
 
 	errGetKubeSA             = "cannot get Kubernetes service account %q: %w"
 	errGetKubeSASecrets      = "cannot find secrets bound to service account: %q"
@@ -85,7 +97,9 @@ const (
 
 	errClientTLSAuth = "error from Client TLS Auth: %q"
 
-	errVaultRevokeToken = "error while revoking token: %w"
+	errVaultRevokeToken   = "error while revoking token: %w"
+	errVaultRenewToken    = "error while renewing token: %w"
+	errVaultTokenExpiring = "token expires in %s and can not be renewed"
 
 	errUnknownCAProvider = "unknown caProvider type given"
 	errCANamespace       = "cannot read secret for CAProvider due to missing namespace on kind ClusterSecretStore"
@@ -107,7 +121,10 @@ const (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &client{}
+var _ esv1beta1.SecretsClientRenewer = &client{}
+var _ esv1beta1.SecretVersionGetter = &client{}
 var _ esv1beta1.Provider = &connector{}
+var _ esv1beta1.MetadataFetcher = &connector{}
 
 type Auth interface {
 	Login(ctx context.Context, authMethod vault.AuthMethod) (*vault.Secret, error)
@@ -116,12 +133,14 @@ type Auth interface {
 type Token interface {
 	RevokeSelfWithContext(ctx context.Context, token string) error
 	LookupSelfWithContext(ctx context.Context) (*vault.Secret, error)
+	RenewSelfWithContext(ctx context.Context, increment int) (*vault.Secret, error)
 }
 
 type Logical interface {
 	ReadWithDataWithContext(ctx context.Context, path string, data map[string][]string) (*vault.Secret, error)
 	ListWithContext(ctx context.Context, path string) (*vault.Secret, error)
 	WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error)
+	DeleteWithContext(ctx context.Context, path string) (*vault.Secret, error)
 }
 
 type Client interface {
@@ -189,6 +208,11 @@ type client struct {
 	token     Token
 	namespace string
 	storeKind string
+
+	// versions holds the kv v2 version of the last value read per path
+	// and requested version, a client may be used by concurrent fetches.
+	versionsMu sync.Mutex
+	versions   map[string]string
 }
 
 func init() {
@@ -289,6 +313,15 @@ func (c *connector) newClient(ctx context.Context, store esv1beta1.GenericStore,
 	return vStore, nil
 }
 
+// SupportsMetadataFetch returns true for kv v2 stores
+// as custom_metadata does not exist in kv v1.
+func (c *connector) SupportsMetadataFetch(store esv1beta1.GenericStore) bool {
+	if store == nil || store.GetSpec() == nil || store.GetSpec().Provider == nil || store.GetSpec().Provider.Vault == nil {
+		return false
+	}
+	return store.GetSpec().Provider.Vault.Version != esv1beta1.VaultKVStoreV1
+}
+
 func (c *connector) ValidateStore(store esv1beta1.GenericStore) error {
 	if store == nil {
 		return fmt.Errorf(errInvalidStore)
@@ -430,7 +463,7 @@ func (v *client) listSecrets(ctx context.Context, path string) ([]string, error)
 	}
 	secret, err := v.logical.ListWithContext(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, classifyErr(fmt.Errorf(errReadSecret, err))
 	}
 	t, ok := secret.Data["keys"]
 	if !ok {
@@ -465,7 +498,7 @@ func (v *client) readSecretMetadata(ctx context.Context, path string) (map[strin
 	}
 	secret, err := v.logical.ReadWithDataWithContext(ctx, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, classifyErr(fmt.Errorf(errReadSecret, err))
 	}
 	if secret == nil {
 		return nil, errors.New(errNotFound)
@@ -484,12 +517,160 @@ func (v *client) readSecretMetadata(ctx context.Context, path string) (map[strin
 	return metadata, nil
 }
 
+// SetSecret writes the value to a kv v2 secret. If a property is given,
+// the value is merged into the existing secret data, otherwise the value
+// must be a JSON object which replaces the secret data.
+// Secrets that exist but were not created by external-secrets are never overwritten.
+func (v *client) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	if v.store.Version != esv1beta1.VaultKVStoreV2 {
+		return errors.New(errPushUnsupportedKv)
+	}
+	key := remoteRef.GetRemoteKey()
+	current, err := v.readManagedSecret(ctx, key)
+	if err != nil {
+		return err
+	}
+	secretData := make(map[string]interface{})
+	if remoteRef.GetProperty() == "" {
+		if err := json.Unmarshal(value, &secretData); err != nil {
+			return fmt.Errorf(errPushFormat, err)
+		}
+	} else {
+		for k, val := range current {
+			secretData[k] = val
+		}
+		secretData[remoteRef.GetProperty()] = string(value)
+	}
+	// avoid creating a new secret version if nothing changed
+	if current != nil && reflect.DeepEqual(current, secretData) {
+		return nil
+	}
+	// existing secrets are already marked as managed by readManagedSecret
+	if current == nil {
+		if err := v.markManaged(ctx, key); err != nil {
+			return err
+		}
+	}
+	_, err = v.logical.WriteWithContext(ctx, v.buildPath(key), map[string]interface{}{
+		"data": secretData,
+	})
+	if err != nil {
+		return classifyErr(fmt.Errorf(errWriteSecret, err))
+	}
+	return nil
+}
+
+// markManaged adds the managed-by key to the custom_metadata of a secret.
+// Vault replaces the whole custom_metadata on write, so the metadata
+// which is already set, e.g. for a deleted secret, is merged.
+func (v *client) markManaged(ctx context.Context, key string) error {
+	metaPath, err := v.buildMetadataPath(key)
+	if err != nil {
+		return err
+	}
+	customMetadata := map[string]interface{}{}
+	metadata, err := v.logical.ReadWithDataWithContext(ctx, metaPath, nil)
+	if err != nil {
+		return classifyErr(fmt.Errorf(errReadSecret, err))
+	}
+	if metadata != nil {
+		if existing, ok := metadata.Data["custom_metadata"].(map[string]interface{}); ok {
+			for k, val := range existing {
+				customMetadata[k] = val
+			}
+		}
+	}
+	customMetadata[utils.ManagedByKey] = utils.ManagedByValue
+	_, err = v.logical.WriteWithContext(ctx, metaPath, map[string]interface{}{
+		"custom_metadata": customMetadata,
+	})
+	if err != nil {
+		return classifyErr(fmt.Errorf(errWriteSecret, err))
+	}
+	return nil
+}
+
+// DeleteSecret removes a property from a secret created by SetSecret.
+// The secret is deleted with all its versions once no property is left
+// or if no property is given.
+func (v *client) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	if v.store.Version != esv1beta1.VaultKVStoreV2 {
+		return errors.New(errPushUnsupportedKv)
+	}
+	key := remoteRef.GetRemoteKey()
+	current, err := v.readManagedSecret(ctx, key)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	if remoteRef.GetProperty() != "" {
+		if _, ok := current[remoteRef.GetProperty()]; !ok {
+			return nil
+		}
+		delete(current, remoteRef.GetProperty())
+		if len(current) > 0 {
+			_, err = v.logical.WriteWithContext(ctx, v.buildPath(key), map[string]interface{}{
+				"data": current,
+			})
+			if err != nil {
+				return classifyErr(fmt.Errorf(errWriteSecret, err))
+			}
+			return nil
+		}
+	}
+	metaPath, err := v.buildMetadataPath(key)
+	if err != nil {
+		return err
+	}
+	_, err = v.logical.DeleteWithContext(ctx, metaPath)
+	if err != nil {
+		return classifyErr(fmt.Errorf(errDeleteSecret, err))
+	}
+	return nil
+}
+
+// readManagedSecret returns the current data of a secret, or nil if
+// it does not exist. It fails if the secret is not managed by external-secrets.
+func (v *client) readManagedSecret(ctx context.Context, key string) (map[string]interface{}, error) {
+	vaultSecret, err := v.logical.ReadWithDataWithContext(ctx, v.buildPath(key), nil)
+	if err != nil {
+		return nil, classifyErr(fmt.Errorf(errReadSecret, err))
+	}
+	if vaultSecret == nil || vaultSecret.Data["data"] == nil {
+		return nil, nil
+	}
+	secretData, ok := vaultSecret.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errJSONUnmarshall)
+	}
+	metadata, err := v.readSecretMetadata(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if metadata[utils.ManagedByKey] != utils.ManagedByValue {
+		return nil, fmt.Errorf(errNotManaged, key)
+	}
+	return secretData, nil
+}
+
 // GetSecret supports two types:
 // 1. get the full secret as json-encoded value
 //    by leaving the ref.Property empty.
 // 2. get a key from the secret.
 //    Nested values are supported by specifying a gjson expression
 func (v *client) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		if v.store.Version == esv1beta1.VaultKVStoreV1 {
+			return nil, errors.New(errUnsupportedMetadata)
+		}
+		metadata, err := v.readSecretMetadata(ctx, ref.Key)
+		if err != nil {
+			return nil, err
+		}
+		return utils.GetMetadataValue(metadata, ref.Property)
+	}
 	data, err := v.readSecret(ctx, ref.Key, ref.Version)
 	if err != nil {
 		return nil, err
@@ -567,6 +748,41 @@ func getTypedKey(data map[string]interface{}, key string) ([]byte, error) {
 	}
 }
 
+// Renew extends the lease of a token which has been obtained by logging in,
+// so that a client which is kept alive does not have to log in again.
+// Tokens from a TokenSecretRef are managed by the user and are not renewed.
+func (v *client) Renew(ctx context.Context) error {
+	if v.client.Token() == "" || v.store.Auth.TokenSecretRef != nil {
+		return nil
+	}
+	// https://www.vaultproject.io/api-docs/auth/token#lookup-a-token-self
+	resp, err := v.token.LookupSelfWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf(errVaultRenewToken, err)
+	}
+	renewable, err := resp.TokenIsRenewable()
+	if err != nil {
+		return fmt.Errorf(errVaultRenewToken, err)
+	}
+	if !renewable {
+		// e.g. batch tokens, which are used until they are about to expire
+		ttl, err := resp.TokenTTL()
+		if err != nil {
+			return fmt.Errorf(errVaultRenewToken, err)
+		}
+		if ttl != 0 && ttl < minTokenTTL {
+			return fmt.Errorf(errVaultTokenExpiring, ttl)
+		}
+		return nil
+	}
+	// https://www.vaultproject.io/api-docs/auth/token#renew-a-token-self
+	_, err = v.token.RenewSelfWithContext(ctx, 0)
+	if err != nil {
+		return fmt.Errorf(errVaultRenewToken, err)
+	}
+	return nil
+}
+
 func (v *client) Close(ctx context.Context) error {
 	// Revoke the token if we have one set and it wasn't sourced from a TokenSecretRef
 	if v.client.Token() != "" && v.store.Auth.TokenSecretRef == nil {
@@ -585,6 +801,20 @@ func (v *client) Close(ctx context.Context) error {
 	return nil
 }
 
+// classifyErr classifies the error of a Vault request by its HTTP status code.
+// Vault answers requests with an invalid token with 403 as well,
+// these are reported as permission denied.
+func classifyErr(err error) error {
+	if errors.As(err, &esv1beta1.ThrottledError{}) {
+		return err
+	}
+	var respErr *vault.ResponseError
+	if errors.As(err, &respErr) {
+		return esv1beta1.FromHTTPStatus(respErr.StatusCode, err)
+	}
+	return err
+}
+
 func isReferentSpec(prov *esv1beta1.VaultProvider) bool {
 	if prov.Auth.TokenSecretRef != nil && prov.Auth.TokenSecretRef.Namespace == nil {
 		return true
@@ -680,7 +910,7 @@ func (v *client) readSecret(ctx context.Context, path, version string) (map[stri
 	}
 	vaultSecret, err := v.logical.ReadWithDataWithContext(ctx, dataPath, params)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, classifyErr(fmt.Errorf(errReadSecret, err))
 	}
 	if vaultSecret == nil {
 		return nil, errors.New(errNotFound)
@@ -698,16 +928,44 @@ func (v *client) readSecret(ctx context.Context, path, version string) (map[stri
 		if !ok {
 			return nil, errors.New(errJSONUnmarshall)
 		}
+		if metadata, ok := vaultSecret.Data["metadata"].(map[string]interface{}); ok && metadata["version"] != nil {
+			v.setVersion(path, version, fmt.Sprint(metadata["version"]))
+		}
 	}
 
 	return secretData, nil
 }
 
+func versionKey(path, version string) string {
+	return fmt.Sprintf("%s#%s", path, version)
+}
+
+func (v *client) setVersion(path, version, readVersion string) {
+	v.versionsMu.Lock()
+	defer v.versionsMu.Unlock()
+	if v.versions == nil {
+		v.versions = make(map[string]string)
+	}
+	v.versions[versionKey(path, version)] = readVersion
+}
+
+// GetSecretVersion returns the kv v2 metadata version of the value which has
+// been read last for the ref. kv v1 secrets do not have versions.
+func (v *client) GetSecretVersion(_ context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	if ref.MetadataPolicy == esv1beta1.ExternalSecretMetadataPolicyFetch {
+		return "", nil
+	}
+	v.versionsMu.Lock()
+	defer v.versionsMu.Unlock()
+	return v.versions[versionKey(ref.Key, ref.Version)], nil
+}
+
 func (v *client) newConfig() (*vault.Config, error) {
 	cfg := vault.DefaultConfig()
 	cfg.Address = v.store.Server
 	// In a controller-runtime context, we rely on the reconciliation process for retrying
 	cfg.MaxRetries = 0
+	cfg.CheckRetry = checkRetry
 
 	if len(v.store.CABundle) == 0 && v.store.CAProvider == nil {
 		return cfg, nil
@@ -759,6 +1017,19 @@ func (v *client) newConfig() (*vault.Config, error) {
 	return cfg, nil
 }
 
+// checkRetry keeps the Retry-After header of a request which Vault
+// rejected because a rate limit quota has been exceeded,
+// so that the controller knows when to try again.
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, ok := utils.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		if respErr := (&vault.Response{Response: resp}).Error(); ok && respErr != nil {
+			return false, esv1beta1.ThrottledError{Err: respErr, RetryAfter: retryAfter}
+		}
+	}
+	return vault.DefaultRetryPolicy(ctx, resp, err)
+}
+
 func getCertFromSecret(v *client) ([]byte, error) {
 	secretRef := esmeta.SecretKeySelector{
 		Name: v.store.CAProvider.Name,
@@ -16,10 +16,15 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
@@ -29,6 +34,7 @@ import (
 	"k8s.io/utils/pointer"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/provider/vault/fake"
@@ -673,6 +679,62 @@ func TestGetSecret(t *testing.T) {
 				err: errors.New(errNotFound),
 			},
 		},
+		"ReadSecretMetadata": {
+			reason: "Should return the custom metadata of a kv v2 secret",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "secret",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+				vLogical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(map[string]interface{}{
+						"custom_metadata": map[string]interface{}{
+							"owner": "team-a",
+						},
+					}, nil),
+				},
+			},
+			want: want{
+				err: nil,
+				val: []byte(`{"owner":"team-a"}`),
+			},
+		},
+		"ReadSecretMetadataProperty": {
+			reason: "Should return a single custom metadata value of a kv v2 secret",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "secret",
+					Property:       "owner",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+				vLogical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(map[string]interface{}{
+						"custom_metadata": map[string]interface{}{
+							"owner": "team-a",
+						},
+					}, nil),
+				},
+			},
+			want: want{
+				err: nil,
+				val: []byte("team-a"),
+			},
+		},
+		"ReadSecretMetadataKvV1": {
+			reason: "Should fail to fetch metadata with kv v1",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+				data: esv1beta1.ExternalSecretDataRemoteRef{
+					Key:            "secret",
+					MetadataPolicy: esv1beta1.ExternalSecretMetadataPolicyFetch,
+				},
+			},
+			want: want{
+				err: errors.New(errUnsupportedMetadata),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -948,6 +1010,73 @@ func newReadtWithContextFn(secrets map[string]interface{}) func(ctx context.Cont
 		return secret, nil
 	}
 }
+func TestGetSecretVersion(t *testing.T) {
+	kv2 := map[string]interface{}{
+		"data": map[string]interface{}{
+			"access_key": "access_key",
+		},
+		"metadata": map[string]interface{}{
+			"version": json.Number("3"),
+		},
+	}
+	kv1 := map[string]interface{}{
+		"access_key": "access_key",
+	}
+
+	cases := map[string]struct {
+		reason  string
+		store   *esv1beta1.VaultProvider
+		secret  map[string]interface{}
+		read    esv1beta1.ExternalSecretDataRemoteRef
+		ref     esv1beta1.ExternalSecretDataRemoteRef
+		version string
+	}{
+		"ReadVersion": {
+			reason:  "Should return the metadata version of the value which has been read",
+			store:   makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+			secret:  kv2,
+			read:    esv1beta1.ExternalSecretDataRemoteRef{Key: "secret", Property: "access_key"},
+			ref:     esv1beta1.ExternalSecretDataRemoteRef{Key: "secret", Property: "access_key"},
+			version: "3",
+		},
+		"NotRead": {
+			reason: "Should return no version for a secret which has not been read",
+			store:  makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV2).Spec.Provider.Vault,
+			secret: kv2,
+			read:   esv1beta1.ExternalSecretDataRemoteRef{Key: "secret"},
+			ref:    esv1beta1.ExternalSecretDataRemoteRef{Key: "secret", Version: "2"},
+		},
+		"KVv1": {
+			reason: "Should return no version for a kv v1 secret",
+			store:  makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+			secret: kv1,
+			read:   esv1beta1.ExternalSecretDataRemoteRef{Key: "secret"},
+			ref:    esv1beta1.ExternalSecretDataRemoteRef{Key: "secret"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			vStore := &client{
+				logical: &fake.Logical{
+					ReadWithDataWithContextFn: fake.NewReadWithContextFn(tc.secret, nil),
+				},
+				store: tc.store,
+			}
+			if _, err := vStore.GetSecret(context.Background(), tc.read); err != nil {
+				t.Fatalf("\n%s\nvault.GetSecret(...): unexpected error: %v", tc.reason, err)
+			}
+			version, err := vStore.GetSecretVersion(context.Background(), tc.ref)
+			if err != nil {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): unexpected error: %v", tc.reason, err)
+			}
+			if version != tc.version {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): want %q, got %q", tc.reason, tc.version, version)
+			}
+		})
+	}
+}
+
 func TestGetAllSecrets(t *testing.T) {
 	secret1Bytes := []byte("{\"access_key\":\"access_key\",\"access_secret\":\"access_secret\"}")
 	secret2Bytes := []byte("{\"access_key\":\"access_key2\",\"access_secret\":\"access_secret2\"}")
@@ -1172,6 +1301,255 @@ func TestGetAllSecrets(t *testing.T) {
 	}
 }
 
+func TestSetSecret(t *testing.T) {
+	managedMetadata := map[string]interface{}{
+		"custom_metadata": map[string]interface{}{
+			"managed-by": "external-secrets",
+		},
+	}
+	readFn := func(data, metadata map[string]interface{}) fake.ReadWithDataWithContextFn {
+		return func(ctx context.Context, path string, d map[string][]string) (*vault.Secret, error) {
+			if strings.Contains(path, "metadata") {
+				return &vault.Secret{Data: metadata}, nil
+			}
+			if data == nil {
+				return nil, nil
+			}
+			return &vault.Secret{Data: map[string]interface{}{"data": data}}, nil
+		}
+	}
+
+	type args struct {
+		store *esv1beta1.VaultProvider
+		value string
+		ref   esv1alpha1.PushSecretRemoteRef
+		read  fake.ReadWithDataWithContextFn
+	}
+	type want struct {
+		err    error
+		writes map[string]interface{}
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CreateWithProperty": {
+			reason: "Should create a new managed secret",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read:  readFn(nil, nil),
+			},
+			want: want{
+				writes: map[string]interface{}{
+					"secret/metadata/foo": managedMetadata,
+					"secret/data/foo":     map[string]interface{}{"data": map[string]interface{}{"key": "bar"}},
+				},
+			},
+		},
+		"CreateKeepsMetadata": {
+			reason: "Should keep the custom metadata which is already set when creating a secret",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read: readFn(nil, map[string]interface{}{
+					"custom_metadata": map[string]interface{}{"owner": "team-a"},
+				}),
+			},
+			want: want{
+				writes: map[string]interface{}{
+					"secret/metadata/foo": map[string]interface{}{
+						"custom_metadata": map[string]interface{}{
+							"owner":      "team-a",
+							"managed-by": "external-secrets",
+						},
+					},
+					"secret/data/foo": map[string]interface{}{"data": map[string]interface{}{"key": "bar"}},
+				},
+			},
+		},
+		"MergeProperty": {
+			reason: "Should merge the property into a managed secret",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read:  readFn(map[string]interface{}{"other": "x"}, managedMetadata),
+			},
+			want: want{
+				writes: map[string]interface{}{
+					"secret/data/foo": map[string]interface{}{"data": map[string]interface{}{"key": "bar", "other": "x"}},
+				},
+			},
+		},
+		"Unchanged": {
+			reason: "Should not write a new version if nothing changed",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read:  readFn(map[string]interface{}{"key": "bar"}, managedMetadata),
+			},
+			want: want{
+				writes: map[string]interface{}{},
+			},
+		},
+		"WholeSecret": {
+			reason: "Should replace the secret data with the JSON value",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: `{"a":"b"}`,
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"},
+				read:  readFn(map[string]interface{}{"key": "bar"}, managedMetadata),
+			},
+			want: want{
+				writes: map[string]interface{}{
+					"secret/data/foo": map[string]interface{}{"data": map[string]interface{}{"a": "b"}},
+				},
+			},
+		},
+		"NotManaged": {
+			reason: "Should refuse to overwrite secrets not managed by external-secrets",
+			args: args{
+				store: makeValidSecretStore().Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read:  readFn(map[string]interface{}{"key": "old"}, map[string]interface{}{}),
+			},
+			want: want{
+				err:    fmt.Errorf(errNotManaged, "foo"),
+				writes: map[string]interface{}{},
+			},
+		},
+		"KVv1": {
+			reason: "Should refuse to push to kv v1",
+			args: args{
+				store: makeValidSecretStoreWithVersion(esv1beta1.VaultKVStoreV1).Spec.Provider.Vault,
+				value: "bar",
+				ref:   esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+				read:  readFn(nil, nil),
+			},
+			want: want{
+				err:    errors.New(errPushUnsupportedKv),
+				writes: map[string]interface{}{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			writes := map[string]interface{}{}
+			vStore := &client{
+				logical: &fake.Logical{
+					ReadWithDataWithContextFn: tc.args.read,
+					WriteWithContextFn: func(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
+						writes[path] = data
+						return nil, nil
+					},
+				},
+				store: tc.args.store,
+			}
+			err := vStore.SetSecret(context.Background(), []byte(tc.args.value), tc.args.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvault.SetSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.writes, writes); diff != "" {
+				t.Errorf("\n%s\nvault.SetSecret(...): -want writes, +got writes:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	managedMetadata := map[string]interface{}{
+		"custom_metadata": map[string]interface{}{
+			"managed-by": "external-secrets",
+		},
+	}
+	readFn := func(data map[string]interface{}) fake.ReadWithDataWithContextFn {
+		return func(ctx context.Context, path string, d map[string][]string) (*vault.Secret, error) {
+			if strings.Contains(path, "metadata") {
+				return &vault.Secret{Data: managedMetadata}, nil
+			}
+			if data == nil {
+				return nil, nil
+			}
+			return &vault.Secret{Data: map[string]interface{}{"data": data}}, nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason     string
+		ref        esv1alpha1.PushSecretRemoteRef
+		read       fake.ReadWithDataWithContextFn
+		wantWrites map[string]interface{}
+		wantDelete []string
+	}{
+		"DeleteProperty": {
+			reason: "Should remove the property and keep the secret",
+			ref:    esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+			read:   readFn(map[string]interface{}{"key": "bar", "other": "x"}),
+			wantWrites: map[string]interface{}{
+				"secret/data/foo": map[string]interface{}{"data": map[string]interface{}{"other": "x"}},
+			},
+		},
+		"DeleteLastProperty": {
+			reason:     "Should delete the secret once the last property is removed",
+			ref:        esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo", Property: "key"},
+			read:       readFn(map[string]interface{}{"key": "bar"}),
+			wantWrites: map[string]interface{}{},
+			wantDelete: []string{"secret/metadata/foo"},
+		},
+		"DeleteWholeSecret": {
+			reason:     "Should delete the secret",
+			ref:        esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"},
+			read:       readFn(map[string]interface{}{"key": "bar"}),
+			wantWrites: map[string]interface{}{},
+			wantDelete: []string{"secret/metadata/foo"},
+		},
+		"NotFound": {
+			reason:     "Should not fail if the secret does not exist",
+			ref:        esv1alpha1.PushSecretRemoteRef{RemoteKey: "foo"},
+			read:       readFn(nil),
+			wantWrites: map[string]interface{}{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			writes := map[string]interface{}{}
+			var deletes []string
+			vStore := &client{
+				logical: &fake.Logical{
+					ReadWithDataWithContextFn: tc.read,
+					WriteWithContextFn: func(ctx context.Context, path string, data map[string]interface{}) (*vault.Secret, error) {
+						writes[path] = data
+						return nil, nil
+					},
+					DeleteWithContextFn: func(ctx context.Context, path string) (*vault.Secret, error) {
+						deletes = append(deletes, path)
+						return nil, nil
+					},
+				},
+				store: makeValidSecretStore().Spec.Provider.Vault,
+			}
+			err := vStore.DeleteSecret(context.Background(), tc.ref)
+			if err != nil {
+				t.Errorf("\n%s\nvault.DeleteSecret(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.wantWrites, writes); diff != "" {
+				t.Errorf("\n%s\nvault.DeleteSecret(...): -want writes, +got writes:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantDelete, deletes); diff != "" {
+				t.Errorf("\n%s\nvault.DeleteSecret(...): -want deletes, +got deletes:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetSecretPath(t *testing.T) {
 	storeV2 := makeValidSecretStore()
 	storeV2NoPath := storeV2.DeepCopy()
@@ -1261,6 +1639,139 @@ func TestGetSecretPath(t *testing.T) {
 	}
 }
 
+func TestClassifyErr(t *testing.T) {
+	respErr := func(code int) error {
+		return fmt.Errorf(errReadSecret, &vault.ResponseError{StatusCode: code})
+	}
+	tbl := []struct {
+		err  error
+		want interface{}
+	}{
+		{err: respErr(403), want: &esv1beta1.PermissionDeniedError{}},
+		{err: respErr(429), want: &esv1beta1.ThrottledError{}},
+		{err: respErr(503), want: &esv1beta1.TransientError{}},
+	}
+	for _, tc := range tbl {
+		if err := classifyErr(tc.err); !errors.As(err, tc.want) {
+			t.Errorf("%v: unexpected error type %T", tc.err, err)
+		}
+	}
+	errBoom := errors.New("boom")
+	if err := classifyErr(errBoom); err != errBoom {
+		t.Errorf("unclassified error must be returned as is, got %T", err)
+	}
+}
+
+func TestCheckRetry(t *testing.T) {
+	makeResp := func(code int, retryAfter string) *http.Response {
+		return &http.Response{
+			StatusCode: code,
+			Header:     http.Header{"Retry-After": []string{retryAfter}},
+			Body:       io.NopCloser(strings.NewReader(`{"errors":["rate limit quota exceeded"]}`)),
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/v1/secret/data/foo"}},
+		}
+	}
+	retry, err := checkRetry(context.Background(), makeResp(http.StatusTooManyRequests, "30"), nil)
+	var throttled esv1beta1.ThrottledError
+	if retry || !errors.As(err, &throttled) || throttled.RetryAfter != 30*time.Second {
+		t.Errorf("unexpected result: %v, %v", retry, err)
+	}
+	if err := classifyErr(fmt.Errorf(errReadSecret, err)); !errors.As(err, &throttled) || throttled.RetryAfter != 30*time.Second {
+		t.Errorf("classifyErr must keep the retry after, got %v", err)
+	}
+	if retry, err := checkRetry(context.Background(), makeResp(http.StatusTooManyRequests, ""), nil); err != nil {
+		t.Errorf("unexpected result without retry after: %v, %v", retry, err)
+	}
+	if retry, err := checkRetry(context.Background(), makeResp(http.StatusOK, "30"), nil); retry || err != nil {
+		t.Errorf("unexpected result for success: %v, %v", retry, err)
+	}
+}
+
+func TestRenew(t *testing.T) {
+	lookup := func(renewable bool, ttl string) fake.LookupSelfWithContextFn {
+		return func(ctx context.Context) (*vault.Secret, error) {
+			return &vault.Secret{Data: map[string]interface{}{
+				"renewable": renewable,
+				"ttl":       json.Number(ttl),
+			}}, nil
+		}
+	}
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason    string
+		tokenRef  bool
+		lookup    fake.LookupSelfWithContextFn
+		renewErr  error
+		wantRenew bool
+		wantErr   bool
+	}{
+		"RenewableToken": {
+			reason:    "Should renew a renewable token",
+			lookup:    lookup(true, "60"),
+			wantRenew: true,
+		},
+		"RenewFailed": {
+			reason:    "Should return an error if the token can not be renewed",
+			lookup:    lookup(true, "60"),
+			renewErr:  errBoom,
+			wantRenew: true,
+			wantErr:   true,
+		},
+		"BatchToken": {
+			reason: "Should keep using a token which is not renewable",
+			lookup: lookup(false, "3600"),
+		},
+		"ExpiringBatchToken": {
+			reason:  "Should return an error if a token which is not renewable is about to expire",
+			lookup:  lookup(false, "10"),
+			wantErr: true,
+		},
+		"LookupFailed": {
+			reason:  "Should return an error if the token can not be looked up",
+			lookup:  func(ctx context.Context) (*vault.Secret, error) { return nil, errBoom },
+			wantErr: true,
+		},
+		"TokenSecretRef": {
+			reason:   "Should not renew a token from a TokenSecretRef",
+			tokenRef: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var renewed bool
+			store := makeValidSecretStore().Spec.Provider.Vault
+			if tc.tokenRef {
+				store.Auth = esv1beta1.VaultAuth{TokenSecretRef: &esmeta.SecretKeySelector{Name: tokenSecretName, Key: "token"}}
+			}
+			cl := fake.VaultClient{
+				MockToken: fake.NewTokenFn("token"),
+			}
+			vStore := &client{
+				store: store,
+				client: VClient{
+					token: cl.Token,
+				},
+				token: fake.Token{
+					LookupSelfWithContextFn: tc.lookup,
+					RenewSelfWithContextFn: func(ctx context.Context, increment int) (*vault.Secret, error) {
+						renewed = true
+						return nil, tc.renewErr
+					},
+				},
+			}
+			err := vStore.Renew(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nvault.Renew(...): unexpected error: %v", tc.reason, err)
+			}
+			if renewed != tc.wantRenew {
+				t.Errorf("\n%s\nvault.Renew(...): want renew %t, got %t", tc.reason, tc.wantRenew, renewed)
+			}
+		})
+	}
+}
+
 func TestValidateStore(t *testing.T) {
 	type args struct {
 		auth esv1beta1.VaultAuth
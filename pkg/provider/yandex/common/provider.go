@@ -48,8 +48,11 @@ type YandexCloudProvider struct {
 
 	secretGetteMap       map[string]SecretGetter // apiEndpoint -> SecretGetter
 	secretGetterMapMutex sync.Mutex
-	iamTokenMap          map[iamTokenKey]*IamToken
-	iamTokenMapMutex     sync.Mutex
+	// iamTokenMap is shared by all stores using the same authorized key,
+	// which is why it is not folded into the client pool. Pooled clients
+	// renew their token from it.
+	iamTokenMap      map[iamTokenKey]*IamToken
+	iamTokenMapMutex sync.Mutex
 }
 
 type iamTokenKey struct {
@@ -177,7 +180,16 @@ func (p *YandexCloudProvider) NewClient(ctx context.Context, store esv1beta1.Gen
 		return nil, fmt.Errorf("failed to create IAM token: %w", err)
 	}
 
-	return &yandexCloudSecretsClient{secretGetter, iamToken.Token}, nil
+	return &yandexCloudSecretsClient{
+		secretGetter: secretGetter,
+		provider:     p,
+		input: iamTokenInput{
+			apiEndpoint:   input.APIEndpoint,
+			authorizedKey: &authorizedKey,
+			caCertificate: caCertificateData,
+		},
+		iamToken: iamToken.Token,
+	}, nil
 }
 
 func (p *YandexCloudProvider) getOrCreateSecretGetter(ctx context.Context, apiEndpoint string, authorizedKey *iamkey.Key, caCertificate []byte) (SecretGetter, error) {
@@ -15,18 +15,56 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+
+	"github.com/yandex-cloud/go-sdk/iamkey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 )
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1beta1.SecretsClient = &yandexCloudSecretsClient{}
+var _ esv1beta1.SecretsClientRenewer = &yandexCloudSecretsClient{}
 
 // Implementation of v1beta1.SecretsClient.
 type yandexCloudSecretsClient struct {
 	secretGetter SecretGetter
-	iamToken     string
+	provider     *YandexCloudProvider
+	input        iamTokenInput
+
+	mu       sync.Mutex
+	iamToken string
+}
+
+// iamTokenInput holds what is needed to create an IAM token for a client.
+type iamTokenInput struct {
+	apiEndpoint   string
+	authorizedKey *iamkey.Key
+	caCertificate []byte
+}
+
+// Renew replaces the IAM token of a pooled client with a token from the
+// IAM token cache of the provider, which creates a new token before the
+// current one expires.
+func (c *yandexCloudSecretsClient) Renew(ctx context.Context) error {
+	iamToken, err := c.provider.getOrCreateIamToken(ctx, c.input.apiEndpoint, c.input.authorizedKey, c.input.caCertificate)
+	if err != nil {
+		return fmt.Errorf("failed to create IAM token: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.iamToken = iamToken.Token
+	return nil
+}
+
+func (c *yandexCloudSecretsClient) token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.iamToken
 }
 
 func (c *yandexCloudSecretsClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
@@ -34,12 +72,24 @@ func (c *yandexCloudSecretsClient) GetAllSecrets(ctx context.Context, ref esv1be
 	return nil, fmt.Errorf("GetAllSecrets not supported")
 }
 
+// SetSecret is not implemented for this provider.
+func (c *yandexCloudSecretsClient) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("SetSecret not implemented")
+}
+
+// DeleteSecret is not implemented for this provider.
+func (c *yandexCloudSecretsClient) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	return fmt.Errorf("DeleteSecret not implemented")
+}
+
 func (c *yandexCloudSecretsClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
-	return c.secretGetter.GetSecret(ctx, c.iamToken, ref.Key, ref.Version, ref.Property)
+	data, err := c.secretGetter.GetSecret(ctx, c.token(), ref.Key, ref.Version, ref.Property)
+	return data, classifyErr(err)
 }
 
 func (c *yandexCloudSecretsClient) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
-	return c.secretGetter.GetSecretMap(ctx, c.iamToken, ref.Key, ref.Version)
+	data, err := c.secretGetter.GetSecretMap(ctx, c.token(), ref.Key, ref.Version)
+	return data, classifyErr(err)
 }
 
 func (c *yandexCloudSecretsClient) Close(ctx context.Context) error {
@@ -49,3 +99,24 @@ func (c *yandexCloudSecretsClient) Close(ctx context.Context) error {
 func (c *yandexCloudSecretsClient) Validate() (esv1beta1.ValidationResult, error) {
 	return esv1beta1.ValidationResultReady, nil
 }
+
+// classifyErr classifies an error of the Yandex.Cloud API by its gRPC status code.
+func classifyErr(err error) error {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return err
+	}
+	switch grpcErr.GRPCStatus().Code() {
+	case codes.Unauthenticated:
+		return esv1beta1.AuthError{Err: err}
+	case codes.PermissionDenied:
+		return esv1beta1.PermissionDeniedError{Err: err}
+	case codes.ResourceExhausted:
+		return esv1beta1.ThrottledError{Err: err}
+	case codes.InvalidArgument:
+		return esv1beta1.InvalidRefError{Err: err}
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Aborted:
+		return esv1beta1.TransientError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+type failingSecretGetter struct {
+	err error
+}
+
+func (g *failingSecretGetter) GetSecret(context.Context, string, string, string, string) ([]byte, error) {
+	return nil, g.err
+}
+
+func (g *failingSecretGetter) GetSecretMap(context.Context, string, string, string) (map[string][]byte, error) {
+	return nil, g.err
+}
+
+func TestSecretsClientClassifiesErrors(t *testing.T) {
+	tbl := []struct {
+		code codes.Code
+		want interface{}
+	}{
+		{code: codes.Unauthenticated, want: &esv1beta1.AuthError{}},
+		{code: codes.PermissionDenied, want: &esv1beta1.PermissionDeniedError{}},
+		{code: codes.ResourceExhausted, want: &esv1beta1.ThrottledError{}},
+		{code: codes.Unavailable, want: &esv1beta1.TransientError{}},
+	}
+	for _, row := range tbl {
+		err := fmt.Errorf("unable to request secret payload to get secret: %w", status.Error(row.code, "boom"))
+		c := &yandexCloudSecretsClient{secretGetter: &failingSecretGetter{err: err}}
+		if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "key"}); !errors.As(err, row.want) {
+			t.Errorf("%s: unexpected error type %T", row.code, err)
+		}
+		if _, err := c.GetSecretMap(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "key"}); !errors.As(err, row.want) {
+			t.Errorf("%s: unexpected error type %T", row.code, err)
+		}
+	}
+	errBoom := errors.New("boom")
+	c := &yandexCloudSecretsClient{secretGetter: &failingSecretGetter{err: errBoom}}
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "key"}); err != errBoom {
+		t.Errorf("unclassified error must be returned as is, got %T", err)
+	}
+}
@@ -447,6 +447,41 @@ func TestGetSecretWithIamTokenExpiration(t *testing.T) {
 	tassert.Nil(t, err)
 }
 
+func TestRenewWithIamTokenExpiration(t *testing.T) {
+	ctx := context.Background()
+	namespace := uuid.NewString()
+	authorizedKey := newFakeAuthorizedKey()
+
+	fakeClock := clock.NewFakeClock()
+	tokenExpirationTime := time.Hour
+	fakeLockboxServer := client.NewFakeLockboxServer(fakeClock, tokenExpirationTime)
+	k1, v1 := "k1", "v1"
+	secretID, _ := fakeLockboxServer.CreateSecret(authorizedKey,
+		textEntry(k1, v1),
+	)
+
+	k8sClient := clientfake.NewClientBuilder().Build()
+	const authorizedKeySecretName = "authorizedKeySecretName"
+	const authorizedKeySecretKey = "authorizedKeySecretKey"
+	err := createK8sSecret(ctx, t, k8sClient, namespace, authorizedKeySecretName, authorizedKeySecretKey, toJSON(t, authorizedKey))
+	tassert.Nil(t, err)
+	store := newYandexLockboxSecretStore("", namespace, authorizedKeySecretName, authorizedKeySecretKey)
+
+	provider := newLockboxProvider(fakeClock, fakeLockboxServer)
+
+	secretsClient, err := provider.NewClient(ctx, store, k8sClient, namespace)
+	tassert.Nil(t, err)
+
+	fakeClock.AddDuration(2 * tokenExpirationTime)
+
+	renewer, ok := secretsClient.(esv1beta1.SecretsClientRenewer)
+	tassert.True(t, ok)
+	tassert.Nil(t, renewer.Renew(ctx))
+	data, err := secretsClient.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: secretID, Property: k1})
+	tassert.Equal(t, v1, string(data))
+	tassert.Nil(t, err)
+}
+
 func TestGetSecretWithIamTokenCleanup(t *testing.T) {
 	ctx := context.Background()
 	namespace := uuid.NewString()
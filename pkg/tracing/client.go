@@ -0,0 +1,103 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+// WrapProvider creates a span for every client created by the provider.
+// The clients themselves are not wrapped, see Wrap.
+func WrapProvider(p esv1beta1.Provider) esv1beta1.Provider {
+	return &provider{Provider: p}
+}
+
+type provider struct {
+	esv1beta1.Provider
+}
+
+func (p *provider) NewClient(ctx context.Context, store esv1beta1.GenericStore, kube client.Client, namespace string) (esv1beta1.SecretsClient, error) {
+	ctx, span := Start(ctx, "NewClient", StoreAttributes(store)...)
+	secretClient, err := p.Provider.NewClient(ctx, store, kube, namespace)
+	End(span, err)
+	return secretClient, err
+}
+
+// Wrap creates a span for every request of the client to the provider of the store.
+func Wrap(store esv1beta1.GenericStore, secretClient esv1beta1.SecretsClient) esv1beta1.SecretsClient {
+	return &secretsClient{
+		SecretsClient: secretClient,
+		attrs:         StoreAttributes(store),
+	}
+}
+
+type secretsClient struct {
+	esv1beta1.SecretsClient
+	attrs []attribute.KeyValue
+}
+
+func (c *secretsClient) start(ctx context.Context, method, key string) (context.Context, trace.Span) {
+	attrs := append([]attribute.KeyValue{}, c.attrs...)
+	if key != "" {
+		attrs = append(attrs, RemoteKeyKey.String(key))
+	}
+	return Start(ctx, method, attrs...)
+}
+
+func (c *secretsClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	ctx, span := c.start(ctx, "GetSecret", ref.Key)
+	secret, err := c.SecretsClient.GetSecret(ctx, ref)
+	End(span, err)
+	return secret, err
+}
+
+func (c *secretsClient) GetSecretMap(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	ctx, span := c.start(ctx, "GetSecretMap", ref.Key)
+	secretMap, err := c.SecretsClient.GetSecretMap(ctx, ref)
+	End(span, err)
+	return secretMap, err
+}
+
+// GetSecretVersion does not call the provider and is not traced.
+func (c *secretsClient) GetSecretVersion(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) (string, error) {
+	return esv1beta1.GetSecretVersion(ctx, c.SecretsClient, ref)
+}
+
+func (c *secretsClient) GetAllSecrets(ctx context.Context, ref esv1beta1.ExternalSecretFind) (map[string][]byte, error) {
+	ctx, span := c.start(ctx, "GetAllSecrets", "")
+	secretMap, err := c.SecretsClient.GetAllSecrets(ctx, ref)
+	End(span, err)
+	return secretMap, err
+}
+
+func (c *secretsClient) SetSecret(ctx context.Context, value []byte, remoteRef esv1beta1.PushRemoteRef) error {
+	ctx, span := c.start(ctx, "SetSecret", remoteRef.GetRemoteKey())
+	err := c.SecretsClient.SetSecret(ctx, value, remoteRef)
+	End(span, err)
+	return err
+}
+
+func (c *secretsClient) DeleteSecret(ctx context.Context, remoteRef esv1beta1.PushRemoteRef) error {
+	ctx, span := c.start(ctx, "DeleteSecret", remoteRef.GetRemoteKey())
+	err := c.SecretsClient.DeleteSecret(ctx, remoteRef)
+	End(span, err)
+	return err
+}
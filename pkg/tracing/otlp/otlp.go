@@ -0,0 +1,83 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlp exports the spans created by the tracing package
+// to an OTLP gRPC receiver.
+package otlp
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// shutdownTimeout bounds the time spent exporting the remaining spans on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// Options configures the export of spans.
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver.
+	Endpoint string
+	// Insecure disables TLS towards the receiver.
+	Insecure bool
+	// SampleRatio is the fraction of traces which are sampled,
+	// unless the parent span has been sampled.
+	SampleRatio float64
+	// ServiceName is reported as the service.name of the resource.
+	ServiceName string
+}
+
+// Provider exports spans until it is stopped.
+type Provider struct {
+	*sdktrace.TracerProvider
+}
+
+// Setup registers a global tracer provider which exports spans to the receiver.
+func Setup(ctx context.Context, opts Options) (*Provider, error) {
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(opts.ServiceName))),
+	)
+	otel.SetTracerProvider(tp)
+	return &Provider{TracerProvider: tp}, nil
+}
+
+// Start exports spans until the context is done, then the remaining
+// spans are flushed. It implements manager.Runnable.
+func (p *Provider) Start(ctx context.Context) error {
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return p.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable,
+// spans are exported whether or not this instance is the leader.
+func (p *Provider) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing creates OpenTelemetry spans for reconciles and provider calls.
+// Spans are only exported once a tracer provider has been set up, see package otlp.
+// Spans carry the names of resources and remote keys but never secret values.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const tracerName = "github.com/external-secrets/external-secrets"
+
+const (
+	ExternalSecretNameKey      = attribute.Key("externalsecret.name")
+	ExternalSecretNamespaceKey = attribute.Key("externalsecret.namespace")
+	StoreKindKey               = attribute.Key("store.kind")
+	StoreNameKey               = attribute.Key("store.name")
+	StoreNamespaceKey          = attribute.Key("store.namespace")
+	StoreProviderKey           = attribute.Key("store.provider")
+	RemoteKeyKey               = attribute.Key("remote.key")
+	TargetKindKey              = attribute.Key("target.kind")
+	TargetNameKey              = attribute.Key("target.name")
+)
+
+// Start starts a span as a child of the span in the context.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ExternalSecretAttributes identifies an ExternalSecret.
+func ExternalSecretAttributes(name, namespace string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		ExternalSecretNameKey.String(name),
+		ExternalSecretNamespaceKey.String(namespace),
+	}
+}
+
+// StoreAttributes identifies a store and its provider.
+func StoreAttributes(store esv1beta1.GenericStore) []attribute.KeyValue {
+	kind := esv1beta1.SecretStoreKind
+	if _, ok := store.(*esv1beta1.ClusterSecretStore); ok {
+		kind = esv1beta1.ClusterSecretStoreKind
+	}
+	// the provider has already been looked up by name, so this does not fail
+	provider, _ := esv1beta1.GetProviderName(store)
+	return []attribute.KeyValue{
+		StoreKindKey.String(kind),
+		StoreNameKey.String(store.GetName()),
+		StoreNamespaceKey.String(store.GetNamespace()),
+		StoreProviderKey.String(provider),
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+)
+
+const secretValue = "s3cr3t"
+
+type fakeClient struct {
+	esv1beta1.SecretsClient
+	err error
+}
+
+func (c *fakeClient) GetSecret(ctx context.Context, ref esv1beta1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	return []byte(secretValue), c.err
+}
+
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+	})
+	return recorder
+}
+
+func makeStore() *esv1beta1.SecretStore {
+	return &esv1beta1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "store",
+			Namespace: "default",
+		},
+		Spec: esv1beta1.SecretStoreSpec{
+			Provider: &esv1beta1.SecretStoreProvider{
+				Fake: &esv1beta1.FakeProvider{},
+			},
+		},
+	}
+}
+
+func TestWrap(t *testing.T) {
+	recorder := record(t)
+	ctx, parent := Start(context.Background(), "Reconcile", ExternalSecretAttributes("es", "default")...)
+	c := Wrap(makeStore(), &fakeClient{})
+	if _, err := c.GetSecret(ctx, esv1beta1.ExternalSecretDataRemoteRef{Key: "db/password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("want 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GetSecret" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("want GetSecret as child of Reconcile, got %s", span.Name())
+	}
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+		if strings.Contains(kv.Value.Emit(), secretValue) {
+			t.Errorf("span must not contain the secret value, got %s=%s", kv.Key, kv.Value.Emit())
+		}
+	}
+	want := map[string]string{
+		"store.kind":      esv1beta1.SecretStoreKind,
+		"store.name":      "store",
+		"store.namespace": "default",
+		"store.provider":  "fake",
+		"remote.key":      "db/password",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("want attribute %s=%s, got %q", k, v, attrs[k])
+		}
+	}
+}
+
+func TestWrapRecordsError(t *testing.T) {
+	recorder := record(t)
+	c := Wrap(makeStore(), &fakeClient{err: errors.New("access denied")})
+	if _, err := c.GetSecret(context.Background(), esv1beta1.ExternalSecretDataRemoteRef{Key: "db/password"}); err == nil {
+		t.Fatalf("want error")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error || spans[0].Status().Description != "access denied" {
+		t.Errorf("want span with error status, got %+v", spans)
+	}
+}
@@ -19,19 +19,29 @@ import (
 	// nolint:gosec
 	"crypto/md5"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	tpl "text/template"
 	"time"
 	"unicode"
 
 	esv1beta1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	template "github.com/external-secrets/external-secrets/pkg/template/v2"
 )
 
+// ErrKeyCollision is returned if different secret keys end up with the same name.
+var ErrKeyCollision = errors.New("secret key collision")
+
 // MergeByteMap merges map of byte slices.
 func MergeByteMap(dst, src map[string][]byte) map[string][]byte {
 	for k, v := range src {
@@ -98,6 +108,79 @@ func ConvertKeys(strategy esv1beta1.ExternalSecretConversionStrategy, in map[str
 	return out, nil
 }
 
+// RewriteMap applies the rewrite operations in order to all keys of the secret map.
+// It fails if different keys are rewritten to the same key.
+func RewriteMap(operations []esv1beta1.ExternalSecretRewrite, in map[string][]byte) (map[string][]byte, error) {
+	if len(operations) == 0 {
+		return in, nil
+	}
+	// origin tracks the original key of every rewritten key
+	// so that collisions can be reported with the provider keys.
+	origin := make(map[string]string, len(in))
+	for k := range in {
+		origin[k] = k
+	}
+	for i, op := range operations {
+		rewriteFn, err := rewriteFunc(op)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite[%d]: %w", i, err)
+		}
+		next := make(map[string]string, len(origin))
+		for _, k := range sortedKeys(origin) {
+			newKey, err := rewriteFn(k)
+			if err != nil {
+				return nil, fmt.Errorf("unable to rewrite key %q with rewrite[%d]: %w", origin[k], i, err)
+			}
+			if newKey == "" {
+				return nil, fmt.Errorf("rewrite[%d] of key %q resulted in an empty key", i, origin[k])
+			}
+			if prev, exists := next[newKey]; exists {
+				return nil, fmt.Errorf("%w: keys %q and %q are both rewritten to %q", ErrKeyCollision, prev, origin[k], newKey)
+			}
+			next[newKey] = origin[k]
+		}
+		origin = next
+	}
+	out := make(map[string][]byte, len(in))
+	for k, orig := range origin {
+		out[k] = in[orig]
+	}
+	return out, nil
+}
+
+func rewriteFunc(op esv1beta1.ExternalSecretRewrite) (func(string) (string, error), error) {
+	switch {
+	case op.Regexp != nil:
+		re, err := regexp.Compile(op.Regexp.Source)
+		if err != nil {
+			return nil, err
+		}
+		return func(key string) (string, error) {
+			return re.ReplaceAllString(key, op.Regexp.Target), nil
+		}, nil
+	case op.Transform != nil:
+		t, err := tpl.New("rewrite").Funcs(template.FuncMap()).Parse(op.Transform.Template)
+		if err != nil {
+			return nil, err
+		}
+		return func(key string) (string, error) {
+			var buf strings.Builder
+			err := t.Execute(&buf, map[string]string{"value": key})
+			return buf.String(), err
+		}, nil
+	}
+	return nil, errors.New("one of regexp or transform must be set")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func convert(strategy esv1beta1.ExternalSecretConversionStrategy, str string) string {
 	rs := []rune(str)
 	newName := make([]string, len(rs))
@@ -146,6 +229,55 @@ func ObjectHash(object interface{}) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(textualVersion)))
 }
 
+const (
+	// ManagedByKey is the tag/label key used to mark provider secrets
+	// that have been written by a PushSecret.
+	ManagedByKey = "managed-by"
+	// ManagedByValue is the value of the ManagedByKey tag/label.
+	ManagedByValue = "external-secrets"
+)
+
+// GetMetadataValue returns provider metadata (tags or labels) for remoteRef.metadataPolicy=Fetch.
+// Without property all metadata is returned as a JSON object, otherwise the value of that key.
+func GetMetadataValue(metadata map[string]string, property string) ([]byte, error) {
+	if property == "" {
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		return json.Marshal(metadata)
+	}
+	val, ok := metadata[property]
+	if !ok {
+		return nil, fmt.Errorf("metadata key %q does not exist", property)
+	}
+	return []byte(val), nil
+}
+
+// SetJSONProperty sets property in the JSON object doc to value.
+// An empty doc is treated as an empty object.
+func SetJSONProperty(doc []byte, property string, value []byte) ([]byte, error) {
+	kv := make(map[string]interface{})
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &kv); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal secret: %w", err)
+		}
+	}
+	kv[property] = string(value)
+	return json.Marshal(kv)
+}
+
+// DeleteJSONProperty removes property from the JSON object doc.
+// It also reports whether the resulting object is empty.
+func DeleteJSONProperty(doc []byte, property string) ([]byte, bool, error) {
+	kv := make(map[string]interface{})
+	if err := json.Unmarshal(doc, &kv); err != nil {
+		return nil, false, fmt.Errorf("unable to unmarshal secret: %w", err)
+	}
+	delete(kv, property)
+	out, err := json.Marshal(kv)
+	return out, len(kv) == 0, err
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -235,3 +367,21 @@ func NetworkValidate(endpoint string, timeout time.Duration) error {
 	defer conn.Close()
 	return nil
 }
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date. It returns false if the value
+// is empty, can not be parsed or does not lie in the future.
+func ParseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(date)
+	return wait, wait > 0
+}
@@ -15,6 +15,8 @@ limitations under the License.
 package utils
 
 import (
+	"errors"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -232,6 +234,151 @@ func TestConvertKeys(t *testing.T) {
 	}
 }
 
+func TestRewriteMap(t *testing.T) {
+	type args struct {
+		operations []esv1beta1.ExternalSecretRewrite
+		in         map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "no operations",
+			args: args{
+				in: map[string][]byte{
+					"my/path/foo": []byte(`bar`),
+				},
+			},
+			want: map[string][]byte{
+				"my/path/foo": []byte(`bar`),
+			},
+		},
+		{
+			name: "remove path",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "my/path/(.*)", Target: "$1"}},
+				},
+				in: map[string][]byte{
+					"my/path/foo": []byte(`bar`),
+					"other":       []byte(`baz`),
+				},
+			},
+			want: map[string][]byte{
+				"foo":   []byte(`bar`),
+				"other": []byte(`baz`),
+			},
+		},
+		{
+			name: "operations are applied in order",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "my/path/(?P<secret>.*)", Target: "$secret"}},
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "(?U)(?P<role>.*)-(?P<app>.*)-webapp", Target: "$app-$role"}},
+				},
+				in: map[string][]byte{
+					"my/path/reader-db-creds-webapp": []byte(`bar`),
+				},
+			},
+			want: map[string][]byte{
+				"db-creds-reader": []byte(`bar`),
+			},
+		},
+		{
+			name: "transform with template",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Transform: &esv1beta1.ExternalSecretRewriteTransform{Template: "{{ .value | upper }}_KEY"}},
+				},
+				in: map[string][]byte{
+					"foo": []byte(`bar`),
+				},
+			},
+			want: map[string][]byte{
+				"FOO_KEY": []byte(`bar`),
+			},
+		},
+		{
+			name: "error on collision",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "^(a|b)/", Target: ""}},
+				},
+				in: map[string][]byte{
+					"a/foo": []byte(`bar`),
+					"b/foo": []byte(`baz`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on empty key",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: ".*", Target: ""}},
+				},
+				in: map[string][]byte{
+					"foo": []byte(`bar`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on invalid regexp",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{
+					{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "(", Target: ""}},
+				},
+				in: map[string][]byte{
+					"foo": []byte(`bar`),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error on empty operation",
+			args: args{
+				operations: []esv1beta1.ExternalSecretRewrite{{}},
+				in: map[string][]byte{
+					"foo": []byte(`bar`),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteMap(tt.args.operations, tt.args.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RewriteMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RewriteMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteMapCollision(t *testing.T) {
+	_, err := RewriteMap([]esv1beta1.ExternalSecretRewrite{
+		{Regexp: &esv1beta1.ExternalSecretRewriteRegexp{Source: "^(a|b)/", Target: ""}},
+	}, map[string][]byte{
+		"a/foo": []byte(`bar`),
+		"b/foo": []byte(`baz`),
+	})
+	if !errors.Is(err, ErrKeyCollision) {
+		t.Fatalf("RewriteMap() error = %v, want %v", err, ErrKeyCollision)
+	}
+	want := `secret key collision: keys "a/foo" and "b/foo" are both rewritten to "foo"`
+	if err.Error() != want {
+		t.Errorf("RewriteMap() error = %q, want %q", err.Error(), want)
+	}
+}
+
 func TestDecode(t *testing.T) {
 	type args struct {
 		strategy esv1beta1.ExternalSecretDecodingStrategy
@@ -335,3 +482,141 @@ func TestValidate(t *testing.T) {
 		t.Errorf("Connection problem: %v", err)
 	}
 }
+
+func TestGetMetadataValue(t *testing.T) {
+	metadata := map[string]string{
+		"env":  "dev",
+		"team": "platform",
+	}
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		property string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "all metadata",
+			metadata: metadata,
+			want:     `{"env":"dev","team":"platform"}`,
+		},
+		{
+			name:     "no metadata",
+			metadata: nil,
+			want:     `{}`,
+		},
+		{
+			name:     "single key",
+			metadata: metadata,
+			property: "team",
+			want:     "platform",
+		},
+		{
+			name:     "missing key",
+			metadata: metadata,
+			property: "owner",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMetadataValue(tt.metadata, tt.property)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMetadataValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("GetMetadataValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetJSONProperty(t *testing.T) {
+	tbl := []struct {
+		name     string
+		doc      string
+		property string
+		value    string
+		exp      string
+		expErr   string
+	}{
+		{
+			name:     "empty document",
+			property: "foo",
+			value:    "bar",
+			exp:      `{"foo":"bar"}`,
+		},
+		{
+			name:     "merge into existing document",
+			doc:      `{"foo":"bar","num":1}`,
+			property: "baz",
+			value:    "qux",
+			exp:      `{"baz":"qux","foo":"bar","num":1}`,
+		},
+		{
+			name:     "overwrite existing property",
+			doc:      `{"foo":"bar"}`,
+			property: "foo",
+			value:    "baz",
+			exp:      `{"foo":"baz"}`,
+		},
+		{
+			name:     "invalid document",
+			doc:      `not json`,
+			property: "foo",
+			value:    "bar",
+			expErr:   "unable to unmarshal secret",
+		},
+	}
+	for _, row := range tbl {
+		t.Run(row.name, func(t *testing.T) {
+			out, err := SetJSONProperty([]byte(row.doc), row.property, []byte(row.value))
+			if !ErrorContains(err, row.expErr) {
+				t.Errorf("unexpected error: %v, expected: %s", err, row.expErr)
+			}
+			if row.expErr == "" && string(out) != row.exp {
+				t.Errorf("unexpected result: %s, expected: %s", out, row.exp)
+			}
+		})
+	}
+}
+
+func TestDeleteJSONProperty(t *testing.T) {
+	out, empty, err := DeleteJSONProperty([]byte(`{"foo":"bar","baz":"qux"}`), "foo")
+	if err != nil || empty || string(out) != `{"baz":"qux"}` {
+		t.Errorf("unexpected result: %s, %v, %v", out, empty, err)
+	}
+	out, empty, err = DeleteJSONProperty(out, "baz")
+	if err != nil || !empty || string(out) != `{}` {
+		t.Errorf("unexpected result: %s, %v, %v", out, empty, err)
+	}
+	_, _, err = DeleteJSONProperty([]byte(`not json`), "foo")
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tbl := []struct {
+		value string
+		exp   time.Duration
+		expOK bool
+	}{
+		{value: "", expOK: false},
+		{value: "120", exp: 2 * time.Minute, expOK: true},
+		{value: "0", expOK: false},
+		{value: "soon", expOK: false},
+		{value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), expOK: false},
+	}
+	for _, row := range tbl {
+		out, ok := ParseRetryAfter(row.value)
+		if ok != row.expOK || (row.expOK && out != row.exp) {
+			t.Errorf("unexpected result for %q: %s, %v", row.value, out, ok)
+		}
+	}
+	out, ok := ParseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if !ok || out <= 58*time.Minute || out > time.Hour {
+		t.Errorf("unexpected result for date: %s, %v", out, ok)
+	}
+}